@@ -16,7 +16,9 @@ import (
 	"github.com/ai-model-match/backend/internal/app/rolloutStrategy"
 	"github.com/ai-model-match/backend/internal/app/rsEngine"
 	"github.com/ai-model-match/backend/internal/app/useCase"
+	"github.com/ai-model-match/backend/internal/app/useCaseReport"
 	"github.com/ai-model-match/backend/internal/app/useCaseStep"
+	"github.com/ai-model-match/backend/internal/app/webhook"
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_env"
 	"github.com/ai-model-match/backend/internal/pkg/mm_log"
@@ -59,7 +61,7 @@ func main() {
 	// Scheduler
 	scheduler := mm_scheduler.NewScheduler()
 	// PUB-SUB agent
-	pubSubAgent := mm_pubsub.NewPubSubAgent(dbConnection, scheduler, envs.PubSubPersistEventsOnDb, envs.PubSubPersistEventsRetentionDays, envs.PubSubSyncMode)
+	pubSubAgent := mm_pubsub.NewPubSubAgent(dbConnection, scheduler, envs.PubSubPersistEventsOnDb, envs.PubSubPersistEventsRetentionDays, envs.PubSubSyncMode, envs.PubSubDistributedMode, envs.PubSubMaxDeliveryAttempts)
 
 	// Init modules
 	r := gin.New()
@@ -72,6 +74,7 @@ func main() {
 	auth.Init(envs, dbConnection, scheduler, v1Api)
 	useCase.Init(envs, dbConnection, pubSubAgent, v1Api)
 	useCaseStep.Init(envs, dbConnection, pubSubAgent, v1Api)
+	useCaseReport.Init(envs, dbConnection, v1Api)
 	flow.Init(envs, dbConnection, pubSubAgent, v1Api)
 	flowStep.Init(envs, dbConnection, pubSubAgent, v1Api)
 	flowStatistics.Init(envs, dbConnection, pubSubAgent, v1Api)
@@ -80,6 +83,7 @@ func main() {
 	picker.Init(envs, dbConnection, pubSubAgent, scheduler, v1Api)
 	feedback.Init(envs, dbConnection, pubSubAgent, v1Api)
 	rsEngine.Init(envs, dbConnection, pubSubAgent, scheduler)
+	webhook.Init(envs, dbConnection, pubSubAgent, scheduler, v1Api)
 
 	// Create CLI app
 	app := cli.NewApp()
@@ -107,6 +111,74 @@ func main() {
 				},
 			},
 		},
+		{
+			Name: "rollout-backtest",
+			Action: func(c *cli.Context) error {
+				return commands.RolloutBacktestCommand(c, dbConnection)
+			},
+			Usage: "Replay recorded sessions and feedback of a Use Case through a candidate rollout strategy configuration",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "use-case-id",
+					Usage:    "ID of the Use Case to backtest",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "config-file",
+					Usage:    "Path to a JSON file with the rollout strategy configuration, same format used by the API",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "start-from",
+					Usage:    "Optional ISO 8601 date to start replay from",
+					Required: false,
+				},
+				&cli.StringFlag{
+					Name:     "end-at",
+					Usage:    "Optional ISO 8601 date to end replay at",
+					Required: false,
+				},
+				&cli.StringFlag{
+					Name:     "format",
+					Usage:    "Optional report format, json (default) or csv",
+					Required: false,
+				},
+				&cli.StringFlag{
+					Name:     "output",
+					Usage:    "Optional path of the report file, printed to standard output if not set",
+					Required: false,
+				},
+				&cli.Uint64Flag{
+					Name:     "seed",
+					Usage:    "Optional seed to make resampled feedback reproducible",
+					Required: false,
+				},
+			},
+		},
+		{
+			Name: "statistics-recompute",
+			Action: func(c *cli.Context) error {
+				return commands.StatisticsRecomputeCommand(c, dbConnection)
+			},
+			Usage: "Rebuild Flow and Flow Step statistics of a Use Case from recorded requests, feedback and session outcomes",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "use-case-id",
+					Usage:    "ID of the Use Case to recompute",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "since",
+					Usage:    "Optional ISO 8601 date to recompute from, required if the start of the current rollout is not recorded",
+					Required: false,
+				},
+				&cli.BoolFlag{
+					Name:     "dry-run",
+					Usage:    "Optional flag to only print the differences, without updating statistics",
+					Required: false,
+				},
+			},
+		},
 	}
 	// Start the CLI
 	err := app.Run(os.Args)
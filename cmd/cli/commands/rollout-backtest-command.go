@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/app/rolloutStrategy"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	backtestFormatJson = "json"
+	backtestFormatCsv  = "csv"
+)
+
+type backtestFlowModel struct {
+	ID              uuid.UUID `gorm:"column:id"`
+	CurrentServePct *float64  `gorm:"column:current_pct"`
+}
+
+type backtestSessionModel struct {
+	CorrelationID uuid.UUID `gorm:"column:correlation_id"`
+	FlowID        uuid.UUID `gorm:"column:flow_id"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+type backtestFeedbackModel struct {
+	CorrelationID uuid.UUID `gorm:"column:correlation_id"`
+	FlowID        uuid.UUID `gorm:"column:flow_id"`
+	Score         float64   `gorm:"column:score"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+/*
+RolloutBacktestCommand replays the recorded sessions and feedback of a Use Case through a candidate
+Rollout Strategy configuration, and reports the projected traffic allocation, the time to convergence
+and the winning Flow
+*/
+func RolloutBacktestCommand(c *cli.Context, tx *gorm.DB) error {
+	useCaseIDParam := c.String("use-case-id")
+	configFile := c.String("config-file")
+	startFrom := c.String("start-from")
+	endAt := c.String("end-at")
+	format := c.String("format")
+	output := c.String("output")
+	seed := c.Uint64("seed")
+
+	// Validate use-case-id
+	useCaseID, err := uuid.Parse(useCaseIDParam)
+	if err != nil {
+		return errors.New("use-case-id must be a valid UUID")
+	}
+	// Validate format
+	if format == "" {
+		format = backtestFormatJson
+	}
+	if format != backtestFormatJson && format != backtestFormatCsv {
+		return errors.New("format must be one of json, csv")
+	}
+	// Validate start-from and end-at if set
+	var startFromTime *time.Time
+	if startFrom != "" {
+		if fromTime, err := time.Parse(time.RFC3339, startFrom); err != nil {
+			return errors.New("start-from must be a valid ISO 8601 date, e.g., 2025-08-26T15:04:05Z")
+		} else {
+			startFromTime = &fromTime
+		}
+	}
+	var endAtTime *time.Time
+	if endAt != "" {
+		if toTime, err := time.Parse(time.RFC3339, endAt); err != nil {
+			return errors.New("end-at must be a valid ISO 8601 date, e.g., 2025-08-26T15:04:05Z")
+		} else {
+			endAtTime = &toTime
+		}
+	}
+	// Read the candidate configuration
+	config, err := readBacktestConfiguration(configFile)
+	if err != nil {
+		return err
+	}
+	// Load recorded data
+	var flowModels []backtestFlowModel
+	if err := tx.Table("mm_flow").Where("use_case_id = ?", useCaseID).Where("active IS TRUE").Order("created_at ASC").Find(&flowModels).Error; err != nil {
+		return err
+	}
+	if len(flowModels) == 0 {
+		return errors.New("use case has no active flows")
+	}
+	var sessionModels []backtestSessionModel
+	sessionQuery := tx.Table("mm_picker_request").Where("use_case_id = ?", useCaseID).Where("is_first_correlation IS TRUE")
+	feedbackQuery := tx.Table("mm_feedback").Where("use_case_id = ?", useCaseID).Where("score IS NOT NULL")
+	if startFromTime != nil {
+		sessionQuery = sessionQuery.Where("created_at >= ?", startFromTime)
+		feedbackQuery = feedbackQuery.Where("created_at >= ?", startFromTime)
+	}
+	if endAtTime != nil {
+		sessionQuery = sessionQuery.Where("created_at < ?", endAtTime)
+	}
+	if err := sessionQuery.Order("created_at ASC").Find(&sessionModels).Error; err != nil {
+		return err
+	}
+	var feedbackModels []backtestFeedbackModel
+	if err := feedbackQuery.Order("created_at ASC").Find(&feedbackModels).Error; err != nil {
+		return err
+	}
+	// Execute the backtest
+	flows := make([]mm_rsengine.Flow, len(flowModels))
+	for i, model := range flowModels {
+		flows[i] = mm_rsengine.Flow{ID: model.ID}
+		if model.CurrentServePct != nil {
+			flows[i].CurrentServePct = *model.CurrentServePct
+		}
+	}
+	sessions := make([]mm_rsengine.BacktestSession, len(sessionModels))
+	for i, model := range sessionModels {
+		sessions[i] = mm_rsengine.BacktestSession{
+			CorrelationID: model.CorrelationID,
+			FlowID:        model.FlowID,
+			StartedAt:     model.CreatedAt,
+		}
+	}
+	feedback := make([]mm_rsengine.BacktestFeedback, len(feedbackModels))
+	for i, model := range feedbackModels {
+		feedback[i] = mm_rsengine.BacktestFeedback(model)
+	}
+	result := mm_rsengine.Backtest(config, flows, sessions, feedback, seed)
+	zap.L().Info("Rollout backtest completed",
+		zap.String("Use Case ID", useCaseID.String()),
+		zap.Int64("sessions", result.TotSessions),
+		zap.Int64("feedback", result.TotFeedback),
+		zap.String("final state", string(result.FinalState)),
+		zap.Int64p("convergence minute", result.ConvergenceMinute),
+		zap.Stringp("winner flow ID", mm_utils.GetOptionalStringFromUUID(result.WinnerFlowID)),
+		zap.String("service", "cli"),
+	)
+	// Emit the report
+	writer := io.Writer(os.Stdout)
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	if format == backtestFormatCsv {
+		return writeBacktestCsv(writer, result)
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+/*
+Read the Rollout Strategy configuration from a JSON file, with the same format used by the API,
+and validate it with the same rules of the API. The scheduled start can be in the past, as the
+configuration is replayed on recorded traffic.
+*/
+func readBacktestConfiguration(configFile string) (mm_pubsub.RSConfiguration, error) {
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return mm_pubsub.RSConfiguration{}, err
+	}
+	config, err := rolloutStrategy.ParseConfiguration(content, true)
+	if err != nil {
+		return config, fmt.Errorf("config-file must contain a valid rollout strategy configuration: %w", err)
+	}
+	return config, nil
+}
+
+/*
+Write one row per Flow, with the projected allocation and the outcome of the backtest
+*/
+func writeBacktestCsv(writer io.Writer, result mm_rsengine.BacktestResult) error {
+	w := csv.NewWriter(writer)
+	if err := w.Write([]string{
+		"flow_id",
+		"recorded_sessions",
+		"projected_sessions",
+		"projected_traffic_pct",
+		"projected_feedback",
+		"projected_avg_score",
+		"final_serve_pct",
+		"winner",
+		"final_state",
+		"convergence_minute",
+	}); err != nil {
+		return err
+	}
+	convergenceMinute := ""
+	if result.ConvergenceMinute != nil {
+		convergenceMinute = strconv.FormatInt(*result.ConvergenceMinute, 10)
+	}
+	for _, flow := range result.Flows {
+		if err := w.Write([]string{
+			flow.FlowID.String(),
+			strconv.FormatInt(flow.RecordedSessions, 10),
+			strconv.FormatInt(flow.ProjectedSessions, 10),
+			strconv.FormatFloat(mm_utils.RoundTo2Decimals(flow.ProjectedTrafficPct), 'f', -1, 64),
+			strconv.FormatInt(flow.ProjectedFeedback, 10),
+			strconv.FormatFloat(flow.ProjectedAvgScore, 'f', -1, 64),
+			strconv.FormatFloat(flow.FinalServePct, 'f', -1, 64),
+			strconv.FormatBool(result.WinnerFlowID != nil && *result.WinnerFlowID == flow.FlowID),
+			string(result.FinalState),
+			convergenceMinute,
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_statistics"
+	"github.com/google/uuid"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+StatisticsRecomputeCommand rebuilds the Flow and Flow Step statistics of a Use Case from the recorded
+picker requests, feedback and session outcomes, and prints the differences with the stored ones
+*/
+func StatisticsRecomputeCommand(c *cli.Context, tx *gorm.DB) error {
+	useCaseIDParam := c.String("use-case-id")
+	since := c.String("since")
+	dryRun := c.Bool("dry-run")
+
+	// Validate use-case-id
+	useCaseID, err := uuid.Parse(useCaseIDParam)
+	if err != nil {
+		return errors.New("use-case-id must be a valid UUID")
+	}
+	// Validate since if set
+	var sinceTime *time.Time
+	if since != "" {
+		if parsedSince, err := time.Parse(time.RFC3339, since); err != nil {
+			return errors.New("since must be a valid ISO 8601 date, e.g., 2025-08-26T15:04:05Z")
+		} else {
+			sinceTime = &parsedSince
+		}
+	}
+	// Execute the command
+	result, err := mm_statistics.Recompute(tx, useCaseID, sinceTime, dryRun)
+	if err == mm_statistics.ErrRolloutStartNotFound {
+		return errors.New("the start of the current rollout is not recorded, set since to recompute from")
+	}
+	if err != nil {
+		return err
+	}
+	// Print only the statistics that differ from the recorded data
+	diffs := mm_statistics.RecomputeResult{
+		UseCaseID:   result.UseCaseID,
+		Since:       result.Since,
+		DryRun:      result.DryRun,
+		Flows:       []mm_statistics.FlowStatisticsDiff{},
+		FlowSteps:   []mm_statistics.FlowStepStatisticsDiff{},
+		FlowBuckets: []mm_statistics.FlowBucketDiff{},
+	}
+	for _, diff := range result.Flows {
+		if diff.Changed {
+			diffs.Flows = append(diffs.Flows, diff)
+		}
+	}
+	for _, diff := range result.FlowSteps {
+		if diff.Changed {
+			diffs.FlowSteps = append(diffs.FlowSteps, diff)
+		}
+	}
+	for _, diff := range result.FlowBuckets {
+		if diff.Changed {
+			diffs.FlowBuckets = append(diffs.FlowBuckets, diff)
+		}
+	}
+	zap.L().Info(
+		"Statistics recomputed",
+		zap.String("service", "statistics-recompute-command"),
+		zap.String("use-case-id", useCaseID.String()),
+		zap.Bool("dry-run", dryRun),
+		zap.Int("changed-flow-statistics", len(diffs.Flows)),
+		zap.Int("changed-flow-step-statistics", len(diffs.FlowSteps)),
+		zap.Int("changed-flow-buckets", len(diffs.FlowBuckets)),
+	)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diffs)
+}
@@ -21,16 +21,21 @@ import (
 	"github.com/ai-model-match/backend/internal/app/rolloutStrategy"
 	"github.com/ai-model-match/backend/internal/app/rsEngine"
 	"github.com/ai-model-match/backend/internal/app/useCase"
+	"github.com/ai-model-match/backend/internal/app/useCaseReport"
 	"github.com/ai-model-match/backend/internal/app/useCaseStep"
+	"github.com/ai-model-match/backend/internal/app/webhook"
 	"github.com/ai-model-match/backend/internal/pkg/mm_auth"
 	"github.com/ai-model-match/backend/internal/pkg/mm_cors"
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_env"
 	"github.com/ai-model-match/backend/internal/pkg/mm_log"
+	"github.com/ai-model-match/backend/internal/pkg/mm_outbox"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/ai-model-match/backend/internal/pkg/mm_router"
 	"github.com/ai-model-match/backend/internal/pkg/mm_scheduler"
+	"github.com/ai-model-match/backend/internal/pkg/mm_tracing"
 	ginzap "github.com/gin-contrib/zap"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
 	"github.com/gin-gonic/gin"
@@ -61,10 +66,17 @@ func main() {
 		envs.DbLogSlowQueryThreshold,
 		envs.AppMode,
 	)
+	// Tracing
+	mm_tracing.Init(mm_tracing.Config{
+		Exporter:     envs.TracingExporter,
+		OtlpEndpoint: envs.TracingOtlpEndpoint,
+		ServiceName:  envs.TracingServiceName,
+		SampleRatio:  envs.TracingSampleRatio,
+	})
 	// Scheduler
 	scheduler := mm_scheduler.NewScheduler()
 	// PUB-SUB agent
-	pubSubAgent := mm_pubsub.NewPubSubAgent(dbConnection, scheduler, envs.PubSubPersistEventsOnDb, envs.PubSubPersistEventsRetentionDays, envs.PubSubSyncMode)
+	pubSubAgent := mm_pubsub.NewPubSubAgent(dbConnection, scheduler, envs.PubSubPersistEventsOnDb, envs.PubSubPersistEventsRetentionDays, envs.PubSubSyncMode, envs.PubSubDistributedMode, envs.PubSubMaxDeliveryAttempts)
 
 	// Start Server
 	zap.L().Info("Starting HTTP Server...", zap.String("service", "webapp"))
@@ -74,6 +86,8 @@ func main() {
 	// Set GIN logger
 	r.Use(ginzap.Ginzap(logger, time.RFC3339, true))
 	r.Use(ginzap.RecoveryWithZap(logger, true))
+	// Tracing middleware
+	r.Use(mm_tracing.TracingMiddleware(envs.TracingServiceName))
 	// Cors Middleware
 	allowOrigins := []string{envs.AppCorsOrigin}
 	if envs.AppMode != "release" {
@@ -91,6 +105,9 @@ func main() {
 	}
 	mm_auth.InitAuthMiddleware(authConfig)
 
+	// Expose metrics to be scraped by Prometheus
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	r.NoRoute(func(ctx *gin.Context) {
 		mm_router.ReturnNotFoundError(ctx, errors.New("endpoint-not-found"))
 	})
@@ -101,6 +118,7 @@ func main() {
 	auth.Init(envs, dbConnection, scheduler, v1Api)
 	useCase.Init(envs, dbConnection, pubSubAgent, v1Api)
 	useCaseStep.Init(envs, dbConnection, pubSubAgent, v1Api)
+	useCaseReport.Init(envs, dbConnection, v1Api)
 	flow.Init(envs, dbConnection, pubSubAgent, v1Api)
 	flowStep.Init(envs, dbConnection, pubSubAgent, v1Api)
 	flowStatistics.Init(envs, dbConnection, pubSubAgent, v1Api)
@@ -109,7 +127,21 @@ func main() {
 	picker.Init(envs, dbConnection, pubSubAgent, scheduler, v1Api)
 	feedback.Init(envs, dbConnection, pubSubAgent, v1Api)
 	rsEngine.Init(envs, dbConnection, pubSubAgent, scheduler)
+	webhook.Init(envs, dbConnection, pubSubAgent, scheduler, v1Api)
+
+	// Relay persisted events to external sinks
+	sinkTimeout := time.Duration(envs.OutboxSinkTimeoutSeconds) * time.Second
+	outboxSinks := []mm_outbox.Sink{}
+	if envs.OutboxWebhookEnabled {
+		outboxSinks = append(outboxSinks, mm_outbox.NewWebhookSink(envs.OutboxWebhookUrl, sinkTimeout))
+	}
+	if envs.OutboxNatsEnabled {
+		outboxSinks = append(outboxSinks, mm_outbox.NewNatsSink(envs.OutboxNatsUrl, envs.OutboxNatsSubjectPrefix, sinkTimeout))
+	}
+	mm_outbox.NewOutboxRelay(dbConnection, scheduler, outboxSinks, envs.OutboxMaxAttempts)
 
+	// Redeliver events not processed yet
+	pubSubAgent.Init()
 	// Start the scheduler
 	if err := scheduler.Init(); err != nil {
 		panic(err)
@@ -146,6 +178,7 @@ func main() {
 	mm_db.CloseDatabaseConnection(dbConnection)
 	scheduler.Close()
 	pubSubAgent.Close()
+	mm_tracing.Shutdown()
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
 		zap.L().Error("Server Shutdown Error", zap.String("service", "webapp"), zap.Error(err))
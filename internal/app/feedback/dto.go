@@ -1,6 +1,8 @@
 package feedback
 
 import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
@@ -18,3 +20,15 @@ func (r createFeedbackInputDto) validate() error {
 		validation.Field(&r.Comment, validation.Required, validation.Length(0, 4096)),
 	)
 }
+
+type createSessionOutcomeInputDto struct {
+	CorrelationID string `json:"correlationId"`
+	Outcome       string `json:"outcome"`
+}
+
+func (r createSessionOutcomeInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.CorrelationID, validation.Required, is.UUID),
+		validation.Field(&r.Outcome, validation.Required, validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableSessionOutcome)...)),
+	)
+}
@@ -9,6 +9,8 @@ import (
 
 type feedbackEntity mm_pubsub.FeedbackEventEntity
 
+type sessionOutcomeEntity mm_pubsub.SessionOutcomeEventEntity
+
 type pickerCorrelationEntity struct {
 	ID        uuid.UUID
 	UseCaseID uuid.UUID
@@ -4,3 +4,4 @@ import "errors"
 
 var errCorrelationNotFound = errors.New("correlation-not-found")
 var errFeedbackAlreadyProvided = errors.New("feedback-already-provided")
+var errSessionOutcomeAlreadyProvided = errors.New("session-outcome-already-provided")
@@ -0,0 +1,27 @@
+package feedback
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var feedbackScoreBuckets = []float64{1, 1.5, 2, 2.5, 3, 3.5, 4, 4.5, 5}
+
+var feedbackTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "mm_feedback_total",
+	Help: "Feedback received by Use Case and Flow",
+}, []string{"use_case_id", "flow_id"})
+
+var feedbackScore = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "mm_feedback_score",
+	Help:    "Distribution of the feedback scores by Use Case and Flow",
+	Buckets: feedbackScoreBuckets,
+}, []string{"use_case_id", "flow_id"})
+
+/*
+Track a new feedback and its score.
+*/
+func observeFeedback(feedback feedbackEntity) {
+	feedbackTotal.WithLabelValues(feedback.UseCaseID.String(), feedback.FlowID.String()).Inc()
+	feedbackScore.WithLabelValues(feedback.UseCaseID.String(), feedback.FlowID.String()).Observe(feedback.Score)
+}
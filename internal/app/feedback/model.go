@@ -3,6 +3,7 @@ package feedback
 import (
 	"time"
 
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/google/uuid"
 )
 
@@ -38,3 +39,20 @@ func (m feedbackModel) TableName() string {
 func (m feedbackModel) toEntity() feedbackEntity {
 	return feedbackEntity(m)
 }
+
+type sessionOutcomeModel struct {
+	ID            uuid.UUID                `gorm:"primaryKey;column:id;type:varchar(36)"`
+	UseCaseID     uuid.UUID                `gorm:"column:use_case_id;type:varchar(36)"`
+	FlowID        uuid.UUID                `gorm:"column:flow_id;type:varchar(36)"`
+	CorrelationID uuid.UUID                `gorm:"column:correlation_id;type:varchar(36)"`
+	Outcome       mm_pubsub.SessionOutcome `gorm:"column:outcome;type:varchar(32)"`
+	CreatedAt     time.Time                `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
+}
+
+func (m sessionOutcomeModel) TableName() string {
+	return "mm_session_outcome"
+}
+
+func (m sessionOutcomeModel) toEntity() sessionOutcomeEntity {
+	return sessionOutcomeEntity(m)
+}
@@ -10,6 +10,8 @@ type feedbackRepositoryInterface interface {
 	getPickerCorrelationByID(tx *gorm.DB, correlationID uuid.UUID) (pickerCorrelationEntity, error)
 	getRecentFeedbackByCorrelationID(tx *gorm.DB, correlationID uuid.UUID) (feedbackEntity, error)
 	saveFeedback(tx *gorm.DB, feedback feedbackEntity, operation mm_db.SaveOperation) (feedbackEntity, error)
+	getRecentSessionOutcomeByCorrelationID(tx *gorm.DB, correlationID uuid.UUID) (sessionOutcomeEntity, error)
+	saveSessionOutcome(tx *gorm.DB, sessionOutcome sessionOutcomeEntity, operation mm_db.SaveOperation) (sessionOutcomeEntity, error)
 }
 
 type feedbackRepository struct {
@@ -61,3 +63,33 @@ func (r feedbackRepository) saveFeedback(tx *gorm.DB, feedback feedbackEntity, o
 	}
 	return feedback, nil
 }
+
+func (r feedbackRepository) getRecentSessionOutcomeByCorrelationID(tx *gorm.DB, correlationID uuid.UUID) (sessionOutcomeEntity, error) {
+	var model *sessionOutcomeModel
+	query := tx.Where("correlation_id = ?", correlationID).Order("created_at DESC")
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return sessionOutcomeEntity{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sessionOutcomeEntity{}, nil
+	}
+	return model.toEntity(), nil
+}
+
+func (r feedbackRepository) saveSessionOutcome(tx *gorm.DB, sessionOutcome sessionOutcomeEntity, operation mm_db.SaveOperation) (sessionOutcomeEntity, error) {
+	var model = sessionOutcomeModel(sessionOutcome)
+	var err error
+	switch operation {
+	case mm_db.Create:
+		err = tx.Create(model).Error
+	case mm_db.Update:
+		err = tx.Updates(model).Error
+	case mm_db.Upsert:
+		err = tx.Save(model).Error
+	}
+	if err != nil {
+		return sessionOutcomeEntity{}, err
+	}
+	return sessionOutcome, nil
+}
@@ -62,4 +62,38 @@ func (r feedbackRouter) register(router *gin.RouterGroup) {
 			mm_router.ReturnOk(ctx, &gin.H{"item": item})
 		})
 
+	router.POST(
+		"/session-outcomes",
+		mm_auth.AuthMiddleware([]string{mm_auth.M2M_READ, mm_auth.M2M_WRITE}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request createSessionOutcomeInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.createSessionOutcome(ctx, request)
+			if err == errCorrelationNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			if err == errSessionOutcomeAlreadyProvided {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "feedback-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
+
 }
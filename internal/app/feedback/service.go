@@ -6,6 +6,7 @@ import (
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_err"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_tracing"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ import (
 
 type feedbackServiceInterface interface {
 	createFeedback(ctx *gin.Context, input createFeedbackInputDto) (feedbackEntity, error)
+	createSessionOutcome(ctx *gin.Context, input createSessionOutcomeInputDto) (sessionOutcomeEntity, error)
 }
 
 type feedbackService struct {
@@ -31,10 +33,12 @@ func newFeedbackService(storage *gorm.DB, pubSubAgent *mm_pubsub.PubSubAgent, re
 }
 
 func (s feedbackService) createFeedback(ctx *gin.Context, input createFeedbackInputDto) (feedbackEntity, error) {
+	// Trace the queries within the request
+	db := s.storage.WithContext(mm_tracing.DetachedContext(ctx))
 	now := time.Now()
 	var newFeedback feedbackEntity
 	eventsToPublish := []mm_pubsub.EventToPublish{}
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+	errTransaction := db.Transaction(func(tx *gorm.DB) error {
 		correlation, err := s.repository.getPickerCorrelationByID(tx, uuid.MustParse(input.CorrelationID))
 		if err != nil {
 			return mm_err.ErrGeneric
@@ -89,6 +93,73 @@ func (s feedbackService) createFeedback(ctx *gin.Context, input createFeedbackIn
 		return feedbackEntity{}, errTransaction
 	} else {
 		s.pubSubAgent.PublishBulk(eventsToPublish)
+		observeFeedback(newFeedback)
 	}
 	return newFeedback, nil
 }
+
+/*
+Store the outcome of a session reported by the client (error or abandon), used by the Escape guardrails.
+*/
+func (s feedbackService) createSessionOutcome(ctx *gin.Context, input createSessionOutcomeInputDto) (sessionOutcomeEntity, error) {
+	// Trace the queries within the request
+	db := s.storage.WithContext(mm_tracing.DetachedContext(ctx))
+	now := time.Now()
+	var newSessionOutcome sessionOutcomeEntity
+	eventsToPublish := []mm_pubsub.EventToPublish{}
+	errTransaction := db.Transaction(func(tx *gorm.DB) error {
+		correlation, err := s.repository.getPickerCorrelationByID(tx, uuid.MustParse(input.CorrelationID))
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		if mm_utils.IsEmpty(correlation) {
+			return errCorrelationNotFound
+		}
+		recentSessionOutcome, err := s.repository.getRecentSessionOutcomeByCorrelationID(tx, uuid.MustParse(input.CorrelationID))
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		if !mm_utils.IsEmpty(recentSessionOutcome) && recentSessionOutcome.CreatedAt.After(correlation.CreatedAt) {
+			return errSessionOutcomeAlreadyProvided
+		}
+		newSessionOutcome = sessionOutcomeEntity{
+			ID:            uuid.New(),
+			CorrelationID: correlation.ID,
+			UseCaseID:     correlation.UseCaseID,
+			FlowID:        correlation.FlowID,
+			Outcome:       mm_pubsub.SessionOutcome(input.Outcome),
+			CreatedAt:     now,
+		}
+		if _, err = s.repository.saveSessionOutcome(tx, newSessionOutcome, mm_db.Create); err != nil {
+			return mm_err.ErrGeneric
+		}
+		// Send an event of session outcome created
+		if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicFeedbackV1, mm_pubsub.PubSubMessage{
+			Message: mm_pubsub.PubSubEvent{
+				EventID:   uuid.New(),
+				EventTime: time.Now(),
+				EventType: mm_pubsub.SessionOutcomeCreatedEvent,
+				EventEntity: &mm_pubsub.SessionOutcomeEventEntity{
+					ID:            newSessionOutcome.ID,
+					CorrelationID: newSessionOutcome.CorrelationID,
+					UseCaseID:     newSessionOutcome.UseCaseID,
+					FlowID:        newSessionOutcome.FlowID,
+					Outcome:       newSessionOutcome.Outcome,
+					CreatedAt:     newSessionOutcome.CreatedAt,
+				},
+				EventChangedFields: mm_utils.DiffStructs(sessionOutcomeEntity{}, newSessionOutcome),
+			},
+		}); err != nil {
+			return err
+		} else {
+			eventsToPublish = append(eventsToPublish, event)
+		}
+		return nil
+	})
+	if errTransaction != nil {
+		return sessionOutcomeEntity{}, errTransaction
+	} else {
+		s.pubSubAgent.PublishBulk(eventsToPublish)
+	}
+	return newSessionOutcome, nil
+}
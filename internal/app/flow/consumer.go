@@ -25,7 +25,7 @@ func newFlowConsumer(pubSub *mm_pubsub.PubSubAgent, service flowServiceInterface
 
 func (r flowConsumer) subscribe() {
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRsEngineV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRsEngineV1, "flow")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -56,8 +56,9 @@ func (r flowConsumer) subscribe() {
 				}
 				event := msg.Message.EventEntity.(*mm_pubsub.RsEngineEventEntity)
 				// Update the Rollout Strategy
-				if err := r.service.updateFlowsFromEvent(*event); err != nil {
+				if err := r.service.updateFlowsFromEvent(msg.Message.Context(), *event); err != nil {
 					zap.L().Error("Impossible to update flows from RS Engine event", zap.String("service", "rollout-strategy-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
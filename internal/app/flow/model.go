@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/google/uuid"
 )
 
@@ -16,6 +17,16 @@ func (m useCaseModel) TableName() string {
 	return "mm_use_case"
 }
 
+type rolloutStrategyModel struct {
+	ID           uuid.UUID              `gorm:"primaryKey;column:id;type:varchar(36)"`
+	UseCaseID    uuid.UUID              `gorm:"column:use_case_id;type:varchar(36)"`
+	RolloutState mm_pubsub.RolloutState `gorm:"column:rollout_state;type:rollout_state"`
+}
+
+func (m rolloutStrategyModel) TableName() string {
+	return "mm_rollout_strategy"
+}
+
 type flowModel struct {
 	ID              uuid.UUID  `gorm:"primaryKey;column:id;type:varchar(36)"`
 	UseCaseID       uuid.UUID  `gorm:"column:use_case_id;type:varchar(36)"`
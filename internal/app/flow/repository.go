@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -18,6 +19,7 @@ type flowRepositoryInterface interface {
 	getFlowByID(tx *gorm.DB, flowID uuid.UUID, forUpdate bool) (flowEntity, error)
 	getFlowByCode(tx *gorm.DB, useCaseID uuid.UUID, flowCode string, forUpdate bool) (flowEntity, error)
 	getAllActiveFlow(tx *gorm.DB, useCaseID uuid.UUID, forUpdate bool) ([]flowEntity, error)
+	getRolloutStateByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) (mm_pubsub.RolloutState, error)
 	saveFlow(tx *gorm.DB, flow flowEntity, operation mm_db.SaveOperation) (flowEntity, error)
 	deleteFlow(tx *gorm.DB, flow flowEntity) (flowEntity, error)
 }
@@ -164,6 +166,23 @@ func (r flowRepository) getAllActiveFlow(tx *gorm.DB, useCaseID uuid.UUID, forUp
 	return entities, nil
 }
 
+/*
+Read the state of the Rollout Strategy of the Use Case, locking it until the end of the transaction
+to prevent concurrent changes of state.
+*/
+func (r flowRepository) getRolloutStateByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) (mm_pubsub.RolloutState, error) {
+	var model *rolloutStrategyModel
+	query := tx.Where("use_case_id = ?", useCaseID).Clauses(clause.Locking{Strength: "SHARE"})
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 || mm_utils.IsEmpty(model) {
+		return "", nil
+	}
+	return model.RolloutState, nil
+}
+
 func (r flowRepository) saveFlow(tx *gorm.DB, flow flowEntity, operation mm_db.SaveOperation) (flowEntity, error) {
 	var model = flowModel(flow)
 	var err error
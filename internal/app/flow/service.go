@@ -1,6 +1,7 @@
 package flow
 
 import (
+	"context"
 	"math"
 	"time"
 
@@ -21,7 +22,7 @@ type flowServiceInterface interface {
 	deleteFlow(ctx *gin.Context, input deleteFlowInputDto) (flowEntity, error)
 	cloneFlow(ctx *gin.Context, input cloneFlowInputDto) (flowEntity, error)
 	updateFlowPctBulk(ctx *gin.Context, input updateFlowPctBulkDto) ([]flowEntity, error)
-	updateFlowsFromEvent(event mm_pubsub.RsEngineEventEntity) error
+	updateFlowsFromEvent(ctx context.Context, event mm_pubsub.RsEngineEventEntity) error
 }
 
 type flowService struct {
@@ -474,20 +475,32 @@ func (s flowService) updateFlowPctBulk(ctx *gin.Context, input updateFlowPctBulk
 	return updatedFlows, nil
 }
 
-func (s flowService) updateFlowsFromEvent(event mm_pubsub.RsEngineEventEntity) error {
+func (s flowService) updateFlowsFromEvent(ctx context.Context, event mm_pubsub.RsEngineEventEntity) error {
 	eventsToPublish := []mm_pubsub.EventToPublish{}
 	updatedFlows := []flowEntity{}
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
-		exists, err := s.repository.checkUseCaseExists(s.storage, event.UseCaseID)
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
+		exists, err := s.repository.checkUseCaseExists(tx, event.UseCaseID)
 		if err != nil {
 			return mm_err.ErrGeneric
 		}
 		if !exists {
 			return errUseCaseNotFound
 		}
+		// Skip the allocation if the Rollout Strategy has been paused or moved to another state after the engine evaluated it
+		rolloutState, err := s.repository.getRolloutStateByUseCaseID(tx, event.UseCaseID)
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		if !event.AppliesTo(rolloutState) {
+			return nil
+		}
 		// Read all active Flows
 		var activeFlows []flowEntity
-		if activeFlows, err = s.repository.getAllActiveFlow(s.storage, event.UseCaseID, true); err != nil {
+		if activeFlows, err = s.repository.getAllActiveFlow(tx, event.UseCaseID, true); err != nil {
 			return mm_err.ErrGeneric
 		}
 		// Prepare indexed Active Flows
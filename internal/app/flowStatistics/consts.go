@@ -0,0 +1,8 @@
+package flowStatistics
+
+/*
+Sources of the Rollout Strategy history entries: the allocations applied by the engine and the manual transitions,
+which record the serve PCTs in effect without changing them
+*/
+const rsHistorySourceManual string = "MANUAL"
+const rsHistorySourceEngine string = "ENGINE"
@@ -25,7 +25,7 @@ func newFlowStatisticsConsumer(pubSub *mm_pubsub.PubSubAgent, service flowStatis
 
 func (r flowStatisticsConsumer) subscribe() {
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowV1, "flow-statistics")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -62,6 +62,7 @@ func (r flowStatisticsConsumer) subscribe() {
 						return
 					} else {
 						zap.L().Error("Impossible to create the flowStatisticss for the new Flow", zap.String("service", "flow-statistics-consumer"), zap.Error(err))
+						msg.Message.EventState.Fail(err)
 						return
 					}
 				}
@@ -70,7 +71,7 @@ func (r flowStatisticsConsumer) subscribe() {
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicPickerV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicPickerV1, "flow-statistics")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -101,8 +102,9 @@ func (r flowStatisticsConsumer) subscribe() {
 				}
 				event := msg.Message.EventEntity.(*mm_pubsub.PickerEventEntity)
 				// Update Flow Statistics
-				if err := r.service.updateRequestStatistics(*event); err != nil {
+				if err := r.service.updateRequestStatistics(msg.Message.Context(), *event); err != nil {
 					zap.L().Error("Impossible to update requests Flow statistics", zap.String("service", "flow-statistics-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
@@ -110,7 +112,7 @@ func (r flowStatisticsConsumer) subscribe() {
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFeedbackV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFeedbackV1, "flow-statistics")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -136,21 +138,30 @@ func (r flowStatisticsConsumer) subscribe() {
 					zap.String("event-id", msg.Message.EventID.String()),
 					zap.String("event-type", string(msg.Message.EventType)),
 				)
-				if msg.Message.EventType != mm_pubsub.FeedbackCreatedEvent {
-					return
-				}
-				event := msg.Message.EventEntity.(*mm_pubsub.FeedbackEventEntity)
-				// Update Flow Statistics
-				if err := r.service.updateFeedbackStatistics(*event); err != nil {
-					zap.L().Error("Impossible to update feedback Flow statistics", zap.String("service", "flow-statistics-consumer"))
-					return
+				switch msg.Message.EventType {
+				case mm_pubsub.FeedbackCreatedEvent:
+					event := msg.Message.EventEntity.(*mm_pubsub.FeedbackEventEntity)
+					// Update Flow Statistics
+					if err := r.service.updateFeedbackStatistics(msg.Message.Context(), *event); err != nil {
+						zap.L().Error("Impossible to update feedback Flow statistics", zap.String("service", "flow-statistics-consumer"))
+						msg.Message.EventState.Fail(err)
+						return
+					}
+				case mm_pubsub.SessionOutcomeCreatedEvent:
+					event := msg.Message.EventEntity.(*mm_pubsub.SessionOutcomeEventEntity)
+					// Update Flow Statistics
+					if err := r.service.updateOutcomeStatistics(msg.Message.Context(), *event); err != nil {
+						zap.L().Error("Impossible to update outcome Flow statistics", zap.String("service", "flow-statistics-consumer"))
+						msg.Message.EventState.Fail(err)
+						return
+					}
 				}
 			}()
 		}
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRolloutStrategyV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRolloutStrategyV1, "flow-statistics")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -184,9 +195,14 @@ func (r flowStatisticsConsumer) subscribe() {
 				if event.RolloutState != mm_pubsub.RolloutStateWarmup {
 					return
 				}
+				// A Warmup resumed after a pause keeps its statistics
+				if event.Configuration.StateConfigurations.PhaseStartedAt != nil {
+					return
+				}
 				// Cleanup statistics on Rollout Strategy start
-				if err := r.service.cleanupStatistics(*event); err != nil {
+				if err := r.service.cleanupStatistics(msg.Message.Context(), *event); err != nil {
 					zap.L().Error("Impossible to cleanup Statistics for Flow", zap.String("service", "flow-statistics-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
@@ -1,6 +1,12 @@
 package flowStatistics
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_timeseries"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
@@ -14,3 +20,45 @@ func (r getFlowStatisticsInputDto) validate() error {
 		validation.Field(&r.FlowID, validation.Required, is.UUID),
 	)
 }
+
+type recomputeStatisticsInputDto struct {
+	UseCaseID string  `uri:"useCaseId"`
+	Since     *string `json:"since"`
+	DryRun    bool    `json:"dryRun"`
+}
+
+func (r recomputeStatisticsInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.UseCaseID, validation.Required, is.UUID),
+		validation.Field(&r.Since, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+	)
+}
+
+type getFlowStatisticsTimeseriesInputDto struct {
+	FlowID      string `uri:"flowId"`
+	From        string `form:"from"`
+	To          string `form:"to"`
+	Granularity string `form:"granularity"`
+}
+
+func (r getFlowStatisticsTimeseriesInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FlowID, validation.Required, is.UUID),
+		validation.Field(&r.From, validation.Required, validation.Date(time.RFC3339)),
+		validation.Field(&r.To, validation.Required, validation.Date(time.RFC3339), validation.By(func(value interface{}) error {
+			from := mm_utils.GetOptionalTimeFromString(&r.From)
+			to := mm_utils.GetOptionalTimeFromString(&r.To)
+			if from == nil || to == nil {
+				return nil
+			}
+			if !to.After(*from) {
+				return errors.New("must be after from")
+			}
+			if mm_timeseries.CountPoints(*from, *to, mm_timeseries.Granularity(r.Granularity)) > mm_timeseries.MaxPoints {
+				return fmt.Errorf("too many points, max %d", mm_timeseries.MaxPoints)
+			}
+			return nil
+		})),
+		validation.Field(&r.Granularity, validation.Required, validation.In(mm_utils.TransformToStrings(mm_timeseries.AvailableGranularity)...)),
+	)
+}
@@ -1,13 +1,58 @@
 package flowStatistics
 
 import (
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/google/uuid"
 )
 
 type flowStatisticsEntity mm_pubsub.FlowStatisticsEventEntity
 
+type useCaseEntity struct {
+	ID uuid.UUID `json:"id"`
+}
+
 type flowEntity struct {
-	ID        uuid.UUID `json:"flowId"`
-	UseCaseID uuid.UUID `json:"useCaseId"`
+	ID              uuid.UUID `json:"flowId"`
+	UseCaseID       uuid.UUID `json:"useCaseId"`
+	CurrentServePct *float64  `json:"currentServePct"`
+}
+
+/*
+Increments of the statistics of a Flow in the hour starting at BucketStart
+*/
+type flowStatisticsBucketEntity struct {
+	ID                 uuid.UUID `json:"id"`
+	FlowID             uuid.UUID `json:"flowId"`
+	UseCaseID          uuid.UUID `json:"useCaseId"`
+	BucketStart        time.Time `json:"bucketStart"`
+	TotRequests        int64     `json:"totRequests"`
+	TotSessionRequests int64     `json:"totSessionRequests"`
+	TotFeedback        int64     `json:"totFeedback"`
+	SumScore           float64   `json:"sumScore"`
+	SumSquaredScore    float64   `json:"sumSquaredScore"`
+}
+
+/*
+Statistics of a Flow in a point of the time series, with the serve PCT in effect at its end
+(nil if not known, e.g. before the first allocation applied by the engine on a Rollout Strategy started before
+the manual transitions recorded the serve PCTs)
+*/
+type flowStatisticsPointEntity struct {
+	Timestamp          time.Time `json:"timestamp"`
+	TotRequests        int64     `json:"totRequests"`
+	TotSessionRequests int64     `json:"totSessionRequests"`
+	TotFeedback        int64     `json:"totFeedback"`
+	AvgScore           float64   `json:"avgScore"`
+	CurrentServePct    *float64  `json:"currentServePct"`
+}
+
+/*
+Serve PCTs of the Flows in effect since the given time, applied by the engine or recorded by a manual transition
+*/
+type flowAllocationEntity struct {
+	Source    string                              `json:"source"`
+	CreatedAt time.Time                           `json:"createdAt"`
+	Flows     []mm_pubsub.RsEngineFlowEventEntity `json:"flows"`
 }
@@ -2,6 +2,8 @@ package flowStatistics
 
 import "errors"
 
+var errUseCaseNotFound = errors.New("use-case-not-found")
 var errFlowNotFound = errors.New("flow-not-found")
 var errFlowStatisticsNotFound = errors.New("flow-statistics-not-found")
 var errFlowStatisticsAlreadyExists = errors.New("flow-statistics-already-exists")
+var errRolloutStartNotFound = errors.New("rollout-start-not-found")
@@ -1,14 +1,29 @@
 package flowStatistics
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/google/uuid"
 )
 
+type useCaseModel struct {
+	ID uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+}
+
+func (m useCaseModel) TableName() string {
+	return "mm_use_case"
+}
+
+func (m useCaseModel) toEntity() useCaseEntity {
+	return useCaseEntity(m)
+}
+
 type flowModel struct {
-	ID        uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
-	UseCaseID uuid.UUID `gorm:"column:use_case_id;type:varchar(36)"`
+	ID              uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+	UseCaseID       uuid.UUID `gorm:"column:use_case_id;type:varchar(36)"`
+	CurrentServePct *float64  `gorm:"column:current_pct;type:double precision"`
 }
 
 func (m flowModel) TableName() string {
@@ -26,6 +41,9 @@ type flowStatisticsModel struct {
 	TotSessionRequests int64     `gorm:"column:tot_sess_req;type:bigint"`
 	TotFeedback        int64     `gorm:"column:tot_feedback;type:bigint"`
 	AvgScore           float64   `gorm:"column:avg_score;type:double precision"`
+	TotOneStarFeedback int64     `gorm:"column:tot_one_star_feedback;type:bigint"`
+	TotErrors          int64     `gorm:"column:tot_errors;type:bigint"`
+	TotAbandons        int64     `gorm:"column:tot_abandons;type:bigint"`
 	CreatedAt          time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
 	UpdatedAt          time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime:false"`
 }
@@ -37,3 +55,46 @@ func (m flowStatisticsModel) TableName() string {
 func (m flowStatisticsModel) toEntity() flowStatisticsEntity {
 	return flowStatisticsEntity(m)
 }
+
+type flowStatisticsBucketModel struct {
+	ID                 uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+	FlowID             uuid.UUID `gorm:"column:flow_id;type:varchar(36)"`
+	UseCaseID          uuid.UUID `gorm:"column:use_case_id;type:varchar(36)"`
+	BucketStart        time.Time `gorm:"column:bucket_start;type:timestamp"`
+	TotRequests        int64     `gorm:"column:tot_req;type:bigint"`
+	TotSessionRequests int64     `gorm:"column:tot_sess_req;type:bigint"`
+	TotFeedback        int64     `gorm:"column:tot_feedback;type:bigint"`
+	SumScore           float64   `gorm:"column:sum_score;type:double precision"`
+	SumSquaredScore    float64   `gorm:"column:sum_squared_score;type:double precision"`
+}
+
+func (m flowStatisticsBucketModel) TableName() string {
+	return "mm_flow_statistics_bucket"
+}
+
+func (m flowStatisticsBucketModel) toEntity() flowStatisticsBucketEntity {
+	return flowStatisticsBucketEntity(m)
+}
+
+type flowAllocationModel struct {
+	Source    string          `gorm:"column:source;type:varchar(32)"`
+	Flows     json.RawMessage `gorm:"column:flows;type:json"`
+	CreatedAt time.Time       `gorm:"column:created_at;type:timestamp"`
+}
+
+func (m flowAllocationModel) TableName() string {
+	return "mm_rollout_strategy_history"
+}
+
+func (m flowAllocationModel) toEntity() flowAllocationEntity {
+	// Remap the stored JSON Flows in the object list
+	flows := []mm_pubsub.RsEngineFlowEventEntity{}
+	if err := json.Unmarshal(m.Flows, &flows); err != nil {
+		flows = []mm_pubsub.RsEngineFlowEventEntity{}
+	}
+	return flowAllocationEntity{
+		Source:    m.Source,
+		CreatedAt: m.CreatedAt,
+		Flows:     flows,
+	}
+}
@@ -1,6 +1,8 @@
 package flowStatistics
 
 import (
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/google/uuid"
@@ -9,10 +11,15 @@ import (
 )
 
 type flowStatisticsRepositoryInterface interface {
+	getUseCaseByID(tx *gorm.DB, useCaseID uuid.UUID) (useCaseEntity, error)
 	getFlowByID(tx *gorm.DB, flowID uuid.UUID) (flowEntity, error)
 	getFlowStatisticsByFlowID(tx *gorm.DB, flowID uuid.UUID, forUpdate bool) (flowStatisticsEntity, error)
 	saveFlowStatistics(tx *gorm.DB, flowStatistics flowStatisticsEntity, operation mm_db.SaveOperation) (flowStatisticsEntity, error)
 	cleanupFlowStatisticsByUseCaseId(tx *gorm.DB, useCaseID uuid.UUID) error
+	incrementFlowStatisticsBucket(tx *gorm.DB, bucket flowStatisticsBucketEntity) error
+	listFlowStatisticsBuckets(tx *gorm.DB, flowID uuid.UUID, from time.Time, to time.Time) ([]flowStatisticsBucketEntity, error)
+	listFlowAllocations(tx *gorm.DB, useCaseID uuid.UUID, from time.Time, to time.Time) ([]flowAllocationEntity, error)
+	hasFlowAllocationsSince(tx *gorm.DB, useCaseID uuid.UUID, since time.Time) (bool, error)
 }
 
 type flowStatisticsRepository struct {
@@ -22,6 +29,19 @@ func newFlowStatisticsRepository() flowStatisticsRepository {
 	return flowStatisticsRepository{}
 }
 
+func (r flowStatisticsRepository) getUseCaseByID(tx *gorm.DB, useCaseID uuid.UUID) (useCaseEntity, error) {
+	var model *useCaseModel
+	query := tx.Where("id = ?", useCaseID)
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return useCaseEntity{}, result.Error
+	}
+	if result.RowsAffected == 0 || mm_utils.IsEmpty(model) {
+		return useCaseEntity{}, nil
+	}
+	return model.toEntity(), nil
+}
+
 func (r flowStatisticsRepository) getFlowByID(tx *gorm.DB, flowID uuid.UUID) (flowEntity, error) {
 	var model *flowModel
 	query := tx.Where("id = ?", flowID)
@@ -72,10 +92,97 @@ func (r flowStatisticsRepository) cleanupFlowStatisticsByUseCaseId(tx *gorm.DB,
 	result := tx.Model(&flowStatisticsModel{}).
 		Where("use_case_id = ?", useCaseID).
 		UpdateColumns(map[string]any{
-			"tot_req":      0,
-			"tot_sess_req": 0,
-			"tot_feedback": 0,
-			"avg_score":    0,
+			"tot_req":               0,
+			"tot_sess_req":          0,
+			"tot_feedback":          0,
+			"avg_score":             0,
+			"tot_one_star_feedback": 0,
+			"tot_errors":            0,
+			"tot_abandons":          0,
 		})
 	return result.Error
 }
+
+/*
+Create the bucket of the Flow for its hour or, if it already exists, add the given increments to it
+*/
+func (r flowStatisticsRepository) incrementFlowStatisticsBucket(tx *gorm.DB, bucket flowStatisticsBucketEntity) error {
+	var model = flowStatisticsBucketModel(bucket)
+	result := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "flow_id"}, {Name: "bucket_start"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"tot_req":           gorm.Expr("mm_flow_statistics_bucket.tot_req + EXCLUDED.tot_req"),
+			"tot_sess_req":      gorm.Expr("mm_flow_statistics_bucket.tot_sess_req + EXCLUDED.tot_sess_req"),
+			"tot_feedback":      gorm.Expr("mm_flow_statistics_bucket.tot_feedback + EXCLUDED.tot_feedback"),
+			"sum_score":         gorm.Expr("mm_flow_statistics_bucket.sum_score + EXCLUDED.sum_score"),
+			"sum_squared_score": gorm.Expr("mm_flow_statistics_bucket.sum_squared_score + EXCLUDED.sum_squared_score"),
+		}),
+	}).Create(&model)
+	return result.Error
+}
+
+func (r flowStatisticsRepository) listFlowStatisticsBuckets(tx *gorm.DB, flowID uuid.UUID, from time.Time, to time.Time) ([]flowStatisticsBucketEntity, error) {
+	var models []flowStatisticsBucketModel
+	query := tx.Model(flowStatisticsBucketModel{}).
+		Where("flow_id = ?", flowID).
+		Where("bucket_start >= ?", from).
+		Where("bucket_start < ?", to)
+
+	result := query.Order("bucket_start ASC").Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]flowStatisticsBucketEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+/*
+Retrieve the allocations of the Flows of the Use Case between from and to, applied by the engine or recorded by
+manual transitions, together with the last one before from, still in effect at the beginning of the period.
+Manual transitions recorded before the serve PCTs were tracked have no Flows, so they are skipped.
+*/
+func (r flowStatisticsRepository) listFlowAllocations(tx *gorm.DB, useCaseID uuid.UUID, from time.Time, to time.Time) ([]flowAllocationEntity, error) {
+	var previousModels []flowAllocationModel
+	result := tx.Model(flowAllocationModel{}).
+		Where("use_case_id = ?", useCaseID).
+		Where("flows::text <> '[]'").
+		Where("created_at < ?", from).
+		Order("created_at DESC").Limit(1).Find(&previousModels)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var models []flowAllocationModel
+	result = tx.Model(flowAllocationModel{}).
+		Where("use_case_id = ?", useCaseID).
+		Where("flows::text <> '[]'").
+		Where("created_at >= ?", from).
+		Where("created_at < ?", to).
+		Order("created_at ASC").Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := []flowAllocationEntity{}
+	for _, model := range append(previousModels, models...) {
+		entities = append(entities, model.toEntity())
+	}
+	return entities, nil
+}
+
+/*
+Check if any allocation has been applied on the Flows of the Use Case since the given time
+*/
+func (r flowStatisticsRepository) hasFlowAllocationsSince(tx *gorm.DB, useCaseID uuid.UUID, since time.Time) (bool, error) {
+	var count int64
+	result := tx.Model(flowAllocationModel{}).
+		Where("use_case_id = ?", useCaseID).
+		Where("flows::text <> '[]'").
+		Where("created_at >= ?", since).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
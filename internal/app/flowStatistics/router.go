@@ -56,4 +56,68 @@ func (r flowStatisticsRouter) register(router *gin.RouterGroup) {
 			}
 			mm_router.ReturnOk(ctx, &gin.H{"item": item})
 		})
+
+	router.GET(
+		"/flows/:flowId/flow-statistics/timeseries",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(5)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request getFlowStatisticsTimeseriesInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			items, err := r.service.getFlowStatisticsTimeseries(ctx, request)
+			if err == errFlowNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-statistics-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"items": items})
+		})
+
+	router.POST(
+		"/use-cases/:useCaseId/statistics/recompute",
+		mm_auth.AuthMiddleware([]string{mm_auth.WRITE}),
+		mm_timeout.TimeoutMiddleware(time.Duration(5)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request recomputeStatisticsInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.recomputeStatistics(ctx, request)
+			if err == errUseCaseNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			if err == errRolloutStartNotFound {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-statistics-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
 }
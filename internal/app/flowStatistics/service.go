@@ -1,11 +1,15 @@
 package flowStatistics
 
 import (
+	"context"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_err"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/ai-model-match/backend/internal/pkg/mm_statistics"
+	"github.com/ai-model-match/backend/internal/pkg/mm_timeseries"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,10 +18,13 @@ import (
 
 type flowStatisticsServiceInterface interface {
 	getFlowStatisticsByID(ctx *gin.Context, input getFlowStatisticsInputDto) (flowStatisticsEntity, error)
+	getFlowStatisticsTimeseries(ctx *gin.Context, input getFlowStatisticsTimeseriesInputDto) ([]flowStatisticsPointEntity, error)
+	recomputeStatistics(ctx *gin.Context, input recomputeStatisticsInputDto) (mm_statistics.RecomputeResult, error)
 	createFlowStatistics(flowID uuid.UUID) (flowStatisticsEntity, error)
-	updateRequestStatistics(event mm_pubsub.PickerEventEntity) error
-	updateFeedbackStatistics(event mm_pubsub.FeedbackEventEntity) error
-	cleanupStatistics(event mm_pubsub.RolloutStrategyEventEntity) error
+	updateRequestStatistics(ctx context.Context, event mm_pubsub.PickerEventEntity) error
+	updateFeedbackStatistics(ctx context.Context, event mm_pubsub.FeedbackEventEntity) error
+	updateOutcomeStatistics(ctx context.Context, event mm_pubsub.SessionOutcomeEventEntity) error
+	cleanupStatistics(ctx context.Context, event mm_pubsub.RolloutStrategyEventEntity) error
 }
 
 type flowStatisticsService struct {
@@ -46,6 +53,106 @@ func (s flowStatisticsService) getFlowStatisticsByID(ctx *gin.Context, input get
 	return item, nil
 }
 
+/*
+Build the time series of the Flow statistics from the hourly buckets, with the serve PCT in effect
+*/
+func (s flowStatisticsService) getFlowStatisticsTimeseries(ctx *gin.Context, input getFlowStatisticsTimeseriesInputDto) ([]flowStatisticsPointEntity, error) {
+	flowID := uuid.MustParse(input.FlowID)
+	from := *mm_utils.GetOptionalTimeFromString(&input.From)
+	to := *mm_utils.GetOptionalTimeFromString(&input.To)
+	granularity := mm_timeseries.Granularity(input.Granularity)
+	flow, err := s.repository.getFlowByID(s.storage, flowID)
+	if err != nil {
+		return []flowStatisticsPointEntity{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(flow) {
+		return []flowStatisticsPointEntity{}, errFlowNotFound
+	}
+	points := mm_timeseries.Points(from, to, granularity)
+	if len(points) == 0 {
+		return []flowStatisticsPointEntity{}, nil
+	}
+	buckets, err := s.repository.listFlowStatisticsBuckets(s.storage, flowID, points[0], to)
+	if err != nil {
+		return []flowStatisticsPointEntity{}, mm_err.ErrGeneric
+	}
+	allocations, err := s.repository.listFlowAllocations(s.storage, flow.UseCaseID, points[0], to)
+	if err != nil {
+		return []flowStatisticsPointEntity{}, mm_err.ErrGeneric
+	}
+	// Sum the hourly buckets in their points
+	indexedPoints := map[time.Time]int{}
+	items := make([]flowStatisticsPointEntity, len(points))
+	sumScores := make([]float64, len(points))
+	for i, point := range points {
+		indexedPoints[point] = i
+		items[i] = flowStatisticsPointEntity{Timestamp: point}
+	}
+	for _, bucket := range buckets {
+		i, ok := indexedPoints[mm_timeseries.Truncate(bucket.BucketStart, granularity)]
+		if !ok {
+			continue
+		}
+		items[i].TotRequests += bucket.TotRequests
+		items[i].TotSessionRequests += bucket.TotSessionRequests
+		items[i].TotFeedback += bucket.TotFeedback
+		sumScores[i] += bucket.SumScore
+	}
+	// Seed the serve PCT in effect at the beginning of the period, if there is no allocation before it: a manual
+	// transition does not change the serve PCTs, so they were the same before it; without allocations since then,
+	// the Flow is still served with its current PCT
+	var servePct *float64
+	if len(allocations) == 0 {
+		changed, err := s.repository.hasFlowAllocationsSince(s.storage, flow.UseCaseID, to)
+		if err != nil {
+			return []flowStatisticsPointEntity{}, mm_err.ErrGeneric
+		}
+		if !changed {
+			servePct = flow.CurrentServePct
+		}
+	} else if allocations[0].Source == rsHistorySourceManual {
+		pct := allocationServePct(allocations[0], flowID)
+		servePct = &pct
+	}
+	// Compute the average scores and the serve PCT in effect at the end of each point
+	a := 0
+	for i := range items {
+		if items[i].TotFeedback > 0 {
+			items[i].AvgScore = mm_utils.RoundTo2Decimals(sumScores[i] / float64(items[i].TotFeedback))
+		}
+		pointEnd := items[i].Timestamp.Add(granularity.Duration())
+		for ; a < len(allocations) && allocations[a].CreatedAt.Before(pointEnd); a++ {
+			pct := allocationServePct(allocations[a], flowID)
+			servePct = &pct
+		}
+		items[i].CurrentServePct = servePct
+	}
+	return items, nil
+}
+
+/*
+Rebuild the Flow and Flow Step statistics of a Use Case from the recorded requests, feedback and session outcomes.
+With dry run the differences are only reported, without updating the statistics.
+*/
+func (s flowStatisticsService) recomputeStatistics(ctx *gin.Context, input recomputeStatisticsInputDto) (mm_statistics.RecomputeResult, error) {
+	useCaseID := uuid.MustParse(input.UseCaseID)
+	useCase, err := s.repository.getUseCaseByID(s.storage, useCaseID)
+	if err != nil {
+		return mm_statistics.RecomputeResult{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(useCase) {
+		return mm_statistics.RecomputeResult{}, errUseCaseNotFound
+	}
+	result, err := mm_statistics.Recompute(s.storage, useCaseID, mm_utils.GetOptionalTimeFromString(input.Since), input.DryRun)
+	if err == mm_statistics.ErrRolloutStartNotFound {
+		return mm_statistics.RecomputeResult{}, errRolloutStartNotFound
+	}
+	if err != nil {
+		return mm_statistics.RecomputeResult{}, mm_err.ErrGeneric
+	}
+	return result, nil
+}
+
 func (s flowStatisticsService) createFlowStatistics(flowID uuid.UUID) (flowStatisticsEntity, error) {
 	now := time.Now()
 	var newFlowStatistics flowStatisticsEntity
@@ -76,6 +183,9 @@ func (s flowStatisticsService) createFlowStatistics(flowID uuid.UUID) (flowStati
 			TotSessionRequests: 0,
 			TotFeedback:        0,
 			AvgScore:           0,
+			TotOneStarFeedback: 0,
+			TotErrors:          0,
+			TotAbandons:        0,
 			CreatedAt:          now,
 			UpdatedAt:          now,
 		}
@@ -96,6 +206,9 @@ func (s flowStatisticsService) createFlowStatistics(flowID uuid.UUID) (flowStati
 					TotSessionRequests: newFlowStatistics.TotSessionRequests,
 					TotFeedback:        newFlowStatistics.TotFeedback,
 					AvgScore:           newFlowStatistics.AvgScore,
+					TotOneStarFeedback: newFlowStatistics.TotOneStarFeedback,
+					TotErrors:          newFlowStatistics.TotErrors,
+					TotAbandons:        newFlowStatistics.TotAbandons,
 					CreatedAt:          newFlowStatistics.CreatedAt,
 					UpdatedAt:          newFlowStatistics.UpdatedAt,
 				},
@@ -116,10 +229,14 @@ func (s flowStatisticsService) createFlowStatistics(flowID uuid.UUID) (flowStati
 	return newFlowStatistics, nil
 }
 
-func (s flowStatisticsService) updateRequestStatistics(event mm_pubsub.PickerEventEntity) error {
+func (s flowStatisticsService) updateRequestStatistics(ctx context.Context, event mm_pubsub.PickerEventEntity) error {
 	eventsToPublish := []mm_pubsub.EventToPublish{}
 	var updatedFlowStatistics flowStatisticsEntity
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
 		// Find the flow statistics
 		currentFlowStatistics, err := s.repository.getFlowStatisticsByFlowID(tx, event.FlowID, true)
 		if err != nil {
@@ -138,6 +255,20 @@ func (s flowStatisticsService) updateRequestStatistics(event mm_pubsub.PickerEve
 		if _, err := s.repository.saveFlowStatistics(tx, updatedFlowStatistics, mm_db.Update); err != nil {
 			return err
 		}
+		// Track the request also in the hourly bucket
+		bucket := flowStatisticsBucketEntity{
+			ID:          uuid.New(),
+			FlowID:      updatedFlowStatistics.FlowID,
+			UseCaseID:   updatedFlowStatistics.UseCaseID,
+			BucketStart: event.CreatedAt.Truncate(time.Hour),
+			TotRequests: 1,
+		}
+		if *event.IsFirstCorrelation {
+			bucket.TotSessionRequests = 1
+		}
+		if err := s.repository.incrementFlowStatisticsBucket(tx, bucket); err != nil {
+			return err
+		}
 		// Persist event
 		if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicFlowStatisticsV1, mm_pubsub.PubSubMessage{
 			Message: mm_pubsub.PubSubEvent{
@@ -152,6 +283,9 @@ func (s flowStatisticsService) updateRequestStatistics(event mm_pubsub.PickerEve
 					TotSessionRequests: updatedFlowStatistics.TotSessionRequests,
 					TotFeedback:        updatedFlowStatistics.TotFeedback,
 					AvgScore:           updatedFlowStatistics.AvgScore,
+					TotOneStarFeedback: updatedFlowStatistics.TotOneStarFeedback,
+					TotErrors:          updatedFlowStatistics.TotErrors,
+					TotAbandons:        updatedFlowStatistics.TotAbandons,
 					CreatedAt:          updatedFlowStatistics.CreatedAt,
 					UpdatedAt:          updatedFlowStatistics.UpdatedAt,
 				},
@@ -172,10 +306,14 @@ func (s flowStatisticsService) updateRequestStatistics(event mm_pubsub.PickerEve
 	return nil
 }
 
-func (s flowStatisticsService) updateFeedbackStatistics(event mm_pubsub.FeedbackEventEntity) error {
+func (s flowStatisticsService) updateFeedbackStatistics(ctx context.Context, event mm_pubsub.FeedbackEventEntity) error {
 	eventsToPublish := []mm_pubsub.EventToPublish{}
 	var updatedFlowStatistics flowStatisticsEntity
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
 		// Find the flow statistics
 		currentFlowStatistics, err := s.repository.getFlowStatisticsByFlowID(tx, event.FlowID, true)
 		if err != nil {
@@ -189,6 +327,86 @@ func (s flowStatisticsService) updateFeedbackStatistics(event mm_pubsub.Feedback
 		updatedFlowStatistics.TotFeedback++
 		newAvg := ((updatedFlowStatistics.AvgScore * float64(updatedFlowStatistics.TotFeedback-1)) + event.Score) / float64(updatedFlowStatistics.TotFeedback)
 		updatedFlowStatistics.AvgScore = *mm_utils.RoundTo2DecimalsPtr(&newAvg)
+		if mm_rsengine.IsOneStarScore(event.Score) {
+			updatedFlowStatistics.TotOneStarFeedback++
+		}
+		// And save
+		if _, err := s.repository.saveFlowStatistics(tx, updatedFlowStatistics, mm_db.Update); err != nil {
+			return err
+		}
+		// Track the feedback also in the hourly bucket, for windowed and decayed scores
+		if err := s.repository.incrementFlowStatisticsBucket(tx, flowStatisticsBucketEntity{
+			ID:              uuid.New(),
+			FlowID:          updatedFlowStatistics.FlowID,
+			UseCaseID:       updatedFlowStatistics.UseCaseID,
+			BucketStart:     event.CreatedAt.Truncate(time.Hour),
+			TotFeedback:     1,
+			SumScore:        event.Score,
+			SumSquaredScore: event.Score * event.Score,
+		}); err != nil {
+			return err
+		}
+		// Persist event
+		if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicFlowStatisticsV1, mm_pubsub.PubSubMessage{
+			Message: mm_pubsub.PubSubEvent{
+				EventID:   uuid.New(),
+				EventTime: time.Now(),
+				EventType: mm_pubsub.FlowStatisticsUpdatedEvent,
+				EventEntity: &mm_pubsub.FlowStatisticsEventEntity{
+					ID:                 updatedFlowStatistics.ID,
+					FlowID:             updatedFlowStatistics.FlowID,
+					UseCaseID:          updatedFlowStatistics.UseCaseID,
+					TotRequests:        updatedFlowStatistics.TotRequests,
+					TotSessionRequests: updatedFlowStatistics.TotSessionRequests,
+					TotFeedback:        updatedFlowStatistics.TotFeedback,
+					AvgScore:           updatedFlowStatistics.AvgScore,
+					TotOneStarFeedback: updatedFlowStatistics.TotOneStarFeedback,
+					TotErrors:          updatedFlowStatistics.TotErrors,
+					TotAbandons:        updatedFlowStatistics.TotAbandons,
+					CreatedAt:          updatedFlowStatistics.CreatedAt,
+					UpdatedAt:          updatedFlowStatistics.UpdatedAt,
+				},
+				EventChangedFields: mm_utils.DiffStructs(currentFlowStatistics, updatedFlowStatistics),
+			},
+		}); err != nil {
+			return err
+		} else {
+			eventsToPublish = append(eventsToPublish, event)
+		}
+		return nil
+	})
+	if errTransaction != nil {
+		return errTransaction
+	} else {
+		s.pubSubAgent.PublishBulk(eventsToPublish)
+	}
+	return nil
+}
+
+func (s flowStatisticsService) updateOutcomeStatistics(ctx context.Context, event mm_pubsub.SessionOutcomeEventEntity) error {
+	eventsToPublish := []mm_pubsub.EventToPublish{}
+	var updatedFlowStatistics flowStatisticsEntity
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
+		// Find the flow statistics
+		currentFlowStatistics, err := s.repository.getFlowStatisticsByFlowID(tx, event.FlowID, true)
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		if mm_utils.IsEmpty(currentFlowStatistics) {
+			return errFlowStatisticsNotFound
+		}
+		// Update statistics
+		updatedFlowStatistics = currentFlowStatistics
+		switch event.Outcome {
+		case mm_pubsub.SessionOutcomeError:
+			updatedFlowStatistics.TotErrors++
+		case mm_pubsub.SessionOutcomeAbandoned:
+			updatedFlowStatistics.TotAbandons++
+		}
 		// And save
 		if _, err := s.repository.saveFlowStatistics(tx, updatedFlowStatistics, mm_db.Update); err != nil {
 			return err
@@ -207,6 +425,9 @@ func (s flowStatisticsService) updateFeedbackStatistics(event mm_pubsub.Feedback
 					TotSessionRequests: updatedFlowStatistics.TotSessionRequests,
 					TotFeedback:        updatedFlowStatistics.TotFeedback,
 					AvgScore:           updatedFlowStatistics.AvgScore,
+					TotOneStarFeedback: updatedFlowStatistics.TotOneStarFeedback,
+					TotErrors:          updatedFlowStatistics.TotErrors,
+					TotAbandons:        updatedFlowStatistics.TotAbandons,
 					CreatedAt:          updatedFlowStatistics.CreatedAt,
 					UpdatedAt:          updatedFlowStatistics.UpdatedAt,
 				},
@@ -227,7 +448,13 @@ func (s flowStatisticsService) updateFeedbackStatistics(event mm_pubsub.Feedback
 	return nil
 }
 
-func (s flowStatisticsService) cleanupStatistics(event mm_pubsub.RolloutStrategyEventEntity) error {
-	// If needed, send a new cleanup event for each Flow Statistics impacted
-	return s.repository.cleanupFlowStatisticsByUseCaseId(s.storage, event.UseCaseID)
+func (s flowStatisticsService) cleanupStatistics(ctx context.Context, event mm_pubsub.RolloutStrategyEventEntity) error {
+	return s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
+		// If needed, send a new cleanup event for each Flow Statistics impacted
+		return s.repository.cleanupFlowStatisticsByUseCaseId(tx, event.UseCaseID)
+	})
 }
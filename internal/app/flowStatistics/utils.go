@@ -0,0 +1,15 @@
+package flowStatistics
+
+import "github.com/google/uuid"
+
+/*
+Return the serve PCT of the Flow in the allocation. Flows not included are not active, so they are not served.
+*/
+func allocationServePct(allocation flowAllocationEntity, flowID uuid.UUID) float64 {
+	for _, f := range allocation.Flows {
+		if f.FlowID == flowID {
+			return f.CurrentServePct
+		}
+	}
+	return 0
+}
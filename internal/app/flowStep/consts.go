@@ -0,0 +1,17 @@
+package flowStep
+
+/*
+Author of the versions created by the application, e.g. when new Flow Steps are created for a Flow
+*/
+const systemAuthor = "system"
+
+/*
+Operation applied on a value of the configuration between two versions of a Flow Step
+*/
+type configurationOperation string
+
+const (
+	configurationOperationAdded   configurationOperation = "ADDED"
+	configurationOperationRemoved configurationOperation = "REMOVED"
+	configurationOperationChanged configurationOperation = "CHANGED"
+)
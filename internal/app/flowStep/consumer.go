@@ -25,7 +25,7 @@ func newFlowStepConsumer(pubSub *mm_pubsub.PubSubAgent, service flowStepServiceI
 
 func (r flowStepConsumer) subscribe() {
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicUseCaseStepV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicUseCaseStepV1, "flow-step")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -58,6 +58,7 @@ func (r flowStepConsumer) subscribe() {
 				// Create any missing FLow step compared to Use Case steps
 				if err := r.service.createStepsForAllFlowsOfUseCase(event.UseCaseID); err != nil {
 					zap.L().Error("Impossible to create all flowSteps for the new Use Case Step", zap.String("service", "flow-step-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
@@ -65,7 +66,7 @@ func (r flowStepConsumer) subscribe() {
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowV1, "flow-step")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -100,12 +101,14 @@ func (r flowStepConsumer) subscribe() {
 				if event.ClonedFromID != nil {
 					if err := r.service.cloneStepsFromFlow(event.ID, *event.ClonedFromID); err != nil {
 						zap.L().Error("Impossible to clone all flowSteps for the new cloned Flow", zap.String("service", "flow-step-consumer"))
+						msg.Message.EventState.Fail(err)
 						return
 					}
 				}
 				// Create any missing FLow step compared to Use Case steps
 				if err := r.service.createStepsForAllFlowsOfUseCase(event.UseCaseID); err != nil {
 					zap.L().Error("Impossible to create all flowSteps for the new Flow", zap.String("service", "flow-step-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
@@ -32,6 +32,7 @@ func (r getFlowStepInputDto) validate() error {
 type updateFlowStepInputDto struct {
 	ID            string       `uri:"flowStepId"`
 	Configuration aiRequestDTO `json:"configuration"`
+	Note          *string      `json:"note"`
 }
 
 func (r updateFlowStepInputDto) validate() error {
@@ -40,5 +41,70 @@ func (r updateFlowStepInputDto) validate() error {
 		validation.Field(&r.Configuration, validation.Required, validation.By(func(v interface{}) error {
 			return v.(aiRequestDTO).validate()
 		})),
+		validation.Field(&r.Note, validation.NilOrNotEmpty, validation.Length(1, 1000)),
+	)
+}
+
+type listFlowStepVersionsInputDto struct {
+	FlowStepID string `uri:"flowStepId"`
+	Page       int    `form:"page"`
+	PageSize   int    `form:"pageSize"`
+}
+
+func (r listFlowStepVersionsInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FlowStepID, validation.Required, is.UUID),
+		validation.Field(&r.Page, validation.Required, validation.Min(1)),
+		validation.Field(&r.PageSize, validation.Required, validation.Min(1), validation.Max(200)),
+	)
+}
+
+type getFlowStepVersionInputDto struct {
+	FlowStepID string `uri:"flowStepId"`
+	Version    int64  `uri:"version"`
+}
+
+func (r getFlowStepVersionInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FlowStepID, validation.Required, is.UUID),
+		validation.Field(&r.Version, validation.Required, validation.Min(int64(1))),
+	)
+}
+
+type getFlowStepVersionStatisticsInputDto struct {
+	FlowStepID string `uri:"flowStepId"`
+}
+
+func (r getFlowStepVersionStatisticsInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FlowStepID, validation.Required, is.UUID),
+	)
+}
+
+type diffFlowStepVersionsInputDto struct {
+	FlowStepID  string `uri:"flowStepId"`
+	FromVersion int64  `form:"from"`
+	ToVersion   int64  `form:"to"`
+}
+
+func (r diffFlowStepVersionsInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FlowStepID, validation.Required, is.UUID),
+		validation.Field(&r.FromVersion, validation.Required, validation.Min(int64(1))),
+		validation.Field(&r.ToVersion, validation.Required, validation.Min(int64(1))),
+	)
+}
+
+type restoreFlowStepVersionInputDto struct {
+	FlowStepID string  `uri:"flowStepId"`
+	Version    int64   `uri:"version"`
+	Note       *string `json:"note"`
+}
+
+func (r restoreFlowStepVersionInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FlowStepID, validation.Required, is.UUID),
+		validation.Field(&r.Version, validation.Required, validation.Min(int64(1))),
+		validation.Field(&r.Note, validation.NilOrNotEmpty, validation.Length(1, 1000)),
 	)
 }
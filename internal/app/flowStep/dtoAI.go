@@ -2,18 +2,46 @@ package flowStep
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 
+	"github.com/ai-model-match/backend/internal/pkg/mm_provider"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type aiRequestDTO struct {
 	Modality   string           `json:"modality"`
+	Provider   string           `json:"provider,omitempty"`
 	Parameters *json.RawMessage `json:"parameters,omitempty"`
 }
 
 func (r aiRequestDTO) validate() error {
-	return validation.ValidateStruct(&r,
+	if err := validation.ValidateStruct(&r,
 		validation.Field(&r.Modality, validation.Required),
+		validation.Field(&r.Provider, validation.When(r.Provider != "", validation.By(func(value interface{}) error {
+			if !mm_provider.IsSupported(r.Modality, r.Provider) {
+				return errors.New("not supported for the modality")
+			}
+			return nil
+		}))),
 		validation.Field(&r.Parameters, validation.Required),
+	); err != nil {
+		return err
+	}
+	// Without a provider, parameters are stored as they are
+	if r.Provider == "" {
+		return nil
+	}
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Parameters, validation.By(func(value interface{}) error {
+			violations, err := mm_provider.Validate(r.Modality, r.Provider, *r.Parameters)
+			if err != nil {
+				return err
+			}
+			if len(violations) > 0 {
+				return errors.New(strings.Join(violations, "; "))
+			}
+			return nil
+		})),
 	)
 }
@@ -1,6 +1,10 @@
 package flowStep
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_provider"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/google/uuid"
 )
@@ -12,3 +16,46 @@ type missingFlowStepEntity struct {
 	UseCaseID     uuid.UUID `json:"useCaseId"`
 	UseCaseStepID uuid.UUID `json:"useCaseStepId"`
 }
+
+type useCaseStepEntity struct {
+	ID        uuid.UUID
+	UseCaseID uuid.UUID
+	Position  int64
+	Variables json.RawMessage
+}
+
+type configurationSchemaEntity mm_provider.Schema
+
+type flowStepVersionEntity struct {
+	ID            uuid.UUID       `json:"id"`
+	FlowStepID    uuid.UUID       `json:"flowStepId"`
+	Version       int64           `json:"version"`
+	Configuration json.RawMessage `json:"configuration"`
+	Placeholders  json.RawMessage `json:"placeholders"`
+	Author        string          `json:"author"`
+	Note          string          `json:"note"`
+	RestoredFrom  *int64          `json:"restoredFrom"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+type flowStepVersionStatisticsEntity struct {
+	Version     int64   `json:"version"`
+	TotRequests int64   `json:"totRequests"`
+	TotSessions int64   `json:"totSessions"`
+	TotFeedback int64   `json:"totFeedback"`
+	AvgScore    float64 `json:"avgScore"`
+}
+
+type flowStepVersionDiffEntity struct {
+	FlowStepID  uuid.UUID                   `json:"flowStepId"`
+	FromVersion int64                       `json:"fromVersion"`
+	ToVersion   int64                       `json:"toVersion"`
+	Changes     []configurationChangeEntity `json:"changes"`
+}
+
+type configurationChangeEntity struct {
+	Path      string                 `json:"path"`
+	Operation configurationOperation `json:"operation"`
+	From      any                    `json:"from,omitempty"`
+	To        any                    `json:"to,omitempty"`
+}
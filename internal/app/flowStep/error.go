@@ -5,3 +5,6 @@ import "errors"
 var errFlowNotFound = errors.New("flow-not-found")
 var errFlowStepNotFound = errors.New("flow-step-not-found")
 var errFlowStepWrongConfigFormat = errors.New("flow-step-wrong-config-format")
+var errFlowStepUndeclaredPlaceholders = errors.New("flow-step-undeclared-placeholders")
+var errFlowStepVersionNotFound = errors.New("flow-step-version-not-found")
+var errFlowStepVersionAlreadyCurrent = errors.New("flow-step-version-already-current")
@@ -17,15 +17,20 @@ func (m flowModel) TableName() string {
 }
 
 type useCaseStepModel struct {
-	ID        uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
-	UseCaseID uuid.UUID `gorm:"column:use_case_id;type:varchar(36)"`
-	Position  int64     `gorm:"column:position;type:bigint"`
+	ID        uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	UseCaseID uuid.UUID       `gorm:"column:use_case_id;type:varchar(36)"`
+	Position  int64           `gorm:"column:position;type:bigint"`
+	Variables json.RawMessage `gorm:"column:variables;type:json"`
 }
 
 func (m useCaseStepModel) TableName() string {
 	return "mm_use_case_step"
 }
 
+func (m useCaseStepModel) toEntity() useCaseStepEntity {
+	return useCaseStepEntity(m)
+}
+
 type flowStepModel struct {
 	ID            uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
 	FlowID        uuid.UUID       `gorm:"column:flow_id;type:varchar(36)"`
@@ -33,6 +38,7 @@ type flowStepModel struct {
 	UseCaseStepID uuid.UUID       `gorm:"column:use_case_step_id;type:varchar(36)"`
 	Configuration json.RawMessage `gorm:"column:configuration;type:json"`
 	Placeholders  json.RawMessage `gorm:"column:placeholders;type:json"`
+	Version       int64           `gorm:"column:version;type:bigint"`
 	CreatedAt     time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
 	UpdatedAt     time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime:false"`
 }
@@ -58,3 +64,35 @@ func (m missingFlowStepModel) TableName() string {
 func (m missingFlowStepModel) toEntity() missingFlowStepEntity {
 	return missingFlowStepEntity(m)
 }
+
+type flowStepVersionModel struct {
+	ID            uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	FlowStepID    uuid.UUID       `gorm:"column:flow_step_id;type:varchar(36)"`
+	Version       int64           `gorm:"column:version;type:bigint"`
+	Configuration json.RawMessage `gorm:"column:configuration;type:json"`
+	Placeholders  json.RawMessage `gorm:"column:placeholders;type:json"`
+	Author        string          `gorm:"column:author;type:varchar(255)"`
+	Note          string          `gorm:"column:note;type:text"`
+	RestoredFrom  *int64          `gorm:"column:restored_from;type:bigint"`
+	CreatedAt     time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
+}
+
+func (m flowStepVersionModel) TableName() string {
+	return "mm_flow_step_version"
+}
+
+func (m flowStepVersionModel) toEntity() flowStepVersionEntity {
+	return flowStepVersionEntity(m)
+}
+
+type flowStepVersionStatisticsModel struct {
+	Version     int64   `gorm:"column:version"`
+	TotRequests int64   `gorm:"column:tot_requests"`
+	TotSessions int64   `gorm:"column:tot_sessions"`
+	TotFeedback int64   `gorm:"column:tot_feedback"`
+	AvgScore    float64 `gorm:"column:avg_score"`
+}
+
+func (m flowStepVersionStatisticsModel) toEntity() flowStepVersionStatisticsEntity {
+	return flowStepVersionStatisticsEntity(m)
+}
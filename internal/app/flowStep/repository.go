@@ -13,12 +13,17 @@ import (
 type flowStepRepositoryInterface interface {
 	checkFlowExists(tx *gorm.DB, flowID uuid.UUID) (bool, error)
 	checkUseCaseStepExists(tx *gorm.DB, useCaseStepID uuid.UUID) (bool, error)
+	getUseCaseStepByID(tx *gorm.DB, useCaseStepID uuid.UUID) (useCaseStepEntity, error)
 	listFlowSteps(tx *gorm.DB, flowID uuid.UUID, limit int, offset int, forUpdate bool) ([]flowStepEntity, int64, error)
 	getFlowStepByID(tx *gorm.DB, flowStepID uuid.UUID, forUpdate bool) (flowStepEntity, error)
 	getFlowStepByFlowIDAndUseCaseStepID(tx *gorm.DB, flowID uuid.UUID, useCaseStepID uuid.UUID, forUpdate bool) (flowStepEntity, error)
 	saveFlowStep(tx *gorm.DB, flowStep flowStepEntity, operation mm_db.SaveOperation) (flowStepEntity, error)
 	getAllMissingFlowSteps(tx *gorm.DB, useCaseID uuid.UUID) ([]missingFlowStepEntity, error)
 	cloneFlowSteps(tx *gorm.DB, clonedFlowID uuid.UUID, newFlowID uuid.UUID) ([]flowStepEntity, error)
+	listFlowStepVersions(tx *gorm.DB, flowStepID uuid.UUID, limit int, offset int) ([]flowStepVersionEntity, int64, error)
+	getFlowStepVersion(tx *gorm.DB, flowStepID uuid.UUID, version int64) (flowStepVersionEntity, error)
+	saveFlowStepVersion(tx *gorm.DB, flowStepVersion flowStepVersionEntity) (flowStepVersionEntity, error)
+	getFlowStepVersionStatistics(tx *gorm.DB, flowStepID uuid.UUID) ([]flowStepVersionStatisticsEntity, error)
 }
 
 type flowStepRepository struct {
@@ -54,6 +59,19 @@ func (r flowStepRepository) checkUseCaseStepExists(tx *gorm.DB, useCaseStepID uu
 	return true, nil
 }
 
+func (r flowStepRepository) getUseCaseStepByID(tx *gorm.DB, useCaseStepID uuid.UUID) (useCaseStepEntity, error) {
+	var model *useCaseStepModel
+	query := tx.Where("id = ?", useCaseStepID)
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return useCaseStepEntity{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return useCaseStepEntity{}, nil
+	}
+	return model.toEntity(), nil
+}
+
 func (r flowStepRepository) listFlowSteps(tx *gorm.DB, flowID uuid.UUID, limit int, offset int, forUpdate bool) ([]flowStepEntity, int64, error) {
 	var totalCount int64
 	// Retrieve Flow Steps keeping the order of Use Case Steps
@@ -187,6 +205,7 @@ func (r flowStepRepository) cloneFlowSteps(tx *gorm.DB, clonedFlowID uuid.UUID,
 			UseCaseStepID: s.UseCaseStepID,
 			Configuration: s.Configuration,
 			Placeholders:  s.Placeholders,
+			Version:       1,
 			CreatedAt:     now,
 			UpdatedAt:     now,
 		}
@@ -201,3 +220,84 @@ func (r flowStepRepository) cloneFlowSteps(tx *gorm.DB, clonedFlowID uuid.UUID,
 	}
 	return newStepEntities, nil
 }
+
+func (r flowStepRepository) listFlowStepVersions(tx *gorm.DB, flowStepID uuid.UUID, limit int, offset int) ([]flowStepVersionEntity, int64, error) {
+	var totalCount int64
+	var models []*flowStepVersionModel
+	query := tx.Where("flow_step_id = ?", flowStepID).Order("version DESC")
+	queryCount := tx.Model(flowStepVersionModel{}).Where("flow_step_id = ?", flowStepID)
+	result := query.Limit(limit).Offset(offset).Find(&models)
+	queryCount.Count(&totalCount)
+	if result.Error != nil {
+		return []flowStepVersionEntity{}, 0, result.Error
+	}
+	var entities []flowStepVersionEntity = []flowStepVersionEntity{}
+	for _, model := range models {
+		entity := model.toEntity()
+		entities = append(entities, entity)
+	}
+	return entities, totalCount, nil
+}
+
+func (r flowStepRepository) getFlowStepVersion(tx *gorm.DB, flowStepID uuid.UUID, version int64) (flowStepVersionEntity, error) {
+	var model *flowStepVersionModel
+	query := tx.Where("flow_step_id = ?", flowStepID).Where("version = ?", version)
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return flowStepVersionEntity{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return flowStepVersionEntity{}, nil
+	}
+	return model.toEntity(), nil
+}
+
+func (r flowStepRepository) saveFlowStepVersion(tx *gorm.DB, flowStepVersion flowStepVersionEntity) (flowStepVersionEntity, error) {
+	// Versions are immutable, so they can only be created
+	var model = flowStepVersionModel(flowStepVersion)
+	if err := tx.Create(model).Error; err != nil {
+		return flowStepVersionEntity{}, err
+	}
+	return flowStepVersion, nil
+}
+
+func (r flowStepRepository) getFlowStepVersionStatistics(tx *gorm.DB, flowStepID uuid.UUID) ([]flowStepVersionStatisticsEntity, error) {
+	var models []flowStepVersionStatisticsModel
+	// Feedback is sent per session, so it is attributed to each version served within the session
+	query := `
+		SELECT
+			v.version AS version,
+			COALESCE(r.tot_requests, 0) AS tot_requests,
+			COALESCE(r.tot_sessions, 0) AS tot_sessions,
+			COALESCE(f.tot_feedback, 0) AS tot_feedback,
+			COALESCE(f.avg_score, 0) AS avg_score
+		FROM mm_flow_step_version v
+		LEFT JOIN (
+			SELECT flow_step_version AS version, COUNT(*) AS tot_requests, COUNT(DISTINCT correlation_id) AS tot_sessions
+			FROM mm_picker_request
+			WHERE flow_step_id = ?
+			GROUP BY flow_step_version
+		) r ON r.version = v.version
+		LEFT JOIN (
+			SELECT s.version AS version, COUNT(fb.id) AS tot_feedback, AVG(fb.score) AS avg_score
+			FROM (
+				SELECT DISTINCT correlation_id, flow_step_version AS version
+				FROM mm_picker_request
+				WHERE flow_step_id = ?
+			) s
+			JOIN mm_feedback fb ON fb.correlation_id = s.correlation_id
+			GROUP BY s.version
+		) f ON f.version = v.version
+		WHERE v.flow_step_id = ?
+		ORDER BY v.version DESC
+	`
+	if err := tx.Raw(query, flowStepID, flowStepID, flowStepID).Scan(&models).Error; err != nil {
+		return nil, err
+	}
+	var entities []flowStepVersionStatisticsEntity = []flowStepVersionStatisticsEntity{}
+	for _, model := range models {
+		entity := model.toEntity()
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
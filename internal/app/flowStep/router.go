@@ -1,6 +1,7 @@
 package flowStep
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_auth"
@@ -57,6 +58,22 @@ func (r flowStepRouter) register(router *gin.RouterGroup) {
 			mm_router.ReturnOk(ctx, &gin.H{"items": items, "totalCount": totalCount, "hasNext": mm_router.HasNext(request.Page, request.PageSize, totalCount)})
 		})
 
+	router.GET(
+		"/flow-step-schemas",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Business Logic
+			items, err := r.service.listConfigurationSchemas(ctx)
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-step-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"items": items})
+		})
+
 	router.GET(
 		"/flow-steps/:flowStepId",
 		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
@@ -112,6 +129,176 @@ func (r flowStepRouter) register(router *gin.RouterGroup) {
 				mm_router.ReturnBadRequestError(ctx, err)
 				return
 			}
+			if errors.Is(err, errFlowStepUndeclaredPlaceholders) {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-step-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
+
+	router.GET(
+		"/flow-steps/:flowStepId/versions",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request listFlowStepVersionsInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			items, totalCount, err := r.service.listFlowStepVersions(ctx, request)
+			if err == errFlowStepNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-step-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"items": items, "totalCount": totalCount, "hasNext": mm_router.HasNext(request.Page, request.PageSize, totalCount)})
+		})
+
+	router.GET(
+		"/flow-steps/:flowStepId/versions/statistics",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request getFlowStepVersionStatisticsInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			items, err := r.service.getFlowStepVersionStatistics(ctx, request)
+			if err == errFlowStepNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-step-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"items": items})
+		})
+
+	router.GET(
+		"/flow-steps/:flowStepId/versions/diff",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request diffFlowStepVersionsInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.diffFlowStepVersions(ctx, request)
+			if err == errFlowStepVersionNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-step-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
+
+	router.GET(
+		"/flow-steps/:flowStepId/versions/:version",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request getFlowStepVersionInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.getFlowStepVersion(ctx, request)
+			if err == errFlowStepVersionNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-step-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
+
+	router.POST(
+		"/flow-steps/:flowStepId/versions/:version/restore",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ, mm_auth.WRITE}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request restoreFlowStepVersionInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.restoreFlowStepVersion(ctx, request)
+			if err == errFlowStepNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			if err == errFlowStepVersionNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			if err == errFlowStepVersionAlreadyCurrent {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
+			if errors.Is(err, errFlowStepWrongConfigFormat) {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
+			if errors.Is(err, errFlowStepUndeclaredPlaceholders) {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
 			// Errors and output handler
 			if err != nil {
 				zap.L().Error("Something went wrong", zap.String("service", "flow-step-router"), zap.Error(err))
@@ -2,12 +2,15 @@ package flowStep
 
 import (
 	"encoding/json"
-	"regexp"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_err"
+	"github.com/ai-model-match/backend/internal/pkg/mm_provider"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_template"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -18,6 +21,12 @@ type flowStepServiceInterface interface {
 	listFlowSteps(ctx *gin.Context, input ListFlowStepsInputDto) ([]flowStepEntity, int64, error)
 	getFlowStepByID(ctx *gin.Context, input getFlowStepInputDto) (flowStepEntity, error)
 	updateFlowStep(ctx *gin.Context, input updateFlowStepInputDto) (flowStepEntity, error)
+	listFlowStepVersions(ctx *gin.Context, input listFlowStepVersionsInputDto) ([]flowStepVersionEntity, int64, error)
+	getFlowStepVersion(ctx *gin.Context, input getFlowStepVersionInputDto) (flowStepVersionEntity, error)
+	getFlowStepVersionStatistics(ctx *gin.Context, input getFlowStepVersionStatisticsInputDto) ([]flowStepVersionStatisticsEntity, error)
+	diffFlowStepVersions(ctx *gin.Context, input diffFlowStepVersionsInputDto) (flowStepVersionDiffEntity, error)
+	restoreFlowStepVersion(ctx *gin.Context, input restoreFlowStepVersionInputDto) (flowStepEntity, error)
+	listConfigurationSchemas(ctx *gin.Context) ([]configurationSchemaEntity, error)
 	createStepsForAllFlowsOfUseCase(useCaseID uuid.UUID) error
 	cloneStepsFromFlow(newFlowID uuid.UUID, clonedFlowID uuid.UUID) error
 }
@@ -66,7 +75,6 @@ func (s flowStepService) getFlowStepByID(ctx *gin.Context, input getFlowStepInpu
 }
 
 func (s flowStepService) updateFlowStep(ctx *gin.Context, input updateFlowStepInputDto) (flowStepEntity, error) {
-	now := time.Now()
 	var updatedFlowStep flowStepEntity
 	eventsToPublish := []mm_pubsub.EventToPublish{}
 	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
@@ -77,52 +85,131 @@ func (s flowStepService) updateFlowStep(ctx *gin.Context, input updateFlowStepIn
 			return mm_err.ErrGeneric
 		} else if mm_utils.IsEmpty(currentFlowStep) {
 			return errFlowStepNotFound
-		} else {
-			updatedFlowStep = currentFlowStep
 		}
-		if configuration, err := json.Marshal(input.Configuration); err != nil {
+		configuration, err := json.Marshal(input.Configuration)
+		if err != nil {
 			return errFlowStepWrongConfigFormat
-		} else {
-			updatedFlowStep.Configuration = configuration
-		}
-		// Find placeholders to store
-		placeholders := []string{}
-		re := regexp.MustCompile(`\\u003c\\u003c([A-Za-z0-9_-]+)\\u003e\\u003e`)
-		matches := re.FindAllStringSubmatch(string(updatedFlowStep.Configuration), -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				placeholders = append(placeholders, match[1])
-			}
 		}
-		pl, _ := json.Marshal(placeholders)
-		updatedFlowStep.Placeholders = json.RawMessage(pl)
-		updatedFlowStep.UpdatedAt = now
-		if _, err := s.repository.saveFlowStep(tx, updatedFlowStep, mm_db.Update); err != nil {
+		note := ""
+		if input.Note != nil {
+			note = *input.Note
+		}
+		event, item, err := s.saveNewVersion(tx, currentFlowStep, configuration, getAuthor(ctx), note, nil)
+		if err != nil {
+			return err
+		}
+		updatedFlowStep = item
+		eventsToPublish = append(eventsToPublish, event)
+		return nil
+	})
+	if errTransaction != nil {
+		return flowStepEntity{}, errTransaction
+	} else {
+		s.pubSubAgent.PublishBulk(eventsToPublish)
+	}
+	return updatedFlowStep, nil
+}
+
+func (s flowStepService) listFlowStepVersions(ctx *gin.Context, input listFlowStepVersionsInputDto) ([]flowStepVersionEntity, int64, error) {
+	flowStepID := uuid.MustParse(input.FlowStepID)
+	if flowStep, err := s.repository.getFlowStepByID(s.storage, flowStepID, false); err != nil {
+		return []flowStepVersionEntity{}, 0, mm_err.ErrGeneric
+	} else if mm_utils.IsEmpty(flowStep) {
+		return []flowStepVersionEntity{}, 0, errFlowStepNotFound
+	}
+	limit, offset := mm_utils.PagePageSizeToLimitOffset(input.Page, input.PageSize)
+	items, totalCount, err := s.repository.listFlowStepVersions(s.storage, flowStepID, limit, offset)
+	if err != nil {
+		return []flowStepVersionEntity{}, 0, mm_err.ErrGeneric
+	}
+	return items, totalCount, nil
+}
+
+func (s flowStepService) getFlowStepVersion(ctx *gin.Context, input getFlowStepVersionInputDto) (flowStepVersionEntity, error) {
+	flowStepID := uuid.MustParse(input.FlowStepID)
+	item, err := s.repository.getFlowStepVersion(s.storage, flowStepID, input.Version)
+	if err != nil {
+		return flowStepVersionEntity{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(item) {
+		return flowStepVersionEntity{}, errFlowStepVersionNotFound
+	}
+	return item, nil
+}
+
+func (s flowStepService) getFlowStepVersionStatistics(ctx *gin.Context, input getFlowStepVersionStatisticsInputDto) ([]flowStepVersionStatisticsEntity, error) {
+	flowStepID := uuid.MustParse(input.FlowStepID)
+	if flowStep, err := s.repository.getFlowStepByID(s.storage, flowStepID, false); err != nil {
+		return []flowStepVersionStatisticsEntity{}, mm_err.ErrGeneric
+	} else if mm_utils.IsEmpty(flowStep) {
+		return []flowStepVersionStatisticsEntity{}, errFlowStepNotFound
+	}
+	items, err := s.repository.getFlowStepVersionStatistics(s.storage, flowStepID)
+	if err != nil {
+		return []flowStepVersionStatisticsEntity{}, mm_err.ErrGeneric
+	}
+	return items, nil
+}
+
+func (s flowStepService) diffFlowStepVersions(ctx *gin.Context, input diffFlowStepVersionsInputDto) (flowStepVersionDiffEntity, error) {
+	flowStepID := uuid.MustParse(input.FlowStepID)
+	fromVersion, err := s.repository.getFlowStepVersion(s.storage, flowStepID, input.FromVersion)
+	if err != nil {
+		return flowStepVersionDiffEntity{}, mm_err.ErrGeneric
+	} else if mm_utils.IsEmpty(fromVersion) {
+		return flowStepVersionDiffEntity{}, errFlowStepVersionNotFound
+	}
+	toVersion, err := s.repository.getFlowStepVersion(s.storage, flowStepID, input.ToVersion)
+	if err != nil {
+		return flowStepVersionDiffEntity{}, mm_err.ErrGeneric
+	} else if mm_utils.IsEmpty(toVersion) {
+		return flowStepVersionDiffEntity{}, errFlowStepVersionNotFound
+	}
+	changes, err := diffConfigurations(fromVersion.Configuration, toVersion.Configuration)
+	if err != nil {
+		return flowStepVersionDiffEntity{}, mm_err.ErrGeneric
+	}
+	return flowStepVersionDiffEntity{
+		FlowStepID:  flowStepID,
+		FromVersion: fromVersion.Version,
+		ToVersion:   toVersion.Version,
+		Changes:     changes,
+	}, nil
+}
+
+func (s flowStepService) restoreFlowStepVersion(ctx *gin.Context, input restoreFlowStepVersionInputDto) (flowStepEntity, error) {
+	var updatedFlowStep flowStepEntity
+	eventsToPublish := []mm_pubsub.EventToPublish{}
+	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+		flowStepID := uuid.MustParse(input.FlowStepID)
+		currentFlowStep, err := s.repository.getFlowStepByID(tx, flowStepID, true)
+		if err != nil {
 			return mm_err.ErrGeneric
-		} else if updatedFlowStep, err = s.repository.getFlowStepByID(tx, updatedFlowStep.ID, false); err != nil {
+		} else if mm_utils.IsEmpty(currentFlowStep) {
+			return errFlowStepNotFound
+		}
+		version, err := s.repository.getFlowStepVersion(tx, flowStepID, input.Version)
+		if err != nil {
 			return mm_err.ErrGeneric
-		} else if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicFlowStepV1, mm_pubsub.PubSubMessage{
-			Message: mm_pubsub.PubSubEvent{
-				EventID:   uuid.New(),
-				EventTime: time.Now(),
-				EventType: mm_pubsub.FlowStepUpdatedEvent,
-				EventEntity: &mm_pubsub.FlowStepEventEntity{
-					ID:            updatedFlowStep.ID,
-					FlowID:        updatedFlowStep.FlowID,
-					UseCaseID:     updatedFlowStep.UseCaseID,
-					UseCaseStepID: updatedFlowStep.UseCaseStepID,
-					Configuration: updatedFlowStep.Configuration,
-					Placeholders:  updatedFlowStep.Placeholders,
-					CreatedAt:     updatedFlowStep.CreatedAt,
-					UpdatedAt:     updatedFlowStep.UpdatedAt,
-				},
-				EventChangedFields: mm_utils.DiffStructs(currentFlowStep, updatedFlowStep),
-			},
-		}); err != nil {
+		} else if mm_utils.IsEmpty(version) {
+			return errFlowStepVersionNotFound
+		} else if version.Version == currentFlowStep.Version {
+			return errFlowStepVersionAlreadyCurrent
+		}
+		// The provider schemas may have changed since the version was created
+		if err := validateProviderParameters(version.Configuration); err != nil {
 			return err
-		} else {
-			eventsToPublish = append(eventsToPublish, event)
 		}
+		note := fmt.Sprintf("Restored from version %d", version.Version)
+		if input.Note != nil {
+			note = *input.Note
+		}
+		event, item, err := s.saveNewVersion(tx, currentFlowStep, version.Configuration, getAuthor(ctx), note, &version.Version)
+		if err != nil {
+			return err
+		}
+		updatedFlowStep = item
+		eventsToPublish = append(eventsToPublish, event)
 		return nil
 	})
 	if errTransaction != nil {
@@ -133,6 +220,87 @@ func (s flowStepService) updateFlowStep(ctx *gin.Context, input updateFlowStepIn
 	return updatedFlowStep, nil
 }
 
+/*
+Replace the configuration of the Flow Step storing it as a new immutable version, and persist the event of the update.
+*/
+func (s flowStepService) saveNewVersion(tx *gorm.DB, currentFlowStep flowStepEntity, configuration json.RawMessage, author string, note string, restoredFrom *int64) (mm_pubsub.EventToPublish, flowStepEntity, error) {
+	now := time.Now()
+	updatedFlowStep := currentFlowStep
+	updatedFlowStep.Configuration = configuration
+	// Find placeholders to store
+	placeholders, err := mm_template.Placeholders(updatedFlowStep.Configuration)
+	if err != nil {
+		return mm_pubsub.EventToPublish{}, flowStepEntity{}, errFlowStepWrongConfigFormat
+	}
+	// When the Use Case Step declares its variables, the configuration can use only them
+	useCaseStep, err := s.repository.getUseCaseStepByID(tx, updatedFlowStep.UseCaseStepID)
+	if err != nil {
+		return mm_pubsub.EventToPublish{}, flowStepEntity{}, mm_err.ErrGeneric
+	}
+	schema, err := mm_template.ParseSchema(useCaseStep.Variables)
+	if err != nil {
+		return mm_pubsub.EventToPublish{}, flowStepEntity{}, mm_err.ErrGeneric
+	}
+	if len(schema) > 0 {
+		if undeclared := mm_template.UndeclaredPlaceholders(schema, placeholders); len(undeclared) > 0 {
+			return mm_pubsub.EventToPublish{}, flowStepEntity{}, fmt.Errorf("%w: %s", errFlowStepUndeclaredPlaceholders, strings.Join(undeclared, ", "))
+		}
+	}
+	pl, _ := json.Marshal(placeholders)
+	updatedFlowStep.Placeholders = json.RawMessage(pl)
+	updatedFlowStep.Version = currentFlowStep.Version + 1
+	updatedFlowStep.UpdatedAt = now
+	newVersion := flowStepVersionEntity{
+		ID:            uuid.New(),
+		FlowStepID:    updatedFlowStep.ID,
+		Version:       updatedFlowStep.Version,
+		Configuration: updatedFlowStep.Configuration,
+		Placeholders:  updatedFlowStep.Placeholders,
+		Author:        author,
+		Note:          note,
+		RestoredFrom:  restoredFrom,
+		CreatedAt:     now,
+	}
+	if _, err := s.repository.saveFlowStep(tx, updatedFlowStep, mm_db.Update); err != nil {
+		return mm_pubsub.EventToPublish{}, flowStepEntity{}, mm_err.ErrGeneric
+	} else if _, err := s.repository.saveFlowStepVersion(tx, newVersion); err != nil {
+		return mm_pubsub.EventToPublish{}, flowStepEntity{}, mm_err.ErrGeneric
+	} else if updatedFlowStep, err = s.repository.getFlowStepByID(tx, updatedFlowStep.ID, false); err != nil {
+		return mm_pubsub.EventToPublish{}, flowStepEntity{}, mm_err.ErrGeneric
+	}
+	event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicFlowStepV1, mm_pubsub.PubSubMessage{
+		Message: mm_pubsub.PubSubEvent{
+			EventID:   uuid.New(),
+			EventTime: time.Now(),
+			EventType: mm_pubsub.FlowStepUpdatedEvent,
+			EventEntity: &mm_pubsub.FlowStepEventEntity{
+				ID:            updatedFlowStep.ID,
+				FlowID:        updatedFlowStep.FlowID,
+				UseCaseID:     updatedFlowStep.UseCaseID,
+				UseCaseStepID: updatedFlowStep.UseCaseStepID,
+				Configuration: updatedFlowStep.Configuration,
+				Placeholders:  updatedFlowStep.Placeholders,
+				Version:       updatedFlowStep.Version,
+				CreatedAt:     updatedFlowStep.CreatedAt,
+				UpdatedAt:     updatedFlowStep.UpdatedAt,
+			},
+			EventChangedFields: mm_utils.DiffStructs(currentFlowStep, updatedFlowStep),
+		},
+	})
+	if err != nil {
+		return mm_pubsub.EventToPublish{}, flowStepEntity{}, err
+	}
+	return event, updatedFlowStep, nil
+}
+
+func (s flowStepService) listConfigurationSchemas(ctx *gin.Context) ([]configurationSchemaEntity, error) {
+	items := []configurationSchemaEntity{}
+	for _, schema := range mm_provider.Schemas() {
+		items = append(items, configurationSchemaEntity(schema))
+	}
+	return items, nil
+}
+
 func (s flowStepService) createStepsForAllFlowsOfUseCase(useCaseID uuid.UUID) error {
 	now := time.Now()
 	eventsToPublish := []mm_pubsub.EventToPublish{}
@@ -151,12 +319,25 @@ func (s flowStepService) createStepsForAllFlowsOfUseCase(useCaseID uuid.UUID) er
 				UseCaseStepID: missingFlow.UseCaseStepID,
 				Configuration: json.RawMessage(config),
 				Placeholders:  json.RawMessage(placeholders),
+				Version:       1,
 				CreatedAt:     now,
 				UpdatedAt:     now,
 			}
 			if _, err = s.repository.saveFlowStep(tx, newFlowStep, mm_db.Create); err != nil {
 				return mm_err.ErrGeneric
 			}
+			if _, err = s.repository.saveFlowStepVersion(tx, flowStepVersionEntity{
+				ID:            uuid.New(),
+				FlowStepID:    newFlowStep.ID,
+				Version:       newFlowStep.Version,
+				Configuration: newFlowStep.Configuration,
+				Placeholders:  newFlowStep.Placeholders,
+				Author:        systemAuthor,
+				Note:          "Initial version",
+				CreatedAt:     now,
+			}); err != nil {
+				return mm_err.ErrGeneric
+			}
 			// Send an event of flowStep created
 			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicFlowStepV1, mm_pubsub.PubSubMessage{
 				Message: mm_pubsub.PubSubEvent{
@@ -170,6 +351,7 @@ func (s flowStepService) createStepsForAllFlowsOfUseCase(useCaseID uuid.UUID) er
 						UseCaseStepID: newFlowStep.UseCaseStepID,
 						Configuration: newFlowStep.Configuration,
 						Placeholders:  newFlowStep.Placeholders,
+						Version:       newFlowStep.Version,
 						CreatedAt:     newFlowStep.CreatedAt,
 						UpdatedAt:     newFlowStep.UpdatedAt,
 					},
@@ -209,6 +391,18 @@ func (s flowStepService) cloneStepsFromFlow(newFlowID uuid.UUID, clonedFlowID uu
 			return err
 		}
 		for _, clonedFlowStep := range clonedFlowSteps {
+			if _, err = s.repository.saveFlowStepVersion(tx, flowStepVersionEntity{
+				ID:            uuid.New(),
+				FlowStepID:    clonedFlowStep.ID,
+				Version:       clonedFlowStep.Version,
+				Configuration: clonedFlowStep.Configuration,
+				Placeholders:  clonedFlowStep.Placeholders,
+				Author:        systemAuthor,
+				Note:          fmt.Sprintf("Cloned from Flow %s", clonedFlowID),
+				CreatedAt:     clonedFlowStep.CreatedAt,
+			}); err != nil {
+				return mm_err.ErrGeneric
+			}
 			// Send an event of flowStep created
 			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicFlowStepV1, mm_pubsub.PubSubMessage{
 				Message: mm_pubsub.PubSubEvent{
@@ -222,6 +416,7 @@ func (s flowStepService) cloneStepsFromFlow(newFlowID uuid.UUID, clonedFlowID uu
 						UseCaseStepID: clonedFlowStep.UseCaseStepID,
 						Configuration: clonedFlowStep.Configuration,
 						Placeholders:  clonedFlowStep.Placeholders,
+						Version:       clonedFlowStep.Version,
 						CreatedAt:     clonedFlowStep.CreatedAt,
 						UpdatedAt:     clonedFlowStep.UpdatedAt,
 					},
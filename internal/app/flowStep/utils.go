@@ -0,0 +1,127 @@
+package flowStep
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_auth"
+	"github.com/ai-model-match/backend/internal/pkg/mm_provider"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Compare two configurations and return the values added, removed or changed, identified by their JSON Pointer.
+Objects are compared key by key and arrays item by item, while any other change is reported on the whole value.
+*/
+func diffConfigurations(from json.RawMessage, to json.RawMessage) ([]configurationChangeEntity, error) {
+	fromValue, err := decodeConfiguration(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := decodeConfiguration(to)
+	if err != nil {
+		return nil, err
+	}
+	changes := []configurationChangeEntity{}
+	diffValues("", fromValue, toValue, &changes)
+	return changes, nil
+}
+
+func diffValues(path string, from any, to any, changes *[]configurationChangeEntity) {
+	switch fromValue := from.(type) {
+	case map[string]any:
+		if toValue, ok := to.(map[string]any); ok {
+			keys := []string{}
+			for key := range fromValue {
+				keys = append(keys, key)
+			}
+			for key := range toValue {
+				if _, exists := fromValue[key]; !exists {
+					keys = append(keys, key)
+				}
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				childPath := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+				fromChild, inFrom := fromValue[key]
+				toChild, inTo := toValue[key]
+				switch {
+				case !inFrom:
+					*changes = append(*changes, configurationChangeEntity{Path: childPath, Operation: configurationOperationAdded, To: toChild})
+				case !inTo:
+					*changes = append(*changes, configurationChangeEntity{Path: childPath, Operation: configurationOperationRemoved, From: fromChild})
+				default:
+					diffValues(childPath, fromChild, toChild, changes)
+				}
+			}
+			return
+		}
+	case []any:
+		if toValue, ok := to.([]any); ok {
+			for i := 0; i < max(len(fromValue), len(toValue)); i++ {
+				childPath := path + "/" + strconv.Itoa(i)
+				switch {
+				case i >= len(fromValue):
+					*changes = append(*changes, configurationChangeEntity{Path: childPath, Operation: configurationOperationAdded, To: toValue[i]})
+				case i >= len(toValue):
+					*changes = append(*changes, configurationChangeEntity{Path: childPath, Operation: configurationOperationRemoved, From: fromValue[i]})
+				default:
+					diffValues(childPath, fromValue[i], toValue[i], changes)
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, configurationChangeEntity{Path: path, Operation: configurationOperationChanged, From: from, To: to})
+	}
+}
+
+/*
+Decode the configuration keeping numbers as they are, so that they are compared and returned without losing precision.
+*/
+func decodeConfiguration(configuration json.RawMessage) (any, error) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(configuration))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+/*
+Return the username of the authenticated user, as author of a new version.
+*/
+func getAuthor(ctx *gin.Context) string {
+	if user := mm_auth.GetAuthenticatedUserFromSession(ctx); user != nil && user.Username != "" {
+		return user.Username
+	}
+	return systemAuthor
+}
+
+/*
+Validate the parameters of a stored configuration against the schema of its provider, if declared.
+*/
+func validateProviderParameters(configuration json.RawMessage) error {
+	var request aiRequestDTO
+	if err := json.Unmarshal(configuration, &request); err != nil {
+		return errFlowStepWrongConfigFormat
+	}
+	if request.Provider == "" || request.Parameters == nil {
+		return nil
+	}
+	violations, err := mm_provider.Validate(request.Modality, request.Provider, *request.Parameters)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errFlowStepWrongConfigFormat, err.Error())
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: %s", errFlowStepWrongConfigFormat, strings.Join(violations, "; "))
+	}
+	return nil
+}
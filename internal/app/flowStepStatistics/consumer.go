@@ -25,7 +25,7 @@ func newFlowStepStatisticsConsumer(pubSub *mm_pubsub.PubSubAgent, service flowSt
 
 func (r flowStepStatisticsConsumer) subscribe() {
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowStepV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowStepV1, "flow-step-statistics")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -62,6 +62,7 @@ func (r flowStepStatisticsConsumer) subscribe() {
 						return
 					} else {
 						zap.L().Error("Impossible to create the flowStepStatistics for the new Flow Step", zap.String("service", "flow-step-statistics-consumer"))
+						msg.Message.EventState.Fail(err)
 						return
 					}
 				}
@@ -70,7 +71,7 @@ func (r flowStepStatisticsConsumer) subscribe() {
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicPickerV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicPickerV1, "flow-step-statistics")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -101,8 +102,9 @@ func (r flowStepStatisticsConsumer) subscribe() {
 				}
 				event := msg.Message.EventEntity.(*mm_pubsub.PickerEventEntity)
 				// Create the Flow Step Statistics
-				if err := r.service.updateStatistics(*event); err != nil {
+				if err := r.service.updateStatistics(msg.Message.Context(), *event); err != nil {
 					zap.L().Error("Impossible to update Flow Step statistics", zap.String("service", "flow-step-statistics-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
@@ -110,7 +112,7 @@ func (r flowStepStatisticsConsumer) subscribe() {
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRolloutStrategyV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRolloutStrategyV1, "flow-step-statistics")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -144,9 +146,14 @@ func (r flowStepStatisticsConsumer) subscribe() {
 				if event.RolloutState != mm_pubsub.RolloutStateWarmup {
 					return
 				}
+				// A Warmup resumed after a pause keeps its statistics
+				if event.Configuration.StateConfigurations.PhaseStartedAt != nil {
+					return
+				}
 				// Cleanup statistics on Rollout Strategy start
-				if err := r.service.cleanupStatistics(*event); err != nil {
+				if err := r.service.cleanupStatistics(msg.Message.Context(), *event); err != nil {
 					zap.L().Error("Impossible to cleanup Statistics for Flow Step", zap.String("service", "flow-step-statistics-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
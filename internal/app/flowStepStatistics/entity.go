@@ -19,3 +19,16 @@ type flowStepEntity struct {
 	ID     uuid.UUID `json:"flowStepId"`
 	FlowID uuid.UUID `json:"flowId"`
 }
+
+/*
+Requests served by a Flow Step in a point of the time series
+*/
+type flowStepStatisticsPointEntity struct {
+	Timestamp   time.Time `json:"timestamp"`
+	TotRequests int64     `json:"totRequests"`
+}
+
+type flowStepRequestsBucketEntity struct {
+	BucketStart time.Time `json:"bucketStart"`
+	TotRequests int64     `json:"totRequests"`
+}
@@ -3,5 +3,6 @@ package flowStepStatistics
 import "errors"
 
 var errFlowNotFound = errors.New("flow-not-found")
+var errFlowStepNotFound = errors.New("flow-step-not-found")
 var errFlowStepStatisticsNotFound = errors.New("flow-step-statistics-not-found")
 var errFlowStepStatisticsAlreadyExists = errors.New("flow-step-statistics-already-exists")
@@ -34,3 +34,12 @@ func (m flowStepStatisticsModel) TableName() string {
 func (m flowStepStatisticsModel) toEntity() flowStepStatisticsEntity {
 	return flowStepStatisticsEntity(m)
 }
+
+type flowStepRequestsBucketModel struct {
+	BucketStart time.Time `gorm:"column:bucket_start;type:timestamp"`
+	TotRequests int64     `gorm:"column:tot_req;type:bigint"`
+}
+
+func (m flowStepRequestsBucketModel) toEntity() flowStepRequestsBucketEntity {
+	return flowStepRequestsBucketEntity(m)
+}
@@ -1,6 +1,8 @@
 package flowStepStatistics
 
 import (
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/google/uuid"
@@ -13,6 +15,7 @@ type flowStepStatisticsRepositoryInterface interface {
 	getFlowStepStatisticsByFlowStepID(tx *gorm.DB, flowStepID uuid.UUID, forUpdate bool) (flowStepStatisticsEntity, error)
 	saveFlowStepStatistics(tx *gorm.DB, flowStepStatistics flowStepStatisticsEntity, operation mm_db.SaveOperation) (flowStepStatisticsEntity, error)
 	cleanupFlowStepStatisticsByUseCaseId(tx *gorm.DB, useCaseID uuid.UUID) error
+	listFlowStepRequestsBuckets(tx *gorm.DB, flowStepID uuid.UUID, from time.Time, to time.Time) ([]flowStepRequestsBucketEntity, error)
 }
 
 type flowStepStatisticsRepository struct {
@@ -76,3 +79,25 @@ func (r flowStepStatisticsRepository) cleanupFlowStepStatisticsByUseCaseId(tx *g
 		Update("tot_req", 0)
 	return result.Error
 }
+
+/*
+Count the requests served by the Flow Step in each hour between from and to
+*/
+func (r flowStepStatisticsRepository) listFlowStepRequestsBuckets(tx *gorm.DB, flowStepID uuid.UUID, from time.Time, to time.Time) ([]flowStepRequestsBucketEntity, error) {
+	var models []flowStepRequestsBucketModel
+	query := tx.Table("mm_picker_request").
+		Select("DATE_TRUNC('hour', created_at) AS bucket_start, COUNT(*) AS tot_req").
+		Where("flow_step_id = ?", flowStepID).
+		Where("created_at >= ?", from).
+		Where("created_at < ?", to).
+		Group("bucket_start")
+	result := query.Scan(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]flowStepRequestsBucketEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
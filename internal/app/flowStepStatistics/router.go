@@ -56,4 +56,34 @@ func (r flowStepStatisticsRouter) register(router *gin.RouterGroup) {
 			}
 			mm_router.ReturnOk(ctx, &gin.H{"item": item})
 		})
+
+	router.GET(
+		"/flow-steps/:flowStepId/flow-step-statistics/timeseries",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(5)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request getFlowStepStatisticsTimeseriesInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			items, err := r.service.getFlowStepStatisticsTimeseries(ctx, request)
+			if err == errFlowStepNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "flow-step-statistics-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"items": items})
+		})
 }
@@ -1,11 +1,13 @@
 package flowStepStatistics
 
 import (
+	"context"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_err"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_timeseries"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,9 +16,10 @@ import (
 
 type flowStepStatisticsServiceInterface interface {
 	getFlowStepStatisticsByID(ctx *gin.Context, input getFlowStepStatisticsInputDto) (flowStepStatisticsEntity, error)
+	getFlowStepStatisticsTimeseries(ctx *gin.Context, input getFlowStepStatisticsTimeseriesInputDto) ([]flowStepStatisticsPointEntity, error)
 	createFlowStepStatistics(flowStepID uuid.UUID) (flowStepStatisticsEntity, error)
-	updateStatistics(event mm_pubsub.PickerEventEntity) error
-	cleanupStatistics(event mm_pubsub.RolloutStrategyEventEntity) error
+	updateStatistics(ctx context.Context, event mm_pubsub.PickerEventEntity) error
+	cleanupStatistics(ctx context.Context, event mm_pubsub.RolloutStrategyEventEntity) error
 }
 
 type flowStepStatisticsService struct {
@@ -45,6 +48,44 @@ func (s flowStepStatisticsService) getFlowStepStatisticsByID(ctx *gin.Context, i
 	return item, nil
 }
 
+/*
+Build the time series of the requests served by the Flow Step
+*/
+func (s flowStepStatisticsService) getFlowStepStatisticsTimeseries(ctx *gin.Context, input getFlowStepStatisticsTimeseriesInputDto) ([]flowStepStatisticsPointEntity, error) {
+	flowStepID := uuid.MustParse(input.FlowStepID)
+	from := *mm_utils.GetOptionalTimeFromString(&input.From)
+	to := *mm_utils.GetOptionalTimeFromString(&input.To)
+	granularity := mm_timeseries.Granularity(input.Granularity)
+	flowStep, err := s.repository.getFlowStepByID(s.storage, flowStepID)
+	if err != nil {
+		return []flowStepStatisticsPointEntity{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(flowStep) {
+		return []flowStepStatisticsPointEntity{}, errFlowStepNotFound
+	}
+	points := mm_timeseries.Points(from, to, granularity)
+	if len(points) == 0 {
+		return []flowStepStatisticsPointEntity{}, nil
+	}
+	buckets, err := s.repository.listFlowStepRequestsBuckets(s.storage, flowStepID, points[0], to)
+	if err != nil {
+		return []flowStepStatisticsPointEntity{}, mm_err.ErrGeneric
+	}
+	// Sum the hourly buckets in their points
+	indexedPoints := map[time.Time]int{}
+	items := make([]flowStepStatisticsPointEntity, len(points))
+	for i, point := range points {
+		indexedPoints[point] = i
+		items[i] = flowStepStatisticsPointEntity{Timestamp: point}
+	}
+	for _, bucket := range buckets {
+		if i, ok := indexedPoints[mm_timeseries.Truncate(bucket.BucketStart, granularity)]; ok {
+			items[i].TotRequests += bucket.TotRequests
+		}
+	}
+	return items, nil
+}
+
 func (s flowStepStatisticsService) createFlowStepStatistics(flowStepID uuid.UUID) (flowStepStatisticsEntity, error) {
 	now := time.Now()
 	var flowStepStatistics flowStepStatisticsEntity
@@ -85,8 +126,12 @@ func (s flowStepStatisticsService) createFlowStepStatistics(flowStepID uuid.UUID
 	return flowStepStatistics, nil
 }
 
-func (s flowStepStatisticsService) updateStatistics(event mm_pubsub.PickerEventEntity) error {
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+func (s flowStepStatisticsService) updateStatistics(ctx context.Context, event mm_pubsub.PickerEventEntity) error {
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
 		// Find the flow step statistics
 		item, err := s.repository.getFlowStepStatisticsByFlowStepID(tx, event.FlowStepID, true)
 		if err != nil {
@@ -109,6 +154,12 @@ func (s flowStepStatisticsService) updateStatistics(event mm_pubsub.PickerEventE
 	return nil
 }
 
-func (s flowStepStatisticsService) cleanupStatistics(event mm_pubsub.RolloutStrategyEventEntity) error {
-	return s.repository.cleanupFlowStepStatisticsByUseCaseId(s.storage, event.UseCaseID)
+func (s flowStepStatisticsService) cleanupStatistics(ctx context.Context, event mm_pubsub.RolloutStrategyEventEntity) error {
+	return s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
+		return s.repository.cleanupFlowStepStatisticsByUseCaseId(tx, event.UseCaseID)
+	})
 }
@@ -1,14 +1,19 @@
 package picker
 
 import (
+	"errors"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_template"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
 
 type pickerInputDto struct {
-	CorrelationID   string `json:"correlationId"`
-	UseCaseCode     string `json:"useCaseCode"`
-	UseCaseStepCode string `json:"useCaseStepCode"`
+	CorrelationID   string            `json:"correlationId"`
+	UseCaseCode     string            `json:"useCaseCode"`
+	UseCaseStepCode string            `json:"useCaseStepCode"`
+	Variables       map[string]string `json:"variables,omitempty"`
+	Strict          bool              `json:"strict,omitempty"`
 }
 
 func (r pickerInputDto) validate() error {
@@ -16,5 +21,13 @@ func (r pickerInputDto) validate() error {
 		validation.Field(&r.CorrelationID, validation.Required, is.UUID),
 		validation.Field(&r.UseCaseCode, validation.Required, validation.Length(1, 255)),
 		validation.Field(&r.UseCaseStepCode, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Variables, validation.By(func(value interface{}) error {
+			for name := range r.Variables {
+				if !mm_template.NameRegex.MatchString(name) {
+					return errors.New("names must contain only letters, numbers, underscores and dashes")
+				}
+			}
+			return nil
+		})),
 	)
 }
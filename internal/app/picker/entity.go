@@ -15,8 +15,9 @@ type useCaseEntity struct {
 }
 
 type useCaseStepEntity struct {
-	ID   uuid.UUID
-	Code string
+	ID        uuid.UUID
+	Code      string
+	Variables json.RawMessage
 }
 
 type flowEntity struct {
@@ -33,6 +34,7 @@ type flowStepEntity struct {
 	UseCaseStepID uuid.UUID
 	Configuration json.RawMessage
 	Placeholders  json.RawMessage
+	Version       int64
 }
 
 type pickerCorrelationEntity struct {
@@ -8,3 +8,5 @@ var errUseCaseStepNotFound = errors.New("use-case-step-not-found")
 var errFlowNotFound = errors.New("flow-not-found")
 var errCorrelationConflict = errors.New("correlation-conflict")
 var errFlowsNotAvailable = errors.New("flows-not-available")
+var errMissingPlaceholders = errors.New("missing-placeholders")
+var errInvalidVariables = errors.New("invalid-variables")
@@ -0,0 +1,58 @@
+package picker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	pickOutcomeMatched = "matched"
+	pickOutcomeError   = "error"
+)
+
+/*
+Label of the picker requests for a Use Case code that does not exist
+*/
+const pickUnknownUseCaseCode = "unknown"
+
+var pickDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "mm_picker_request_duration_seconds",
+	Help:    "Time to pick the Flow Step to serve for a Use Case",
+	Buckets: prometheus.DefBuckets,
+}, []string{"use_case_code"})
+
+var pickTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "mm_picker_requests_total",
+	Help: "Picker requests by Use Case and outcome, that is matched or the reason of the failure",
+}, []string{"use_case_code", "outcome"})
+
+var pickFailures = []error{
+	errUseCaseNotFound,
+	errUseCaseNotAcive,
+	errUseCaseStepNotFound,
+	errFlowNotFound,
+	errCorrelationConflict,
+	errFlowsNotAvailable,
+	errMissingPlaceholders,
+	errInvalidVariables,
+}
+
+/*
+Track the latency and the outcome of a picker request.
+*/
+func observePick(useCaseCode string, startedAt time.Time, err error) {
+	outcome := pickOutcomeMatched
+	if err != nil {
+		outcome = pickOutcomeError
+		for _, failure := range pickFailures {
+			if errors.Is(err, failure) {
+				outcome = failure.Error()
+			}
+		}
+	}
+	pickDuration.WithLabelValues(useCaseCode).Observe(time.Since(startedAt).Seconds())
+	pickTotal.WithLabelValues(useCaseCode, outcome).Inc()
+}
@@ -22,8 +22,9 @@ func (m useCaseModel) toEntity() useCaseEntity {
 }
 
 type useCaseStepModel struct {
-	ID   uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
-	Code string    `gorm:"column:code;type:varchar(255)"`
+	ID        uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	Code      string          `gorm:"column:code;type:varchar(255)"`
+	Variables json.RawMessage `gorm:"column:variables;type:json"`
 }
 
 func (m useCaseStepModel) TableName() string {
@@ -56,6 +57,7 @@ type flowStepModel struct {
 	UseCaseStepID uuid.UUID       `gorm:"column:use_case_step_id;type:varchar(36)"`
 	Configuration json.RawMessage `gorm:"column:configuration;type:json"`
 	Placeholders  json.RawMessage `gorm:"column:placeholders;type:json"`
+	Version       int64           `gorm:"column:version;type:bigint"`
 }
 
 func (m flowStepModel) TableName() string {
@@ -87,6 +89,7 @@ type pickerRequestModel struct {
 	UseCaseStepID      uuid.UUID       `gorm:"column:use_case_step_id;type:varchar(36)"`
 	FlowID             uuid.UUID       `gorm:"column:flow_id;type:varchar(36)"`
 	FlowStepID         uuid.UUID       `gorm:"column:flow_step_id;type:varchar(36)"`
+	FlowStepVersion    *int64          `gorm:"column:flow_step_version;type:bigint"`
 	CorrelationID      uuid.UUID       `gorm:"column:correlation_id;type:varchar(36)"`
 	IsFirstCorrelation *bool           `gorm:"column:is_first_correlation;type:bool"`
 	InputMessage       json.RawMessage `gorm:"column:input_message;type:json"`
@@ -1,6 +1,7 @@
 package picker
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_auth"
@@ -44,6 +45,10 @@ func (r pickerRouter) register(router *gin.RouterGroup) {
 			}
 			// Business Logic
 			item, err := r.service.pick(ctx, request)
+			if errors.Is(err, errMissingPlaceholders) || errors.Is(err, errInvalidVariables) {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
 			if err == errUseCaseNotFound {
 				mm_router.ReturnBadRequestError(ctx, err)
 				return
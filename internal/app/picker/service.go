@@ -2,12 +2,16 @@ package picker
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/rand/v2"
+	"strings"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_err"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_template"
+	"github.com/ai-model-match/backend/internal/pkg/mm_tracing"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -32,7 +36,15 @@ func newPickerService(storage *gorm.DB, pubSubAgent *mm_pubsub.PubSubAgent, repo
 	}
 }
 
-func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntity, error) {
+func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (picked pickerEntity, err error) {
+	// Track the latency and the outcome by the code of the Use Case found, so unknown codes do not add new series
+	startedAt := time.Now()
+	useCaseCode := pickUnknownUseCaseCode
+	defer func() {
+		observePick(useCaseCode, startedAt, err)
+	}()
+	// Trace the queries within the request
+	db := s.storage.WithContext(mm_tracing.DetachedContext(ctx))
 	var useCase useCaseEntity
 	var useCaseStep useCaseStepEntity
 	var correlation pickerCorrelationEntity
@@ -43,25 +55,40 @@ func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntit
 	var isFirstCorrelation bool = false
 	eventsToPublish := []mm_pubsub.EventToPublish{}
 	// Check Use Case exists by its code
-	if item, err := s.repository.getUseCaseByCode(s.storage, input.UseCaseCode); err != nil {
+	if item, err := s.repository.getUseCaseByCode(db, input.UseCaseCode); err != nil {
 		return pickerEntity{}, mm_err.ErrGeneric
 	} else if mm_utils.IsEmpty(item) {
 		return pickerEntity{}, errUseCaseNotFound
-	} else if !item.Active {
-		return pickerEntity{}, errUseCaseNotAcive
 	} else {
 		useCase = item
+		useCaseCode = item.Code
+	}
+	if !useCase.Active {
+		return pickerEntity{}, errUseCaseNotAcive
 	}
 	// Check Use Case Step exists by its code and associated to the Use Case before
-	if item, err := s.repository.getUseCaseStepByCode(s.storage, useCase.ID, input.UseCaseStepCode); err != nil {
+	if item, err := s.repository.getUseCaseStepByCode(db, useCase.ID, input.UseCaseStepCode); err != nil {
 		return pickerEntity{}, mm_err.ErrGeneric
 	} else if mm_utils.IsEmpty(item) {
 		return pickerEntity{}, errUseCaseStepNotFound
 	} else {
 		useCaseStep = item
 	}
+	// When the Use Case Step declares its variables, validate the ones provided by the caller and apply defaults
+	variables := input.Variables
+	schema, err := mm_template.ParseSchema(useCaseStep.Variables)
+	if err != nil {
+		return pickerEntity{}, mm_err.ErrGeneric
+	}
+	if len(schema) > 0 {
+		resolved, violations := mm_template.ValidateVariables(schema, input.Variables)
+		if len(violations) > 0 {
+			return pickerEntity{}, fmt.Errorf("%w: %s", errInvalidVariables, strings.Join(violations, "; "))
+		}
+		variables = resolved
+	}
 	// Search a recent correlation by ID
-	if item, err := s.repository.getRecentCorrelationByID(s.storage, mm_utils.GetUUIDFromString(input.CorrelationID)); err != nil {
+	if item, err := s.repository.getRecentCorrelationByID(db, mm_utils.GetUUIDFromString(input.CorrelationID)); err != nil {
 		return pickerEntity{}, mm_err.ErrGeneric
 	} else if !mm_utils.IsEmpty(item) {
 		if item.UseCaseID != useCase.ID {
@@ -71,7 +98,7 @@ func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntit
 	}
 	if !mm_utils.IsEmpty(correlation) {
 		// If correlation found, we have immediately the Flow
-		if item, err := s.repository.getFlowByID(s.storage, correlation.FlowID); err != nil {
+		if item, err := s.repository.getFlowByID(db, correlation.FlowID); err != nil {
 			return pickerEntity{}, mm_err.ErrGeneric
 		} else if mm_utils.IsEmpty(item) {
 			return pickerEntity{}, errFlowNotFound
@@ -80,7 +107,7 @@ func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntit
 		}
 	} else {
 		// If correlation does not exist, retrieve all flows related to the Use Case
-		if items, err := s.repository.getFlowsByUseCaseID(s.storage, useCase.ID); err != nil {
+		if items, err := s.repository.getFlowsByUseCaseID(db, useCase.ID); err != nil {
 			return pickerEntity{}, mm_err.ErrGeneric
 		} else if len(items) == 0 {
 			return pickerEntity{}, errFlowsNotAvailable
@@ -109,16 +136,28 @@ func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntit
 		}
 	}
 	// Retrieve the Step of the selected Flow
-	if item, err := s.repository.getFlowStepByFlowIdandUseCaseStepId(s.storage, selectedFlow.ID, useCaseStep.ID); err != nil {
+	if item, err := s.repository.getFlowStepByFlowIdandUseCaseStepId(db, selectedFlow.ID, useCaseStep.ID); err != nil {
 		return pickerEntity{}, mm_err.ErrGeneric
 	} else if mm_utils.IsEmpty(item) {
 		return pickerEntity{}, errUseCaseStepNotFound
 	} else {
 		selectedFlowStep = item
 	}
+	// Render the placeholders of the configuration with the variables provided by the caller
+	outputMessage := selectedFlowStep.Configuration
+	if variables != nil || input.Strict {
+		rendered, missing, err := mm_template.Render(selectedFlowStep.Configuration, variables)
+		if err != nil {
+			return pickerEntity{}, mm_err.ErrGeneric
+		}
+		if input.Strict && len(missing) > 0 {
+			return pickerEntity{}, fmt.Errorf("%w: %s", errMissingPlaceholders, strings.Join(missing, ", "))
+		}
+		outputMessage = rendered
+	}
 
 	// Start transaction
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+	errTransaction := db.Transaction(func(tx *gorm.DB) error {
 		// Now store the correlation for next requests, updating old ones if needed
 		if mm_utils.IsEmpty(correlation) {
 			correlation = pickerCorrelationEntity{
@@ -127,7 +166,7 @@ func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntit
 				FlowID:    selectedFlow.ID,
 				CreatedAt: time.Now(),
 			}
-			if _, err := s.repository.saveCorrelation(s.storage, correlation, mm_db.Upsert); err != nil {
+			if _, err := s.repository.saveCorrelation(db, correlation, mm_db.Upsert); err != nil {
 				return mm_err.ErrGeneric
 			} else {
 				isFirstCorrelation = true
@@ -144,10 +183,11 @@ func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntit
 			UseCaseStepID:      useCaseStep.ID,
 			FlowID:             selectedFlow.ID,
 			FlowStepID:         selectedFlowStep.ID,
+			FlowStepVersion:    &selectedFlowStep.Version,
 			CorrelationID:      mm_utils.GetUUIDFromString(input.CorrelationID),
 			IsFirstCorrelation: &isFirstCorrelation,
 			InputMessage:       inputMsg,
-			OutputMessage:      selectedFlowStep.Configuration,
+			OutputMessage:      outputMessage,
 			Placeholders:       selectedFlowStep.Placeholders,
 			CreatedAt:          time.Now(),
 		}
@@ -167,6 +207,7 @@ func (s pickerService) pick(ctx *gin.Context, input pickerInputDto) (pickerEntit
 					UseCaseStepID:      newPickedEntity.UseCaseStepID,
 					FlowID:             newPickedEntity.FlowID,
 					FlowStepID:         newPickedEntity.FlowStepID,
+					FlowStepVersion:    newPickedEntity.FlowStepVersion,
 					CorrelationID:      newPickedEntity.CorrelationID,
 					IsFirstCorrelation: newPickedEntity.IsFirstCorrelation,
 					InputMessage:       newPickedEntity.InputMessage,
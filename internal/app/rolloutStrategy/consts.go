@@ -4,3 +4,37 @@ const (
 	MinFeedbackScore float64 = 1.0
 	MaxFeedbackScore float64 = 5.0
 )
+
+/*
+Limits of the Escape rule conditions: nesting of AND/OR conditions and max time window of a score drop
+*/
+const (
+	MaxEscapeConditionDepth int   = 3
+	MaxEscapeWindowMins     int64 = 10080
+)
+
+/*
+Max sliding window or half-life of the aggregated feedback scores (one month)
+*/
+const MaxScoreAggregationHours int64 = 720
+
+/*
+Limits of the simulation, to keep the execution bounded (one week of virtual time)
+*/
+const (
+	MaxSimulationDurationMins          int64   = 10080
+	MaxSimulationSessionRequestsPerMin float64 = 10000
+)
+
+/*
+Origin of a change recorded in the Rollout Strategy history
+*/
+const (
+	rsHistorySourceManual rsHistorySource = "MANUAL"
+	rsHistorySourceEngine rsHistorySource = "ENGINE"
+)
+
+var availableRsHistorySource = []interface{}{
+	rsHistorySourceManual,
+	rsHistorySourceEngine,
+}
@@ -25,7 +25,7 @@ func newRolloutStrategyConsumer(pubSub *mm_pubsub.PubSubAgent, service rolloutSt
 
 func (r rolloutStrategyConsumer) subscribe() {
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicUseCaseV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicUseCaseV1, "rollout-strategy")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -56,12 +56,13 @@ func (r rolloutStrategyConsumer) subscribe() {
 				}
 				event := msg.Message.EventEntity.(*mm_pubsub.UseCaseEventEntity)
 				// Create the Rollout Strategy
-				if _, err := r.service.createRolloutStrategy(event.ID); err != nil {
+				if _, err := r.service.createRolloutStrategy(msg.Message.Context(), event.ID); err != nil {
 					if err == errRolloutStrategyAlreadyExists {
 						zap.L().Info("rolloutStrategy already exists. Skip event", zap.String("service", "rollout-strategy-consumer"))
 						return
 					} else {
 						zap.L().Error("Impossible to create the rolloutStrategy for the new Use Case", zap.String("service", "rollout-strategy-consumer"))
+						msg.Message.EventState.Fail(err)
 						return
 					}
 				}
@@ -70,7 +71,7 @@ func (r rolloutStrategyConsumer) subscribe() {
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRsEngineV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRsEngineV1, "rollout-strategy")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -101,8 +102,9 @@ func (r rolloutStrategyConsumer) subscribe() {
 				}
 				event := msg.Message.EventEntity.(*mm_pubsub.RsEngineEventEntity)
 				// Update the Rollout Strategy
-				if err := r.service.updateRolloutStrategyFromEvent(*event); err != nil {
+				if err := r.service.updateRolloutStrategyFromEvent(msg.Message.Context(), *event); err != nil {
 					zap.L().Error("Impossible to update the rolloutStrategy from RS Engine event", zap.String("service", "rollout-strategy-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
@@ -1,6 +1,9 @@
 package rolloutStrategy
 
 import (
+	"errors"
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -44,3 +47,74 @@ func (r updateRolloutStrategyStatusInputDto) validate() error {
 		validation.Field(&r.CompletedFlowID, is.UUID, validation.NilOrNotEmpty, validation.When(r.RolloutState == string(mm_pubsub.RolloutStateForcedCompleted), validation.Required)),
 	)
 }
+
+type simulateRolloutStrategyInputDto struct {
+	UseCaseID             string                `uri:"useCaseId"`
+	Configuration         rsConfigInputDto      `json:"configuration"`
+	DurationMins          int64                 `json:"durationMins"`
+	SessionRequestsPerMin float64               `json:"sessionRequestsPerMin"`
+	FeedbackRate          float64               `json:"feedbackRate"`
+	Seed                  *uint64               `json:"seed"`
+	Flows                 []rsSimulationFlowDto `json:"flows"`
+}
+
+func (r simulateRolloutStrategyInputDto) validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.UseCaseID, validation.Required, is.UUID),
+		validation.Field(&r.Configuration, validation.By(func(v interface{}) error {
+			return v.(rsConfigInputDto).validate()
+		})),
+		validation.Field(&r.DurationMins, validation.Required, validation.Min(int64(1)), validation.Max(int64(MaxSimulationDurationMins))),
+		validation.Field(&r.SessionRequestsPerMin, validation.Required, validation.Min(0.0).Exclusive(), validation.Max(MaxSimulationSessionRequestsPerMin)),
+		validation.Field(&r.FeedbackRate, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&r.Flows, validation.Required, validation.Length(1, 0), validation.Each(validation.By(func(value interface{}) error {
+			v := value.(rsSimulationFlowDto)
+			return v.validate()
+		}))),
+	); err != nil {
+		return err
+	}
+	// Check there is only one score distribution per Flow
+	seen := make(map[string]bool)
+	for _, flow := range r.Flows {
+		if _, exists := seen[flow.FlowID]; exists {
+			return errors.New("flow can have only one score distribution associated")
+		}
+		seen[flow.FlowID] = true
+	}
+	return nil
+}
+
+type rsSimulationFlowDto struct {
+	FlowID      string  `json:"flowId"`
+	AvgScore    float64 `json:"avgScore"`
+	StdDevScore float64 `json:"stdDevScore"`
+}
+
+func (r rsSimulationFlowDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FlowID, validation.Required, is.UUID),
+		validation.Field(&r.AvgScore, validation.Required, validation.Min(MinFeedbackScore), validation.Max(MaxFeedbackScore)),
+		validation.Field(&r.StdDevScore, validation.Min(0.0), validation.Max(MaxFeedbackScore-MinFeedbackScore)),
+	)
+}
+
+type listRolloutStrategyHistoryInputDto struct {
+	UseCaseID string  `uri:"useCaseId"`
+	Page      int     `form:"page"`
+	PageSize  int     `form:"pageSize"`
+	From      *string `form:"from"`
+	To        *string `form:"to"`
+	Source    *string `form:"source"`
+}
+
+func (r listRolloutStrategyHistoryInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.UseCaseID, validation.Required, is.UUID),
+		validation.Field(&r.Page, validation.Required, validation.Min(1)),
+		validation.Field(&r.PageSize, validation.Required, validation.Min(1), validation.Max(200)),
+		validation.Field(&r.From, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+		validation.Field(&r.To, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+		validation.Field(&r.Source, validation.NilOrNotEmpty, validation.In(mm_utils.TransformToStrings(availableRsHistorySource)...)),
+	)
+}
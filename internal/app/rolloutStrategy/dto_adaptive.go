@@ -1,28 +1,137 @@
 package rolloutStrategy
 
 import (
+	"errors"
+	"regexp"
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type rsAdaptivePhaseDto struct {
-	MinFeedback  int64   `json:"minFeedback"`
-	MaxStepPct   float64 `json:"maxStepPct"`
-	IntervalMins int64   `json:"intervalMins"`
+	Algorithm          string              `json:"algorithm"`
+	MinFeedback        int64               `json:"minFeedback"`
+	MaxStepPct         float64             `json:"maxStepPct"`
+	IntervalMins       int64               `json:"intervalMins"`
+	MinBestProbability *float64            `json:"minBestProbability"`
+	ExplorationFactor  *float64            `json:"explorationFactor"`
+	Epsilon            *float64            `json:"epsilon"`
+	MinConfidence      *float64            `json:"minConfidence"`
+	ActiveWindows      *rsActiveWindowsDto `json:"activeWindows"`
 }
 
 func (r rsAdaptivePhaseDto) validate() error {
+	isThompsonSampling := r.Algorithm == string(mm_pubsub.RsAdaptiveAlgorithmThompsonSampling)
+	isUCB1 := r.Algorithm == string(mm_pubsub.RsAdaptiveAlgorithmUCB1)
+	isEpsilonGreedy := r.Algorithm == string(mm_pubsub.RsAdaptiveAlgorithmEpsilonGreedy)
+	// Epsilon Greedy with exploration completes only once the best Flow is significantly better
+	isExploringEpsilonGreedy := isEpsilonGreedy && r.Epsilon != nil && *r.Epsilon > 0
 	return validation.ValidateStruct(&r,
+		validation.Field(&r.Algorithm, validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableRsAdaptiveAlgorithm)...)),
 		validation.Field(&r.MinFeedback, validation.Min(int64(0))),
 		validation.Field(&r.MaxStepPct, validation.Required, validation.Min(1.0), validation.Max(100.0)),
 		validation.Field(&r.IntervalMins, validation.Required, validation.Min(int64(1))),
+		validation.Field(&r.MinBestProbability, validation.When(isThompsonSampling, validation.Required, validation.Min(0.5), validation.Max(0.9999)).Else(validation.Nil)),
+		validation.Field(&r.ExplorationFactor, validation.When(isUCB1, validation.NotNil, validation.Min(0.0), validation.Max(10.0)).Else(validation.Nil)),
+		validation.Field(&r.Epsilon, validation.When(isEpsilonGreedy, validation.NotNil, validation.Min(0.0), validation.Max(1.0)).Else(validation.Nil)),
+		validation.Field(&r.MinConfidence, validation.When(isExploringEpsilonGreedy, validation.Required).Else(validation.NilOrNotEmpty), validation.Min(0.5), validation.Max(0.9999)),
+		validation.Field(&r.ActiveWindows, validation.By(func(value interface{}) error {
+			if mm_utils.IsEmpty(value) {
+				return nil
+			}
+			return value.(*rsActiveWindowsDto).validate()
+		})),
 	)
 }
 
 func (r rsAdaptivePhaseDto) toEntity() mm_pubsub.RsAdaptivePhase {
-	return mm_pubsub.RsAdaptivePhase{
-		MinFeedback:  r.MinFeedback,
-		MaxStepPct:   r.MaxStepPct,
-		IntervalMins: r.IntervalMins,
+	// Greedy is the default algorithm, for backward compatibility
+	algorithm := mm_pubsub.RsAdaptiveAlgorithmGreedy
+	if r.Algorithm != "" {
+		algorithm = mm_pubsub.RsAdaptiveAlgorithm(r.Algorithm)
+	}
+	a := mm_pubsub.RsAdaptivePhase{
+		Algorithm:          algorithm,
+		MinFeedback:        r.MinFeedback,
+		MaxStepPct:         r.MaxStepPct,
+		IntervalMins:       r.IntervalMins,
+		MinBestProbability: r.MinBestProbability,
+		ExplorationFactor:  r.ExplorationFactor,
+		Epsilon:            r.Epsilon,
+		MinConfidence:      r.MinConfidence,
+	}
+	if r.ActiveWindows != nil {
+		e := r.ActiveWindows.toEntity()
+		a.ActiveWindows = &e
+	}
+	return a
+}
+
+// Time of the day in the "HH:MM" format, "24:00" is allowed as the end of the day
+var timeOfDayRegex = regexp.MustCompile(`^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$`)
+
+type rsActiveWindowsDto struct {
+	Timezone string            `json:"timezone"`
+	Windows  []rsTimeWindowDto `json:"windows"`
+}
+
+func (r rsActiveWindowsDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Timezone, validation.Required, validation.By(func(value interface{}) error {
+			if _, err := time.LoadLocation(value.(string)); err != nil {
+				return errors.New("must be a valid IANA timezone")
+			}
+			return nil
+		})),
+		validation.Field(&r.Windows, validation.Required, validation.Length(1, 0), validation.Each(validation.By(func(value interface{}) error {
+			v := value.(rsTimeWindowDto)
+			return v.validate()
+		}))),
+	)
+}
+
+func (r rsActiveWindowsDto) toEntity() mm_pubsub.RsActiveWindows {
+	windows := make([]mm_pubsub.RsTimeWindow, len(r.Windows))
+	for i, window := range r.Windows {
+		windows[i] = window.toEntity()
+	}
+	return mm_pubsub.RsActiveWindows{
+		Timezone: r.Timezone,
+		Windows:  windows,
+	}
+}
+
+type rsTimeWindowDto struct {
+	Days []string `json:"days"`
+	From string   `json:"from"`
+	To   string   `json:"to"`
+}
+
+func (r rsTimeWindowDto) validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Days, validation.Required, validation.Length(1, 0), validation.Each(validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableRsWeekday)...))),
+		validation.Field(&r.From, validation.Required, validation.Match(timeOfDayRegex)),
+		validation.Field(&r.To, validation.Required, validation.Match(timeOfDayRegex)),
+	); err != nil {
+		return err
+	}
+	// Times are zero-padded, so they can be compared as strings
+	if r.From >= r.To {
+		return errors.New("'from' must be before 'to'")
+	}
+	return nil
+}
+
+func (r rsTimeWindowDto) toEntity() mm_pubsub.RsTimeWindow {
+	days := make([]mm_pubsub.RsWeekday, len(r.Days))
+	for i, day := range r.Days {
+		days[i] = mm_pubsub.RsWeekday(day)
+	}
+	return mm_pubsub.RsTimeWindow{
+		Days: days,
+		From: r.From,
+		To:   r.To,
 	}
 }
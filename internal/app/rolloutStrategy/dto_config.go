@@ -1,19 +1,40 @@
 package rolloutStrategy
 
 import (
+	"encoding/json"
+	"errors"
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type rsConfigInputDto struct {
-	Warmup   *rsWarmupPhaseDto  `json:"warmup"`
-	Escape   *rsEscapePhaseDto  `json:"escape"`
-	Adaptive rsAdaptivePhaseDto `json:"adaptive"`
+	ScheduledStartAt *string                `json:"scheduledStartAt"`
+	Warmup           *rsWarmupPhaseDto      `json:"warmup"`
+	Escape           *rsEscapePhaseDto      `json:"escape"`
+	Adaptive         rsAdaptivePhaseDto     `json:"adaptive"`
+	ScoreAggregation *rsScoreAggregationDto `json:"scoreAggregation"`
 }
 
 func (r rsConfigInputDto) validate() error {
+	return r.validateWithStart(false)
+}
+
+/*
+Validate the configuration, accepting a scheduled start in the past if required, e.g. for configurations
+replayed on past traffic that are never applied.
+*/
+func (r rsConfigInputDto) validateWithStart(allowPastStart bool) error {
 	return validation.ValidateStruct(&r,
+		validation.Field(&r.ScheduledStartAt, validation.NilOrNotEmpty, validation.Date(time.RFC3339), validation.By(func(value interface{}) error {
+			scheduledStartAt := mm_utils.GetOptionalTimeFromString(value.(*string))
+			if !allowPastStart && scheduledStartAt != nil && !scheduledStartAt.After(time.Now()) {
+				return errors.New("must be in the future")
+			}
+			return nil
+		})),
 		validation.Field(&r.Warmup, validation.By(func(value interface{}) error {
 			if mm_utils.IsEmpty(value) {
 				return nil
@@ -29,12 +50,19 @@ func (r rsConfigInputDto) validate() error {
 		validation.Field(&r.Adaptive, validation.By(func(value interface{}) error {
 			return value.(rsAdaptivePhaseDto).validate()
 		})),
+		validation.Field(&r.ScoreAggregation, validation.By(func(value interface{}) error {
+			if mm_utils.IsEmpty(value) {
+				return nil
+			}
+			return value.(*rsScoreAggregationDto).validate()
+		})),
 	)
 }
 
 func (r rsConfigInputDto) toEntity() mm_pubsub.RSConfiguration {
 	a := mm_pubsub.RSConfiguration{
-		Adaptive: r.Adaptive.toEntity(),
+		ScheduledStartAt: mm_utils.GetOptionalTimeFromString(r.ScheduledStartAt),
+		Adaptive:         r.Adaptive.toEntity(),
 	}
 	if r.Warmup != nil {
 		e := r.Warmup.toEntity()
@@ -44,5 +72,46 @@ func (r rsConfigInputDto) toEntity() mm_pubsub.RSConfiguration {
 		e := r.Escape.toEntity()
 		a.Escape = &e
 	}
+	if r.ScoreAggregation != nil {
+		e := r.ScoreAggregation.toEntity()
+		a.ScoreAggregation = &e
+	}
 	return a
 }
+
+/*
+Round decimals on the percentages of all the phases
+*/
+func (r *rsConfigInputDto) roundPercentages() {
+	if !mm_utils.IsEmpty(r.Warmup) {
+		for i := range r.Warmup.Goals {
+			r.Warmup.Goals[i].FinalServePct = (mm_utils.RoundTo2DecimalsPtr(r.Warmup.Goals[i].FinalServePct))
+		}
+	}
+	if !mm_utils.IsEmpty(r.Escape) {
+		for i := range r.Escape.Rules {
+			r.Escape.Rules[i].LowerScore = (mm_utils.RoundTo2DecimalsPtr(r.Escape.Rules[i].LowerScore))
+			for j := range r.Escape.Rules[i].Rollback {
+				r.Escape.Rules[i].Rollback[j].FinalServePct = (mm_utils.RoundTo2DecimalsPtr(r.Escape.Rules[i].Rollback[j].FinalServePct))
+			}
+		}
+	}
+	r.Adaptive.MaxStepPct = (mm_utils.RoundTo2Decimals(r.Adaptive.MaxStepPct))
+}
+
+/*
+ParseConfiguration reads a Rollout Strategy configuration in the JSON format used by the API, validating it
+with the same rules, so tools outside the API (e.g. the backtest command) accept the same configurations.
+Tools replaying the configuration on past traffic can allow a scheduled start in the past.
+*/
+func ParseConfiguration(content []byte, allowPastStart bool) (mm_pubsub.RSConfiguration, error) {
+	var input rsConfigInputDto
+	if err := json.Unmarshal(content, &input); err != nil {
+		return mm_pubsub.RSConfiguration{}, err
+	}
+	if err := input.validateWithStart(allowPastStart); err != nil {
+		return mm_pubsub.RSConfiguration{}, err
+	}
+	input.roundPercentages()
+	return input.toEntity(), nil
+}
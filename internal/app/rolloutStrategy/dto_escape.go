@@ -2,6 +2,7 @@ package rolloutStrategy
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
@@ -44,17 +45,38 @@ func (r rsEscapePhaseDto) toEntity() mm_pubsub.RsEscapePhase {
 }
 
 type rsEscapeRuleDto struct {
-	FlowID      string                `json:"flowId"`
-	MinFeedback int64                 `json:"minFeedback"`
-	LowerScore  *float64              `json:"lowerScore"`
-	Rollback    []rsEscapeRollbackDto `json:"rollback"`
+	FlowID             string                `json:"flowId"`
+	MinFeedback        int64                 `json:"minFeedback"`
+	MinSessionRequests int64                 `json:"minSessionRequests"`
+	LowerScore         *float64              `json:"lowerScore"`
+	Condition          *rsEscapeConditionDto `json:"condition"`
+	Rollback           []rsEscapeRollbackDto `json:"rollback"`
 }
 
 func (r rsEscapeRuleDto) validate() error {
+	// Rules on client outcomes need a min number of session requests to be meaningful
+	hasOutcomeGuardrails := false
+	if r.Condition != nil {
+		for _, guardrail := range r.Condition.guardrails() {
+			if guardrail.Type == string(mm_pubsub.RsEscapeConditionErrorRate) || guardrail.Type == string(mm_pubsub.RsEscapeConditionAbandonRate) {
+				hasOutcomeGuardrails = true
+			}
+			if guardrail.BaselineFlowID != nil && *guardrail.BaselineFlowID == r.FlowID {
+				return errors.New("the baseline flow must be different from the flow of the rule")
+			}
+		}
+	}
 	if err := validation.ValidateStruct(&r,
 		validation.Field(&r.FlowID, validation.Required, is.UUID),
 		validation.Field(&r.MinFeedback, validation.Required, validation.Min(int64(1))),
-		validation.Field(&r.LowerScore, validation.Required, validation.Min(MinFeedbackScore), validation.Max(MaxFeedbackScore)),
+		validation.Field(&r.MinSessionRequests, validation.When(hasOutcomeGuardrails, validation.Required, validation.Min(int64(1))).Else(validation.Min(int64(0)))),
+		validation.Field(&r.LowerScore, validation.When(r.Condition == nil, validation.Required, validation.Min(MinFeedbackScore), validation.Max(MaxFeedbackScore)).Else(validation.Nil)),
+		validation.Field(&r.Condition, validation.By(func(value interface{}) error {
+			if mm_utils.IsEmpty(value) {
+				return nil
+			}
+			return value.(*rsEscapeConditionDto).validate(1)
+		})),
 		validation.Field(&r.Rollback, validation.Required, validation.Length(1, 0), validation.Each(validation.By(func(value interface{}) error {
 			v := value.(rsEscapeRollbackDto)
 			return v.validate()
@@ -83,11 +105,85 @@ func (r rsEscapeRuleDto) toEntity() mm_pubsub.RsEscapeRule {
 	for _, rollback := range r.Rollback {
 		rollbacks = append(rollbacks, rollback.toEntity())
 	}
-	return mm_pubsub.RsEscapeRule{
-		FlowID:      mm_utils.GetUUIDFromString(r.FlowID),
-		MinFeedback: r.MinFeedback,
-		LowerScore:  *r.LowerScore,
-		Rollback:    rollbacks,
+	a := mm_pubsub.RsEscapeRule{
+		FlowID:             mm_utils.GetUUIDFromString(r.FlowID),
+		MinFeedback:        r.MinFeedback,
+		MinSessionRequests: r.MinSessionRequests,
+		Rollback:           rollbacks,
+	}
+	// Legacy rules match on the average score only
+	if r.LowerScore != nil {
+		a.LowerScore = *r.LowerScore
+	}
+	if r.Condition != nil {
+		e := r.Condition.toEntity()
+		a.Condition = &e
+	}
+	return a
+}
+
+type rsEscapeConditionDto struct {
+	Type           string                 `json:"type"`
+	Conditions     []rsEscapeConditionDto `json:"conditions"`
+	Threshold      *float64               `json:"threshold"`
+	BaselineFlowID *string                `json:"baselineFlowId"`
+	WindowMins     *int64                 `json:"windowMins"`
+}
+
+func (r rsEscapeConditionDto) validate(depth int) error {
+	if depth > MaxEscapeConditionDepth {
+		return fmt.Errorf("conditions can be nested at most %d levels deep", MaxEscapeConditionDepth)
+	}
+	isComposition := r.Type == string(mm_pubsub.RsEscapeConditionAnd) || r.Type == string(mm_pubsub.RsEscapeConditionOr)
+	isAvgScore := r.Type == string(mm_pubsub.RsEscapeConditionAvgScore)
+	isBaselineDegradation := r.Type == string(mm_pubsub.RsEscapeConditionBaselineDegradation)
+	isScoreDrop := r.Type == string(mm_pubsub.RsEscapeConditionScoreDrop)
+	isPct := !isComposition && !isAvgScore && !isScoreDrop
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Type, validation.Required, validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableRsEscapeConditionType)...)),
+		validation.Field(&r.Conditions, validation.When(isComposition, validation.Required, validation.Length(1, 0), validation.Each(validation.By(func(value interface{}) error {
+			v := value.(rsEscapeConditionDto)
+			return v.validate(depth + 1)
+		}))).Else(validation.Empty)),
+		validation.Field(&r.Threshold,
+			validation.When(isComposition, validation.Nil).Else(validation.NotNil),
+			validation.When(isAvgScore, validation.Min(MinFeedbackScore), validation.Max(MaxFeedbackScore)),
+			validation.When(isScoreDrop, validation.Min(0.0).Exclusive(), validation.Max(MaxFeedbackScore-MinFeedbackScore)),
+			validation.When(isPct, validation.Min(0.0), validation.Max(100.0)),
+		),
+		validation.Field(&r.BaselineFlowID, validation.When(isBaselineDegradation, validation.Required, is.UUID).Else(validation.Nil)),
+		validation.Field(&r.WindowMins, validation.When(isScoreDrop, validation.Required, validation.Min(int64(1)), validation.Max(MaxEscapeWindowMins)).Else(validation.Nil)),
+	)
+}
+
+/*
+Return all guardrails of the condition, going through the nested AND/OR conditions
+*/
+func (r rsEscapeConditionDto) guardrails() []rsEscapeConditionDto {
+	if len(r.Conditions) == 0 {
+		return []rsEscapeConditionDto{r}
+	}
+	guardrails := []rsEscapeConditionDto{}
+	for _, nested := range r.Conditions {
+		guardrails = append(guardrails, nested.guardrails()...)
+	}
+	return guardrails
+}
+
+func (r rsEscapeConditionDto) toEntity() mm_pubsub.RsEscapeCondition {
+	var conditions []mm_pubsub.RsEscapeCondition
+	if len(r.Conditions) > 0 {
+		conditions = make([]mm_pubsub.RsEscapeCondition, len(r.Conditions))
+		for i, nested := range r.Conditions {
+			conditions[i] = nested.toEntity()
+		}
+	}
+	return mm_pubsub.RsEscapeCondition{
+		Type:           mm_pubsub.RsEscapeConditionType(r.Type),
+		Conditions:     conditions,
+		Threshold:      r.Threshold,
+		BaselineFlowID: mm_utils.GetOptionalUUIDFromString(r.BaselineFlowID),
+		WindowMins:     r.WindowMins,
 	}
 }
 
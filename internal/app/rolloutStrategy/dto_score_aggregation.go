@@ -0,0 +1,31 @@
+package rolloutStrategy
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type rsScoreAggregationDto struct {
+	Mode          string   `json:"mode"`
+	WindowHours   *int64   `json:"windowHours"`
+	HalfLifeHours *float64 `json:"halfLifeHours"`
+}
+
+func (r rsScoreAggregationDto) validate() error {
+	isWindow := r.Mode == string(mm_pubsub.RsScoreAggregationWindow)
+	isDecay := r.Mode == string(mm_pubsub.RsScoreAggregationDecay)
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Mode, validation.Required, validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableRsScoreAggregationMode)...)),
+		validation.Field(&r.WindowHours, validation.When(isWindow, validation.Required, validation.Min(int64(1)), validation.Max(MaxScoreAggregationHours)).Else(validation.Nil)),
+		validation.Field(&r.HalfLifeHours, validation.When(isDecay, validation.Required, validation.Min(0.1), validation.Max(float64(MaxScoreAggregationHours))).Else(validation.Nil)),
+	)
+}
+
+func (r rsScoreAggregationDto) toEntity() mm_pubsub.RsScoreAggregation {
+	return mm_pubsub.RsScoreAggregation{
+		Mode:          mm_pubsub.RsScoreAggregationMode(r.Mode),
+		WindowHours:   r.WindowHours,
+		HalfLifeHours: r.HalfLifeHours,
+	}
+}
@@ -10,9 +10,13 @@ import (
 )
 
 type rsWarmupPhaseDto struct {
-	IntervalMins     *int64          `json:"intervalMins"`
-	IntervalSessReqs *int64          `json:"intervalSessReqs"`
-	Goals            []rsFlowGoalDto `json:"goals"`
+	IntervalMins     *int64                  `json:"intervalMins"`
+	IntervalSessReqs *int64                  `json:"intervalSessReqs"`
+	Goals            []rsFlowGoalDto         `json:"goals"`
+	Curve            string                  `json:"curve"`
+	Doublings        *int64                  `json:"doublings"`
+	Steps            *int64                  `json:"steps"`
+	Checkpoints      []rsWarmupCheckpointDto `json:"checkpoints"`
 }
 
 func (r rsWarmupPhaseDto) validate() error {
@@ -22,6 +26,9 @@ func (r rsWarmupPhaseDto) validate() error {
 	if r.IntervalMins != nil && r.IntervalSessReqs != nil {
 		return errors.New("only one between 'intervalMins' or 'intervalSessReqs' can be set")
 	}
+	isExponential := r.Curve == string(mm_pubsub.RsWarmupCurveExponential)
+	isStep := r.Curve == string(mm_pubsub.RsWarmupCurveStep)
+	isCustom := r.Curve == string(mm_pubsub.RsWarmupCurveCustom)
 	if err := validation.ValidateStruct(&r,
 		validation.Field(&r.IntervalMins, validation.Min(int64(1))),
 		validation.Field(&r.IntervalSessReqs, validation.Min(int64(1))),
@@ -29,9 +36,29 @@ func (r rsWarmupPhaseDto) validate() error {
 			v := value.(rsFlowGoalDto)
 			return v.validate()
 		}))),
+		validation.Field(&r.Curve, validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableRsWarmupCurve)...)),
+		validation.Field(&r.Doublings, validation.When(isExponential, validation.Required, validation.Min(int64(1)), validation.Max(int64(20))).Else(validation.Nil)),
+		validation.Field(&r.Steps, validation.When(isStep, validation.Required, validation.Min(int64(1)), validation.Max(int64(100))).Else(validation.Nil)),
+		validation.Field(&r.Checkpoints, validation.When(isCustom, validation.Required, validation.Length(1, 0), validation.Each(validation.By(func(value interface{}) error {
+			v := value.(rsWarmupCheckpointDto)
+			return v.validate()
+		}))).Else(validation.Empty)),
 	); err != nil {
 		return err
 	}
+	// Check checkpoints move forward within the Warmup interval
+	interval := r.IntervalMins
+	if interval == nil {
+		interval = r.IntervalSessReqs
+	}
+	for i, checkpoint := range r.Checkpoints {
+		if checkpoint.Offset >= *interval {
+			return errors.New("checkpoints must have an offset lower than the warmup interval")
+		}
+		if i > 0 && (checkpoint.Offset <= r.Checkpoints[i-1].Offset || checkpoint.ProgressPct < r.Checkpoints[i-1].ProgressPct) {
+			return errors.New("checkpoints must have increasing offsets and non-decreasing progress")
+		}
+	}
 	// Check there is only one goal per Flow
 	seen := make(map[string]bool)
 	totPct := 0.0
@@ -53,10 +80,45 @@ func (r rsWarmupPhaseDto) toEntity() mm_pubsub.RsWarmupPhase {
 	for i, goal := range r.Goals {
 		goals[i] = goal.toEntity()
 	}
+	// Linear is the default curve, for backward compatibility
+	curve := mm_pubsub.RsWarmupCurveLinear
+	if r.Curve != "" {
+		curve = mm_pubsub.RsWarmupCurve(r.Curve)
+	}
+	var checkpoints []mm_pubsub.RsWarmupCheckpoint
+	if len(r.Checkpoints) > 0 {
+		checkpoints = make([]mm_pubsub.RsWarmupCheckpoint, len(r.Checkpoints))
+		for i, checkpoint := range r.Checkpoints {
+			checkpoints[i] = checkpoint.toEntity()
+		}
+	}
 	return mm_pubsub.RsWarmupPhase{
 		IntervalMins:     r.IntervalMins,
 		IntervalSessReqs: r.IntervalSessReqs,
 		Goals:            goals,
+		Curve:            curve,
+		Doublings:        r.Doublings,
+		Steps:            r.Steps,
+		Checkpoints:      checkpoints,
+	}
+}
+
+type rsWarmupCheckpointDto struct {
+	Offset      int64   `json:"offset"`
+	ProgressPct float64 `json:"progressPct"`
+}
+
+func (r rsWarmupCheckpointDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Offset, validation.Required, validation.Min(int64(1))),
+		validation.Field(&r.ProgressPct, validation.Min(0.0), validation.Max(100.0)),
+	)
+}
+
+func (r rsWarmupCheckpointDto) toEntity() mm_pubsub.RsWarmupCheckpoint {
+	return mm_pubsub.RsWarmupCheckpoint{
+		Offset:      r.Offset,
+		ProgressPct: r.ProgressPct,
 	}
 }
 
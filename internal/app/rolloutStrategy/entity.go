@@ -1,7 +1,54 @@
 package rolloutStrategy
 
 import (
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/google/uuid"
 )
 
 type rolloutStrategyEntity mm_pubsub.RolloutStrategyEventEntity
+
+type flowEntity struct {
+	ID              uuid.UUID `json:"id"`
+	UseCaseID       uuid.UUID `json:"useCaseId"`
+	Active          bool      `json:"active"`
+	CurrentServePct *float64  `json:"currentServePct"`
+}
+
+type rolloutStrategyDetailEntity struct {
+	rolloutStrategyEntity
+	Significance rsSignificanceEntity `json:"significance"`
+}
+
+type rsSignificanceEntity struct {
+	BestFlowID    *uuid.UUID `json:"bestFlowId"`
+	Confidence    float64    `json:"confidence"`
+	MinConfidence *float64   `json:"minConfidence"`
+	Significant   bool       `json:"significant"`
+}
+
+type feedbackSummaryEntity struct {
+	FlowID      uuid.UUID `json:"flowId"`
+	TotFeedback int64     `json:"totFeedback"`
+	AvgScore    float64   `json:"avgScore"`
+	VarScore    float64   `json:"varScore"`
+}
+
+type rsHistorySource string
+
+type rolloutStrategyHistoryEntity struct {
+	ID                uuid.UUID                           `json:"id"`
+	RolloutStrategyID uuid.UUID                           `json:"rolloutStrategyId"`
+	UseCaseID         uuid.UUID                           `json:"useCaseId"`
+	Source            rsHistorySource                     `json:"source"`
+	FromState         mm_pubsub.RolloutState              `json:"fromState"`
+	ToState           mm_pubsub.RolloutState              `json:"toState"`
+	Flows             []mm_pubsub.RsEngineFlowEventEntity `json:"flows"`
+	Actor             *string                             `json:"actor"`
+	EscapeTrigger     *mm_pubsub.RsEscapeTrigger          `json:"escapeTrigger"`
+	CreatedAt         time.Time                           `json:"createdAt"`
+}
+
+type rsSimulationEntity mm_rsengine.SimulationResult
@@ -7,3 +7,4 @@ var errRolloutStrategyNotFound = errors.New("rollout-strategy-not-found")
 var errRolloutStrategyAlreadyExists = errors.New("rollout-strategy-already-exists")
 var errRolloutStrategyNotEditableWhileActive = errors.New("rollout-strategy-not-editable-while-active")
 var errRolloutStrategyTransitionStateNotAllowed = errors.New("rollout-strategy-transition-state-not-allowed")
+var errSimulationFlowsMismatch = errors.New("simulation-flows-mismatch")
@@ -16,6 +16,21 @@ func (m useCaseModel) TableName() string {
 	return "mm_use_case"
 }
 
+type flowModel struct {
+	ID              uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+	UseCaseID       uuid.UUID `gorm:"column:use_case_id;type:varchar(36)"`
+	Active          bool      `gorm:"column:active;type:bool"`
+	CurrentServePct *float64  `gorm:"column:current_pct;type:double precision"`
+}
+
+func (m flowModel) TableName() string {
+	return "mm_flow"
+}
+
+func (m flowModel) toEntity() flowEntity {
+	return flowEntity(m)
+}
+
 type rolloutStrategyModel struct {
 	ID            uuid.UUID              `gorm:"primaryKey;column:id;type:varchar(36)"`
 	UseCaseID     uuid.UUID              `gorm:"column:use_case_id;type:varchar(36)"`
@@ -59,3 +74,75 @@ func (m *rolloutStrategyModel) fromEntity(e rolloutStrategyEntity) error {
 		return nil
 	}
 }
+
+type rolloutStrategyHistoryModel struct {
+	ID                uuid.UUID              `gorm:"primaryKey;column:id;type:varchar(36)"`
+	RolloutStrategyID uuid.UUID              `gorm:"column:rollout_strategy_id;type:varchar(36)"`
+	UseCaseID         uuid.UUID              `gorm:"column:use_case_id;type:varchar(36)"`
+	Source            rsHistorySource        `gorm:"column:source;type:varchar(32)"`
+	FromState         mm_pubsub.RolloutState `gorm:"column:from_state;type:rollout_state"`
+	ToState           mm_pubsub.RolloutState `gorm:"column:to_state;type:rollout_state"`
+	Flows             json.RawMessage        `gorm:"column:flows;type:json"`
+	Actor             *string                `gorm:"column:actor;type:varchar(255)"`
+	EscapeTrigger     json.RawMessage        `gorm:"column:escape_trigger;type:json"`
+	CreatedAt         time.Time              `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
+}
+
+func (m rolloutStrategyHistoryModel) TableName() string {
+	return "mm_rollout_strategy_history"
+}
+
+func (m rolloutStrategyHistoryModel) toEntity() rolloutStrategyHistoryEntity {
+	// Remap the stored JSON Flows in the object list
+	flows := []mm_pubsub.RsEngineFlowEventEntity{}
+	if err := json.Unmarshal(m.Flows, &flows); err != nil {
+		return rolloutStrategyHistoryEntity{}
+	}
+	var escapeTrigger *mm_pubsub.RsEscapeTrigger
+	if len(m.EscapeTrigger) > 0 {
+		if err := json.Unmarshal(m.EscapeTrigger, &escapeTrigger); err != nil {
+			return rolloutStrategyHistoryEntity{}
+		}
+	}
+	return rolloutStrategyHistoryEntity{
+		ID:                m.ID,
+		RolloutStrategyID: m.RolloutStrategyID,
+		UseCaseID:         m.UseCaseID,
+		Source:            m.Source,
+		FromState:         m.FromState,
+		ToState:           m.ToState,
+		Flows:             flows,
+		Actor:             m.Actor,
+		EscapeTrigger:     escapeTrigger,
+		CreatedAt:         m.CreatedAt,
+	}
+}
+
+func (m *rolloutStrategyHistoryModel) fromEntity(e rolloutStrategyHistoryEntity) error {
+	// Convert the object list in JSON for saving
+	if e.Flows == nil {
+		e.Flows = []mm_pubsub.RsEngineFlowEventEntity{}
+	}
+	flows, err := json.Marshal(e.Flows)
+	if err != nil {
+		return err
+	}
+	// The Escape trigger is stored only when an Escape rule fired
+	if e.EscapeTrigger != nil {
+		escapeTrigger, err := json.Marshal(e.EscapeTrigger)
+		if err != nil {
+			return err
+		}
+		m.EscapeTrigger = escapeTrigger
+	}
+	m.ID = e.ID
+	m.RolloutStrategyID = e.RolloutStrategyID
+	m.UseCaseID = e.UseCaseID
+	m.Source = e.Source
+	m.FromState = e.FromState
+	m.ToState = e.ToState
+	m.Flows = flows
+	m.Actor = e.Actor
+	m.CreatedAt = e.CreatedAt
+	return nil
+}
@@ -1,6 +1,8 @@
 package rolloutStrategy
 
 import (
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/google/uuid"
@@ -12,6 +14,9 @@ type rolloutStrategyRepositoryInterface interface {
 	checkUseCaseExists(tx *gorm.DB, useCaseID uuid.UUID) (bool, error)
 	getRolloutStrategyByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, forUpdate bool) (rolloutStrategyEntity, error)
 	saveRolloutStrategy(tx *gorm.DB, rolloutStrategy rolloutStrategyEntity, operation mm_db.SaveOperation) (rolloutStrategyEntity, error)
+	getActiveFlowsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowEntity, error)
+	createRolloutStrategyHistory(tx *gorm.DB, history rolloutStrategyHistoryEntity) error
+	listRolloutStrategyHistory(tx *gorm.DB, useCaseID uuid.UUID, from *time.Time, to *time.Time, source *rsHistorySource, limit int, offset int) ([]rolloutStrategyHistoryEntity, int64, error)
 }
 
 type rolloutStrategyRepository struct {
@@ -68,3 +73,54 @@ func (r rolloutStrategyRepository) saveRolloutStrategy(tx *gorm.DB, rolloutStrat
 	}
 	return rolloutStrategy, nil
 }
+
+func (r rolloutStrategyRepository) getActiveFlowsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowEntity, error) {
+	var models []flowModel
+	query := tx.Model(flowModel{}).Where("use_case_id = ?", useCaseID).Where("active IS TRUE").Order("created_at ASC")
+	result := query.Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]flowEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+func (r rolloutStrategyRepository) createRolloutStrategyHistory(tx *gorm.DB, history rolloutStrategyHistoryEntity) error {
+	var model rolloutStrategyHistoryModel
+	if err := model.fromEntity(history); err != nil {
+		return err
+	}
+	return tx.Create(model).Error
+}
+
+func (r rolloutStrategyRepository) listRolloutStrategyHistory(tx *gorm.DB, useCaseID uuid.UUID, from *time.Time, to *time.Time, source *rsHistorySource, limit int, offset int) ([]rolloutStrategyHistoryEntity, int64, error) {
+	var totalCount int64
+	var models []*rolloutStrategyHistoryModel
+	query := tx.Model(rolloutStrategyHistoryModel{}).Where("use_case_id = ?", useCaseID)
+	queryCount := tx.Model(rolloutStrategyHistoryModel{}).Where("use_case_id = ?", useCaseID)
+	if from != nil {
+		query.Where("created_at >= ?", *from)
+		queryCount.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query.Where("created_at < ?", *to)
+		queryCount.Where("created_at < ?", *to)
+	}
+	if source != nil {
+		query.Where("source = ?", *source)
+		queryCount.Where("source = ?", *source)
+	}
+	result := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&models)
+	queryCount.Count(&totalCount)
+	if result.Error != nil {
+		return []rolloutStrategyHistoryEntity{}, 0, result.Error
+	}
+	var entities []rolloutStrategyHistoryEntity = []rolloutStrategyHistoryEntity{}
+	for _, model := range models {
+		entities = append(entities, model.toEntity())
+	}
+	return entities, totalCount, nil
+}
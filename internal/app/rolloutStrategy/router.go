@@ -124,4 +124,68 @@ func (r rolloutStrategyRouter) register(router *gin.RouterGroup) {
 			}
 			mm_router.ReturnOk(ctx, &gin.H{"item": item})
 		})
+
+	router.POST(
+		"/use-cases/:useCaseId/rollout-strategy/simulate",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(10)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request simulateRolloutStrategyInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.simulateRolloutStrategy(ctx, request)
+			if err == errRolloutStrategyNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			if err == errSimulationFlowsMismatch {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "rollout-strategy-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
+
+	router.GET(
+		"/use-cases/:useCaseId/rollout-strategy/history",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(1)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request listRolloutStrategyHistoryInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			items, totalCount, err := r.service.listRolloutStrategyHistory(ctx, request)
+			if err == errRolloutStrategyNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "rollout-strategy-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"items": items, "totalCount": totalCount, "hasNext": mm_router.HasNext(request.Page, request.PageSize, totalCount)})
+		})
 }
@@ -1,11 +1,16 @@
 package rolloutStrategy
 
 import (
+	"context"
+	"math/rand/v2"
 	"time"
 
+	"github.com/ai-model-match/backend/internal/pkg/mm_auth"
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_err"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/ai-model-match/backend/internal/pkg/mm_statistics"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,11 +18,13 @@ import (
 )
 
 type rolloutStrategyServiceInterface interface {
-	getRolloutStrategyByUseCaseID(ctx *gin.Context, input getRolloutStrategyInputDto) (rolloutStrategyEntity, error)
-	createRolloutStrategy(useCaseID uuid.UUID) (rolloutStrategyEntity, error)
+	getRolloutStrategyByUseCaseID(ctx *gin.Context, input getRolloutStrategyInputDto) (rolloutStrategyDetailEntity, error)
+	createRolloutStrategy(ctx context.Context, useCaseID uuid.UUID) (rolloutStrategyEntity, error)
 	updateRolloutStrategyConfig(ctx *gin.Context, input updateRolloutStrategyInputDto) (rolloutStrategyEntity, error)
 	updateRolloutStrategyState(ctx *gin.Context, input updateRolloutStrategyStatusInputDto) (rolloutStrategyEntity, error)
-	updateRolloutStrategyFromEvent(event mm_pubsub.RsEngineEventEntity) error
+	updateRolloutStrategyFromEvent(ctx context.Context, event mm_pubsub.RsEngineEventEntity) error
+	simulateRolloutStrategy(ctx *gin.Context, input simulateRolloutStrategyInputDto) (rsSimulationEntity, error)
+	listRolloutStrategyHistory(ctx *gin.Context, input listRolloutStrategyHistoryInputDto) ([]rolloutStrategyHistoryEntity, int64, error)
 }
 
 type rolloutStrategyService struct {
@@ -34,23 +41,39 @@ func newRolloutStrategyService(storage *gorm.DB, pubSubAgent *mm_pubsub.PubSubAg
 	}
 }
 
-func (s rolloutStrategyService) getRolloutStrategyByUseCaseID(ctx *gin.Context, input getRolloutStrategyInputDto) (rolloutStrategyEntity, error) {
+func (s rolloutStrategyService) getRolloutStrategyByUseCaseID(ctx *gin.Context, input getRolloutStrategyInputDto) (rolloutStrategyDetailEntity, error) {
 	useCaseID := uuid.MustParse(input.UseCaseID)
 	item, err := s.repository.getRolloutStrategyByUseCaseID(s.storage, useCaseID, false)
 	if err != nil {
-		return rolloutStrategyEntity{}, mm_err.ErrGeneric
+		return rolloutStrategyDetailEntity{}, mm_err.ErrGeneric
 	}
 	if mm_utils.IsEmpty(item) {
-		return rolloutStrategyEntity{}, errRolloutStrategyNotFound
+		return rolloutStrategyDetailEntity{}, errRolloutStrategyNotFound
 	}
-	return item, nil
+	// Evaluate how significant is the current best Flow based on the same feedback used by the engine
+	engineSummaries, err := mm_statistics.FeedbackSummaries(s.storage, useCaseID, item.Configuration, time.Now())
+	if err != nil {
+		return rolloutStrategyDetailEntity{}, mm_err.ErrGeneric
+	}
+	summaries := make([]feedbackSummaryEntity, len(engineSummaries))
+	for i, summary := range engineSummaries {
+		summaries[i] = feedbackSummaryEntity(summary)
+	}
+	return rolloutStrategyDetailEntity{
+		rolloutStrategyEntity: item,
+		Significance:          calculateSignificance(item.Configuration.Adaptive, summaries),
+	}, nil
 }
 
-func (s rolloutStrategyService) createRolloutStrategy(useCaseID uuid.UUID) (rolloutStrategyEntity, error) {
+func (s rolloutStrategyService) createRolloutStrategy(ctx context.Context, useCaseID uuid.UUID) (rolloutStrategyEntity, error) {
 	now := time.Now()
 	var newRolloutStrategy rolloutStrategyEntity
 	eventsToPublish := []mm_pubsub.EventToPublish{}
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
 		// Retrieve and check if the related Use Case exists
 		exists, err := s.repository.checkUseCaseExists(tx, useCaseID)
 		if err != nil {
@@ -76,6 +99,7 @@ func (s rolloutStrategyService) createRolloutStrategy(useCaseID uuid.UUID) (roll
 				Warmup: nil,
 				Escape: nil,
 				Adaptive: mm_pubsub.RsAdaptivePhase{
+					Algorithm:    mm_pubsub.RsAdaptiveAlgorithmGreedy,
 					MinFeedback:  0,
 					MaxStepPct:   10,
 					IntervalMins: 10,
@@ -137,23 +161,8 @@ func (s rolloutStrategyService) updateRolloutStrategyConfig(ctx *gin.Context, in
 		if updatedRolloutStrategy.RolloutState != mm_pubsub.RolloutStateInit {
 			return errRolloutStrategyNotEditableWhileActive
 		}
-		// Round decimals on percentages for Warmup
-		if !mm_utils.IsEmpty(input.Configuration.Warmup) {
-			for i := range input.Configuration.Warmup.Goals {
-				input.Configuration.Warmup.Goals[i].FinalServePct = (mm_utils.RoundTo2DecimalsPtr(input.Configuration.Warmup.Goals[i].FinalServePct))
-			}
-		}
-		// Round decimals on percentages for Escape
-		if !mm_utils.IsEmpty(input.Configuration.Escape) {
-			for i := range input.Configuration.Escape.Rules {
-				input.Configuration.Escape.Rules[i].LowerScore = (mm_utils.RoundTo2DecimalsPtr(input.Configuration.Escape.Rules[i].LowerScore))
-				for j := range input.Configuration.Escape.Rules[i].Rollback {
-					input.Configuration.Escape.Rules[i].Rollback[j].FinalServePct = (mm_utils.RoundTo2DecimalsPtr(input.Configuration.Escape.Rules[i].Rollback[j].FinalServePct))
-				}
-			}
-		}
-		// Round decimals on percentages for Adaptive
-		input.Configuration.Adaptive.MaxStepPct = (mm_utils.RoundTo2Decimals(input.Configuration.Adaptive.MaxStepPct))
+		// Round decimals on percentages
+		input.Configuration.roundPercentages()
 		// Update the configuration
 		updatedRolloutStrategy.Configuration = input.Configuration.toEntity()
 		// Save Rollout Strategy
@@ -213,26 +222,86 @@ func (s rolloutStrategyService) updateRolloutStrategyState(ctx *gin.Context, inp
 		} else {
 			return errRolloutStrategyTransitionStateNotAllowed
 		}
+		currentStateConfigs := currentRolloutStrategy.Configuration.StateConfigurations
+		isResume := currentRolloutStrategy.RolloutState == mm_pubsub.RolloutStatePaused &&
+			(updatedRolloutStrategy.RolloutState == mm_pubsub.RolloutStateWarmup || updatedRolloutStrategy.RolloutState == mm_pubsub.RolloutStateAdaptive)
+		// A paused Rollout Strategy can only be resumed into the phase it has been paused from
+		if isResume && (currentStateConfigs.PausedFromState == nil || *currentStateConfigs.PausedFromState != updatedRolloutStrategy.RolloutState) {
+			return errRolloutStrategyTransitionStateNotAllowed
+		}
 		// Now, if we are activating Rollout Strategy (from INIT to WARMUP), but there is no warmup config, move to ADAPT
-		if updatedRolloutStrategy.RolloutState == mm_pubsub.RolloutStateWarmup && mm_utils.IsEmpty(updatedRolloutStrategy.Configuration.Warmup) {
+		if !isResume && updatedRolloutStrategy.RolloutState == mm_pubsub.RolloutStateWarmup && mm_utils.IsEmpty(updatedRolloutStrategy.Configuration.Warmup) {
 			updatedRolloutStrategy.RolloutState = mm_pubsub.RolloutStateAdaptive
 		}
-		// Now check the status, if it moved to FORCED_COMPLETED, add in configuration the Flow ID, otherwise cleanup it
-		if updatedRolloutStrategy.RolloutState == mm_pubsub.RolloutStateForcedCompleted {
+		// Now check the status and keep in configuration what is needed by the new state, otherwise cleanup it
+		switch {
+		case updatedRolloutStrategy.RolloutState == mm_pubsub.RolloutStateForcedCompleted:
+			// Keep the Flow ID to complete to
 			completedFlowID := mm_utils.GetUUIDFromString(*input.CompletedFlowID)
 			updatedRolloutStrategy.Configuration.StateConfigurations = mm_pubsub.StateConfigurations{
 				CompletedFlowID: &completedFlowID,
 			}
-		} else {
+		case updatedRolloutStrategy.RolloutState == mm_pubsub.RolloutStatePaused:
+			// Keep the paused phase and when it started, to resume it later
+			pausedFromState := currentRolloutStrategy.RolloutState
+			phaseStartedAt := mm_rsengine.PhaseStartedAt(currentRolloutStrategy.Configuration, currentRolloutStrategy.UpdatedAt)
+			updatedRolloutStrategy.Configuration.StateConfigurations = mm_pubsub.StateConfigurations{
+				PausedFromState: &pausedFromState,
+				PausedAt:        &now,
+				PhaseStartedAt:  &phaseStartedAt,
+			}
+		case isResume:
+			// Shift the start of the resumed phase by the paused duration, so statistics and timing are preserved
+			phaseStartedAt := now
+			if currentStateConfigs.PhaseStartedAt != nil && currentStateConfigs.PausedAt != nil {
+				phaseStartedAt = currentStateConfigs.PhaseStartedAt.Add(now.Sub(*currentStateConfigs.PausedAt))
+			}
+			updatedRolloutStrategy.Configuration.StateConfigurations = mm_pubsub.StateConfigurations{
+				PhaseStartedAt: &phaseStartedAt,
+			}
+		default:
 			// CleanUp fields
 			updatedRolloutStrategy.Configuration.StateConfigurations = mm_pubsub.StateConfigurations{}
 		}
+		// A Rollout Strategy started manually does not need its scheduled start anymore
+		if currentRolloutStrategy.RolloutState == mm_pubsub.RolloutStateInit {
+			updatedRolloutStrategy.Configuration.ScheduledStartAt = nil
+		}
 
 		// Save Rollout Strategy
 		updatedRolloutStrategy.UpdatedAt = now
 		if _, err := s.repository.saveRolloutStrategy(tx, updatedRolloutStrategy, mm_db.Update); err != nil {
 			return mm_err.ErrGeneric
 		}
+		// Track the transition in the history, together with who forced it and the serve PCTs in effect
+		var actor *string
+		if user := mm_auth.GetAuthenticatedUserFromSession(ctx); user != nil {
+			actor = &user.Username
+		}
+		activeFlows, err := s.repository.getActiveFlowsByUseCaseID(tx, updatedRolloutStrategy.UseCaseID)
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		flows := make([]mm_pubsub.RsEngineFlowEventEntity, len(activeFlows))
+		for i, flow := range activeFlows {
+			flows[i] = mm_pubsub.RsEngineFlowEventEntity{FlowID: flow.ID}
+			if flow.CurrentServePct != nil {
+				flows[i].CurrentServePct = *flow.CurrentServePct
+			}
+		}
+		if err := s.repository.createRolloutStrategyHistory(tx, rolloutStrategyHistoryEntity{
+			ID:                uuid.New(),
+			RolloutStrategyID: updatedRolloutStrategy.ID,
+			UseCaseID:         updatedRolloutStrategy.UseCaseID,
+			Source:            rsHistorySourceManual,
+			FromState:         currentRolloutStrategy.RolloutState,
+			ToState:           updatedRolloutStrategy.RolloutState,
+			Flows:             flows,
+			Actor:             actor,
+			CreatedAt:         now,
+		}); err != nil {
+			return mm_err.ErrGeneric
+		}
 		// Send an event of Rollout Straregy updated
 		if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRolloutStrategyV1, mm_pubsub.PubSubMessage{
 			Message: mm_pubsub.PubSubEvent{
@@ -264,25 +333,53 @@ func (s rolloutStrategyService) updateRolloutStrategyState(ctx *gin.Context, inp
 	return updatedRolloutStrategy, nil
 }
 
-func (s rolloutStrategyService) updateRolloutStrategyFromEvent(event mm_pubsub.RsEngineEventEntity) error {
+func (s rolloutStrategyService) updateRolloutStrategyFromEvent(ctx context.Context, event mm_pubsub.RsEngineEventEntity) error {
 	now := time.Now()
 	var updatedRolloutStrategy rolloutStrategyEntity
 	eventsToPublish := []mm_pubsub.EventToPublish{}
-	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
+		}
 		// Check if the use Case exists
 		currentRolloutStrategy, err := s.repository.getRolloutStrategyByUseCaseID(tx, event.UseCaseID, true)
 		if err != nil {
 			return mm_err.ErrGeneric
 		} else if mm_utils.IsEmpty(currentRolloutStrategy) {
 			return errRolloutStrategyNotFound
-		} else if currentRolloutStrategy.RolloutState == event.RolloutState {
+		}
+		if !event.AppliesTo(currentRolloutStrategy.RolloutState) {
+			// The Rollout Strategy has been paused or moved to another state after the engine evaluated it
+			return nil
+		}
+		// Track every allocation applied by the engine in the history
+		if err := s.repository.createRolloutStrategyHistory(tx, rolloutStrategyHistoryEntity{
+			ID:                uuid.New(),
+			RolloutStrategyID: currentRolloutStrategy.ID,
+			UseCaseID:         currentRolloutStrategy.UseCaseID,
+			Source:            rsHistorySourceEngine,
+			FromState:         currentRolloutStrategy.RolloutState,
+			ToState:           event.RolloutState,
+			Flows:             event.Flows,
+			EscapeTrigger:     event.EscapeTrigger,
+			CreatedAt:         now,
+		}); err != nil {
+			return mm_err.ErrGeneric
+		}
+		if currentRolloutStrategy.RolloutState == event.RolloutState {
 			// If the stat didn't change, no updates
 			return nil
 		} else {
 			updatedRolloutStrategy = currentRolloutStrategy
 		}
-		// Save Rollout Strategy
+		// Save Rollout Strategy, a new phase starts now
 		updatedRolloutStrategy.RolloutState = event.RolloutState
+		updatedRolloutStrategy.Configuration.StateConfigurations.PhaseStartedAt = nil
+		if currentRolloutStrategy.RolloutState == mm_pubsub.RolloutStateInit {
+			// The scheduled start has been applied
+			updatedRolloutStrategy.Configuration.ScheduledStartAt = nil
+		}
 		updatedRolloutStrategy.UpdatedAt = now
 		if _, err := s.repository.saveRolloutStrategy(tx, updatedRolloutStrategy, mm_db.Update); err != nil {
 			return mm_err.ErrGeneric
@@ -317,3 +414,79 @@ func (s rolloutStrategyService) updateRolloutStrategyFromEvent(event mm_pubsub.R
 	}
 	return nil
 }
+
+/*
+Run the Rollout Strategy engine in virtual time on the active Flows of the Use Case, using the given
+configuration and synthetic traffic. Nothing is stored and no event is sent.
+*/
+func (s rolloutStrategyService) simulateRolloutStrategy(ctx *gin.Context, input simulateRolloutStrategyInputDto) (rsSimulationEntity, error) {
+	useCaseID := uuid.MustParse(input.UseCaseID)
+	item, err := s.repository.getRolloutStrategyByUseCaseID(s.storage, useCaseID, false)
+	if err != nil {
+		return rsSimulationEntity{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(item) {
+		return rsSimulationEntity{}, errRolloutStrategyNotFound
+	}
+	flows, err := s.repository.getActiveFlowsByUseCaseID(s.storage, useCaseID)
+	if err != nil {
+		return rsSimulationEntity{}, mm_err.ErrGeneric
+	}
+	// Score distributions must be provided for all and only the active Flows
+	indexedDistributions := map[uuid.UUID]rsSimulationFlowDto{}
+	for _, flow := range input.Flows {
+		indexedDistributions[mm_utils.GetUUIDFromString(flow.FlowID)] = flow
+	}
+	if len(indexedDistributions) != len(flows) {
+		return rsSimulationEntity{}, errSimulationFlowsMismatch
+	}
+	simulationFlows := make([]mm_rsengine.SimulationFlow, len(flows))
+	for i, flow := range flows {
+		distribution, ok := indexedDistributions[flow.ID]
+		if !ok {
+			return rsSimulationEntity{}, errSimulationFlowsMismatch
+		}
+		simulationFlows[i] = mm_rsengine.SimulationFlow{
+			ID:          flow.ID,
+			AvgScore:    distribution.AvgScore,
+			StdDevScore: distribution.StdDevScore,
+		}
+		if flow.CurrentServePct != nil {
+			simulationFlows[i].InitialServePct = *flow.CurrentServePct
+		}
+	}
+	// Without a seed, each simulation draws different scores
+	seed := rand.Uint64()
+	if input.Seed != nil {
+		seed = *input.Seed
+	}
+	result := mm_rsengine.Simulate(input.Configuration.toEntity(), simulationFlows, mm_rsengine.SimulationParameters{
+		DurationMins:          input.DurationMins,
+		SessionRequestsPerMin: input.SessionRequestsPerMin,
+		FeedbackRate:          input.FeedbackRate,
+		Seed:                  seed,
+	})
+	return rsSimulationEntity(result), nil
+}
+
+func (s rolloutStrategyService) listRolloutStrategyHistory(ctx *gin.Context, input listRolloutStrategyHistoryInputDto) ([]rolloutStrategyHistoryEntity, int64, error) {
+	useCaseID := uuid.MustParse(input.UseCaseID)
+	item, err := s.repository.getRolloutStrategyByUseCaseID(s.storage, useCaseID, false)
+	if err != nil {
+		return []rolloutStrategyHistoryEntity{}, 0, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(item) {
+		return []rolloutStrategyHistoryEntity{}, 0, errRolloutStrategyNotFound
+	}
+	var source *rsHistorySource
+	if input.Source != nil {
+		historySource := rsHistorySource(*input.Source)
+		source = &historySource
+	}
+	limit, offset := mm_utils.PagePageSizeToLimitOffset(input.Page, input.PageSize)
+	items, totalCount, err := s.repository.listRolloutStrategyHistory(s.storage, useCaseID, mm_utils.GetOptionalTimeFromString(input.From), mm_utils.GetOptionalTimeFromString(input.To), source, limit, offset)
+	if err != nil || items == nil {
+		return []rolloutStrategyHistoryEntity{}, 0, mm_err.ErrGeneric
+	}
+	return items, totalCount, nil
+}
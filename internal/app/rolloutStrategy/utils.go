@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_stats"
 )
 
 /*
@@ -17,3 +18,40 @@ func checkStateFlow(currentState mm_pubsub.RolloutState, nextState mm_pubsub.Rol
 	}
 	return false
 }
+
+/*
+Evaluate the confidence that the active Flow with the highest average score is better than all the other
+active Flows, based on a two-sample test on their feedback scores.
+*/
+func calculateSignificance(adaptive mm_pubsub.RsAdaptivePhase, summaries []feedbackSummaryEntity) rsSignificanceEntity {
+	significance := rsSignificanceEntity{
+		MinConfidence: adaptive.MinConfidence,
+	}
+	bestIndex := -1
+	for i, summary := range summaries {
+		if summary.TotFeedback > 0 && (bestIndex < 0 || summary.AvgScore > summaries[bestIndex].AvgScore) {
+			bestIndex = i
+		}
+	}
+	if bestIndex < 0 {
+		return significance
+	}
+	best := mm_stats.Sample{}
+	others := []mm_stats.Sample{}
+	for i, summary := range summaries {
+		sample := mm_stats.Sample{
+			Count:    summary.TotFeedback,
+			Mean:     summary.AvgScore,
+			Variance: summary.VarScore,
+		}
+		if i == bestIndex {
+			best = sample
+		} else {
+			others = append(others, sample)
+		}
+	}
+	significance.BestFlowID = &summaries[bestIndex].FlowID
+	significance.Confidence = mm_stats.Confidence(best, others)
+	significance.Significant = adaptive.MinConfidence != nil && significance.Confidence >= *adaptive.MinConfidence
+	return significance
+}
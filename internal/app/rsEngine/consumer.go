@@ -25,7 +25,7 @@ func newRsEngineConsumer(pubSub *mm_pubsub.PubSubAgent, service rsEngineServiceI
 
 func (r rsEngineConsumer) subscribe() {
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowStatisticsV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicFlowStatisticsV1, "rs-engine")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -55,8 +55,9 @@ func (r rsEngineConsumer) subscribe() {
 					return
 				}
 				event := msg.Message.EventEntity.(*mm_pubsub.FlowStatisticsEventEntity)
-				if err := r.service.onFlowStatisticsUpdate(*event, msg.Message.EventChangedFields); err != nil {
+				if err := r.service.onFlowStatisticsUpdate(msg.Message.Context(), *event, msg.Message.EventChangedFields); err != nil {
 					zap.L().Error("Impossible to run the rsEngine for the new updated statistics", zap.String("service", "rs-engine-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
@@ -64,7 +65,7 @@ func (r rsEngineConsumer) subscribe() {
 	}()
 
 	go func() {
-		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRolloutStrategyV1)
+		messageChannel := r.pubSub.Subscribe(mm_pubsub.TopicRolloutStrategyV1, "rs-engine")
 		isChannelOpen := true
 		for isChannelOpen {
 			func() {
@@ -94,8 +95,9 @@ func (r rsEngineConsumer) subscribe() {
 					return
 				}
 				event := msg.Message.EventEntity.(*mm_pubsub.RolloutStrategyEventEntity)
-				if err := r.service.onRolloutStrategyChangeState(*event); err != nil {
+				if err := r.service.onRolloutStrategyChangeState(msg.Message.Context(), *event); err != nil {
 					zap.L().Error("Impossible to run the rsEngine for the new updated statistics", zap.String("service", "rs-engine-consumer"))
+					msg.Message.EventState.Fail(err)
 					return
 				}
 			}()
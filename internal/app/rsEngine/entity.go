@@ -22,6 +22,16 @@ type flowStatisticsEntity struct {
 	TotSessionRequests int64     `json:"totSessionRequests"`
 	TotFeedback        int64     `json:"totFeedback"`
 	AvgScore           float64   `json:"avgScore"`
+	TotOneStarFeedback int64     `json:"totOneStarFeedback"`
+	TotErrors          int64     `json:"totErrors"`
+	TotAbandons        int64     `json:"totAbandons"`
+}
+
+type feedbackSummaryEntity struct {
+	FlowID      uuid.UUID `json:"flowId"`
+	TotFeedback int64     `json:"totFeedback"`
+	AvgScore    float64   `json:"avgScore"`
+	VarScore    float64   `json:"varScore"`
 }
 
 type rolloutStrategyEntity struct {
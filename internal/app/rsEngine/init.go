@@ -24,5 +24,6 @@ func Init(envs *mm_env.Envs, dbStorage *gorm.DB, pubSubAgent *mm_pubsub.PubSubAg
 	consumer = newRsEngineConsumer(pubSubAgent, service)
 	scheduler.init()
 	consumer.subscribe()
+	registerMetrics(service)
 	zap.L().Info("RsEngine package initialized")
 }
@@ -0,0 +1,52 @@
+package rsEngine
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var tickDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "mm_rsengine_tick_duration_seconds",
+	Help:    "Time to run the scheduled evaluation of a Rollout Strategy, by Use Case and Rollout state",
+	Buckets: prometheus.DefBuckets,
+}, []string{"use_case_id", "state"})
+
+var servePctDesc = prometheus.NewDesc(
+	"mm_flow_serve_pct",
+	"Current serve PCT of each active Flow",
+	[]string{"use_case_id", "flow_id"}, nil,
+)
+
+/*
+servePctCollector exposes the current serve PCT of the active Flows, read from DB on each collection
+so that it is consistent across the application instances.
+*/
+type servePctCollector struct {
+	service rsEngineServiceInterface
+}
+
+func (c servePctCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- servePctDesc
+}
+
+func (c servePctCollector) Collect(ch chan<- prometheus.Metric) {
+	flows, err := c.service.getActiveFlows()
+	if err != nil {
+		zap.L().Error("Unable to collect metric", zap.String("service", "rs-engine-metrics"), zap.String("metric", "mm_flow_serve_pct"), zap.Error(err))
+		return
+	}
+	for _, flow := range flows {
+		if flow.CurrentServePct == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(servePctDesc, prometheus.GaugeValue, *flow.CurrentServePct, flow.UseCaseID.String(), flow.ID.String())
+	}
+}
+
+/*
+Register the collector of the current serve PCT of the active Flows.
+*/
+func registerMetrics(service rsEngineServiceInterface) {
+	prometheus.MustRegister(servePctCollector{service: service})
+}
@@ -31,6 +31,9 @@ type flowStatisticsModel struct {
 	TotSessionRequests int64     `gorm:"column:tot_sess_req;type:bigint"`
 	TotFeedback        int64     `gorm:"column:tot_feedback;type:bigint"`
 	AvgScore           float64   `gorm:"column:avg_score;type:double precision"`
+	TotOneStarFeedback int64     `gorm:"column:tot_one_star_feedback;type:bigint"`
+	TotErrors          int64     `gorm:"column:tot_errors;type:bigint"`
+	TotAbandons        int64     `gorm:"column:tot_abandons;type:bigint"`
 }
 
 func (m flowStatisticsModel) TableName() string {
@@ -41,6 +44,17 @@ func (m flowStatisticsModel) toEntity() flowStatisticsEntity {
 	return flowStatisticsEntity(m)
 }
 
+type feedbackSummaryModel struct {
+	FlowID      uuid.UUID `gorm:"column:flow_id;type:varchar(36)"`
+	TotFeedback int64     `gorm:"column:tot_feedback;type:bigint"`
+	AvgScore    float64   `gorm:"column:avg_score;type:double precision"`
+	VarScore    float64   `gorm:"column:var_score;type:double precision"`
+}
+
+func (m feedbackSummaryModel) toEntity() feedbackSummaryEntity {
+	return feedbackSummaryEntity(m)
+}
+
 type rolloutStrategyModel struct {
 	ID            uuid.UUID              `gorm:"primaryKey;column:id;type:varchar(36)"`
 	UseCaseID     uuid.UUID              `gorm:"column:use_case_id;type:varchar(36)"`
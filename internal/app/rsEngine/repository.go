@@ -1,6 +1,8 @@
 package rsEngine
 
 import (
+	"time"
+
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -9,8 +11,11 @@ import (
 type rsEngineRepositoryInterface interface {
 	getRolloutStrategyByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) (rolloutStrategyEntity, error)
 	getActiveRolloutStrategiesInState(tx *gorm.DB, states []mm_pubsub.RolloutState) ([]rolloutStrategyEntity, error)
+	getRolloutStrategiesWithScheduledStart(tx *gorm.DB) ([]rolloutStrategyEntity, error)
 	getActiveFlowsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowEntity, error)
+	getActiveFlows(tx *gorm.DB) ([]flowEntity, error)
 	getFlowStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowStatisticsEntity, error)
+	getFeedbackSummariesByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, since *time.Time) ([]feedbackSummaryEntity, error)
 }
 
 type rsEngineRepository struct {
@@ -47,6 +52,21 @@ func (r rsEngineRepository) getActiveRolloutStrategiesInState(tx *gorm.DB, state
 	return entities, nil
 }
 
+func (r rsEngineRepository) getRolloutStrategiesWithScheduledStart(tx *gorm.DB) ([]rolloutStrategyEntity, error) {
+	var models []rolloutStrategyModel
+	query := tx.Model(rolloutStrategyModel{}).Where("rollout_state = ?", mm_pubsub.RolloutStateInit)
+	query = query.Where("configuration->>'scheduledStartAt' IS NOT NULL")
+	result := query.Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]rolloutStrategyEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
 func (r rsEngineRepository) getActiveFlowsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowEntity, error) {
 	var models []flowModel
 	query := tx.Model(flowModel{}).Where("use_case_id = ?", useCaseID).Where("active IS TRUE")
@@ -61,6 +81,20 @@ func (r rsEngineRepository) getActiveFlowsByUseCaseID(tx *gorm.DB, useCaseID uui
 	return entities, nil
 }
 
+func (r rsEngineRepository) getActiveFlows(tx *gorm.DB) ([]flowEntity, error) {
+	var models []flowModel
+	query := tx.Model(flowModel{}).Where("active IS TRUE").Order("use_case_id ASC, id ASC")
+	result := query.Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]flowEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
 func (r rsEngineRepository) getFlowStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowStatisticsEntity, error) {
 	var models []flowStatisticsModel
 	query := tx.Model(flowStatisticsModel{}).Where("use_case_id = ?", useCaseID)
@@ -75,3 +109,26 @@ func (r rsEngineRepository) getFlowStatisticsByUseCaseID(tx *gorm.DB, useCaseID
 	}
 	return entities, nil
 }
+
+/*
+Aggregate the feedback scores of each Flow of the Use Case, to compare Flows with statistical tests
+*/
+func (r rsEngineRepository) getFeedbackSummariesByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, since *time.Time) ([]feedbackSummaryEntity, error) {
+	var models []feedbackSummaryModel
+	query := tx.Table("mm_feedback").
+		Select("flow_id, COUNT(*) AS tot_feedback, AVG(score) AS avg_score, COALESCE(VAR_SAMP(score), 0) AS var_score").
+		Where("use_case_id = ?", useCaseID)
+	if since != nil {
+		query = query.Where("created_at >= ?", since)
+	}
+	query = query.Group("flow_id")
+	result := query.Scan(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]feedbackSummaryEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
@@ -1,20 +1,25 @@
 package rsEngine
 
 import (
-	"math"
+	"context"
+	"math/rand/v2"
 	"slices"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/ai-model-match/backend/internal/pkg/mm_statistics"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type rsEngineServiceInterface interface {
-	onFlowStatisticsUpdate(event mm_pubsub.FlowStatisticsEventEntity, updatedFields []string) error
-	onRolloutStrategyChangeState(event mm_pubsub.RolloutStrategyEventEntity) error
+	onFlowStatisticsUpdate(ctx context.Context, event mm_pubsub.FlowStatisticsEventEntity, updatedFields []string) error
+	onRolloutStrategyChangeState(ctx context.Context, event mm_pubsub.RolloutStrategyEventEntity) error
 	onTimeTick() error
+	getActiveFlows() ([]flowEntity, error)
 }
 
 type rsEngineService struct {
@@ -35,197 +40,113 @@ func newRsEngineService(storage *gorm.DB, pubSubAgent *mm_pubsub.PubSubAgent, re
 Each time there is an update on Flow statistics, run the Rollout strategy evaluation on the Use Case
 and related Flows tied to this event
 */
-func (s rsEngineService) onFlowStatisticsUpdate(event mm_pubsub.FlowStatisticsEventEntity, updatedFields []string) error {
-	//
-	//	WARMUP Phase to ADAPTIVE Phase
-	//
-	if mm_utils.SliceContainsAtLeastOneOf([]string{"TotSessionRequests"}, updatedFields) {
-		// Retrieve the Rollout Strategy
-		rs, err := s.repository.getRolloutStrategyByUseCaseID(s.storage, event.UseCaseID)
-		if err != nil {
+func (s rsEngineService) onFlowStatisticsUpdate(ctx context.Context, event mm_pubsub.FlowStatisticsEventEntity, updatedFields []string) error {
+	// Start transaction
+	eventsToPublish := []mm_pubsub.EventToPublish{}
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
 			return err
 		}
-		// If the RS does not exist, skip it
-		if mm_utils.IsEmpty(rs) {
-			return nil
-		}
-		// If the RS is not in the WARMUP status, skip it
-		if rs.RolloutState != mm_pubsub.RolloutStateWarmup {
-			return nil
-		}
-		// If the Warmup configuration is not base on Traffic rules, skip it
-		if rs.Configuration.Warmup.IntervalSessReqs == nil {
-			return nil
-		}
-		// Start transaction
-		eventsToPublish := []mm_pubsub.EventToPublish{}
-		errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
-			// List of Flows without an explicit Warmup goal
-			activeFlowsWithoutGoal := []flowEntity{}
-			// Representation of Warmup goal (FlowID --> Pct Goal)
-			indexedGoals := map[string]float64{}
-			for _, goal := range rs.Configuration.Warmup.Goals {
-				indexedGoals[goal.FlowID.String()] = goal.FinalServePct
-			}
-			// Representation of Flow Statistics (FlowID --> Count Session Requests)
-			indexedStatistics := map[string]int64{}
-			// Total Count of Session Requests across all existing Flows
-			// Note: inactive Flows are included as well because they can be disabled in the middle, but the toal requests remains.
-			var totalCountSessionReqs int64 = 0
-			statistics, err := s.repository.getFlowStatisticsByUseCaseID(tx, rs.UseCaseID)
+		//
+		//	WARMUP Phase to ADAPTIVE Phase
+		//
+		if mm_utils.SliceContainsAtLeastOneOf([]string{"TotSessionRequests"}, updatedFields) {
+			states := []mm_pubsub.RolloutState{mm_pubsub.RolloutStateWarmup}
+			events, err := s.evaluateRolloutStrategy(tx, event.UseCaseID, states, func(rs rolloutStrategyEntity, flows []mm_rsengine.Flow, statistics []mm_rsengine.FlowStatistics, summaries []mm_rsengine.FeedbackSummary, windows []mm_rsengine.FeedbackWindow) (mm_pubsub.RolloutState, *mm_pubsub.RsEscapeTrigger, bool) {
+				newState, evaluated := mm_rsengine.ApplyWarmupOnTraffic(rs.RolloutState, rs.Configuration, flows, statistics)
+				return newState, nil, evaluated
+			})
 			if err != nil {
 				return err
 			}
-			for _, stat := range statistics {
-				indexedStatistics[stat.FlowID.String()] = stat.TotSessionRequests
-				totalCountSessionReqs += stat.TotSessionRequests
-			}
-			// Retrieve all active Flows for the Use Case
-			flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
+			eventsToPublish = append(eventsToPublish, events...)
+		}
+		//
+		//	WARMUP or ADAPTIVE Phase to ESCAPE Phase
+		//
+		if mm_utils.SliceContainsAtLeastOneOf([]string{"TotFeedback", "TotErrors", "TotAbandons"}, updatedFields) {
+			states := []mm_pubsub.RolloutState{mm_pubsub.RolloutStateWarmup, mm_pubsub.RolloutStateAdaptive}
+			events, err := s.evaluateRolloutStrategy(tx, event.UseCaseID, states, func(rs rolloutStrategyEntity, flows []mm_rsengine.Flow, statistics []mm_rsengine.FlowStatistics, summaries []mm_rsengine.FeedbackSummary, windows []mm_rsengine.FeedbackWindow) (mm_pubsub.RolloutState, *mm_pubsub.RsEscapeTrigger, bool) {
+				return mm_rsengine.ApplyEscape(rs.RolloutState, rs.Configuration, flows, statistics, summaries, windows)
+			})
 			if err != nil {
 				return err
 			}
-			// Indicates if all Flows have achieved their goal
-			var flowGoalAchieved bool = true
-			var totalPctReservedForGoals float64 = 0
-			activeFlowsWithoutGoalIndexes := []int{}
-			for i := range flows {
-				// Per each flow update PCT and check if there is an explicit Warmup goal achieved
-				if pctGoal, ok := indexedGoals[flows[i].ID.String()]; !ok {
-					// No explicit Warmup goal, add to the list
-					activeFlowsWithoutGoalIndexes = append(activeFlowsWithoutGoalIndexes, i)
-					continue
-				} else {
-					// Update the Flow PCT based on statistics
-					flows[i].CurrentServePct = mm_utils.Float64Ptr(calculateNewServePct(*flows[i].CurrentServePct, pctGoal, totalCountSessionReqs, *rs.Configuration.Warmup.IntervalSessReqs))
-					totalPctReservedForGoals += pctGoal
-					// Check if the goal has been achieved
-					if *flows[i].CurrentServePct != pctGoal {
-						flowGoalAchieved = false
-					}
-				}
-			}
-			// Now that we updated all Flows with an explicit Warmup goal, proceed with others
-			remainingPctPerFlow := (100.0 - totalPctReservedForGoals) / float64(len(activeFlowsWithoutGoal))
-			for _, i := range activeFlowsWithoutGoalIndexes {
-				flows[i].CurrentServePct = mm_utils.Float64Ptr(calculateNewServePct(*flows[i].CurrentServePct, remainingPctPerFlow, totalCountSessionReqs, *rs.Configuration.Warmup.IntervalSessReqs))
-				if *flows[i].CurrentServePct != remainingPctPerFlow {
-					flowGoalAchieved = false
-				}
-			}
-			// If all flows have achieved their goal, we can move to the next state
-			if flowGoalAchieved {
-				rs.RolloutState = mm_pubsub.RolloutStateAdaptive
-			}
-			// Send RS-ENGINE-UPDATE event
-			e := prepareEvent(rs, flows)
-			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
-				return err
-			} else {
-				eventsToPublish = append(eventsToPublish, event)
-			}
-			return nil
-		})
-		if errTransaction != nil {
-			return errTransaction
-		} else {
-			s.pubSubAgent.PublishBulk(eventsToPublish)
+			eventsToPublish = append(eventsToPublish, events...)
 		}
+		return nil
+	})
+	if errTransaction != nil {
+		return errTransaction
+	} else {
+		s.pubSubAgent.PublishBulk(eventsToPublish)
+	}
+	return nil
+}
+
+/*
+Load the Rollout Strategy of the Use Case, its active Flows and statistics, then run the engine step and
+persist the result, if the step evaluated the Rollout Strategy. Rollout Strategies in other states are skipped.
+Events persisted are returned to be published once the transaction is committed.
+*/
+func (s rsEngineService) evaluateRolloutStrategy(tx *gorm.DB, useCaseID uuid.UUID, states []mm_pubsub.RolloutState, step func(rs rolloutStrategyEntity, flows []mm_rsengine.Flow, statistics []mm_rsengine.FlowStatistics, summaries []mm_rsengine.FeedbackSummary, windows []mm_rsengine.FeedbackWindow) (mm_pubsub.RolloutState, *mm_pubsub.RsEscapeTrigger, bool)) ([]mm_pubsub.EventToPublish, error) {
+	// Retrieve the Rollout Strategy
+	rs, err := s.repository.getRolloutStrategyByUseCaseID(tx, useCaseID)
+	if err != nil {
+		return nil, err
+	}
+	// If the RS does not exist or it is not in one of the expected states, skip it
+	if mm_utils.IsEmpty(rs) || !slices.Contains(states, rs.RolloutState) {
+		return nil, nil
 	}
-	//
-	//	WARMUP or ADAPTIVE Phase to ESCAPE Phase
-	//
-	if mm_utils.SliceContainsAtLeastOneOf([]string{"TotFeedback"}, updatedFields) {
-		// Retrieve the Rollout Strategy
-		rs, err := s.repository.getRolloutStrategyByUseCaseID(s.storage, event.UseCaseID)
+	statistics, err := s.repository.getFlowStatisticsByUseCaseID(tx, rs.UseCaseID)
+	if err != nil {
+		return nil, err
+	}
+	// Retrieve all active Flows for the Use Case
+	flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
+	if err != nil {
+		return nil, err
+	}
+	// Summarize the recent feedback on the time windows used by the Escape guardrails, if any
+	windows := []mm_rsengine.FeedbackWindow{}
+	now := time.Now()
+	for _, windowMins := range mm_rsengine.EscapeWindowsMins(rs.Configuration) {
+		since := now.Add(-time.Duration(windowMins) * time.Minute)
+		summaries, err := s.repository.getFeedbackSummariesByUseCaseID(tx, rs.UseCaseID, &since)
 		if err != nil {
-			return err
-		}
-		// If the RS does not exist, skip it
-		if mm_utils.IsEmpty(rs) {
-			return nil
-		}
-		// If the RS is not in the WARMUP or ADAPTIVE status, skip it
-		if rs.RolloutState != mm_pubsub.RolloutStateWarmup && rs.RolloutState != mm_pubsub.RolloutStateAdaptive {
-			return nil
+			return nil, err
 		}
-		// If the Escape configuration is not defined, skip it
-		if rs.Configuration.Escape == nil {
-			return nil
-		}
-		// Start transaction
-		eventsToPublish := []mm_pubsub.EventToPublish{}
-		errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
-			// Representation of Escape rules (FlowID --> Escape Rule)
-			indexedRules := map[string]mm_pubsub.RsEscapeRule{}
-			for _, rule := range rs.Configuration.Escape.Rules {
-				indexedRules[rule.FlowID.String()] = rule
-			}
-			// Representation of Flow Statistics (FlowID --> Count Session Requests)
-			indexedStatistics := map[string]flowStatisticsEntity{}
-			statistics, err := s.repository.getFlowStatisticsByUseCaseID(tx, rs.UseCaseID)
-			if err != nil {
-				return err
-			}
-			for _, stat := range statistics {
-				indexedStatistics[stat.FlowID.String()] = stat
-			}
-			// Retrieve all active Flows for the Use Case
-			flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
-			if err != nil {
-				return err
-			}
-			for i := range flows {
-				// Per each flow check if there is an explicit Rule for Escape
-				if rule, ok := indexedRules[flows[i].ID.String()]; ok {
-					// If yes, so check if there is also a Flow Statistics
-					if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
-						// Check if the Escape rule matches (based on min number of feedback and score)
-						if rule.MinFeedback <= stat.TotFeedback && rule.LowerScore >= stat.AvgScore {
-							// If yes, move the Rollout Strategy in ESCAPED status
-							rs.RolloutState = mm_pubsub.RolloutStateEscaped
-							// Representation of Escape rules (FlowID --> Escape Rule)
-							indexedRollback := map[string]mm_pubsub.RsEscapeRollback{}
-							for _, rb := range rule.Rollback {
-								indexedRollback[rb.FlowID.String()] = rb
-							}
-							// Adapt all Flows to the Rollback PCTs
-							for i := range flows {
-								// For each active Flow check if there is a Rollback rule that
-								// determine the final Pct, otherwise set to 0
-								if rb, ok := indexedRollback[flows[i].ID.String()]; ok {
-									flows[i].CurrentServePct = &rb.FinalServePct
-								} else {
-									flows[i].CurrentServePct = mm_utils.Float64Ptr(0)
-								}
-							}
-							break
-						}
-					}
-				}
-			}
-			// Send RS-ENGINE-UPDATE event
-			e := prepareEvent(rs, flows)
-			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
-				return err
-			} else {
-				eventsToPublish = append(eventsToPublish, event)
-			}
-			return nil
-		})
-		if errTransaction != nil {
-			return errTransaction
-		} else {
-			s.pubSubAgent.PublishBulk(eventsToPublish)
+		windows = append(windows, toEngineFeedbackWindows(windowMins, summaries)...)
+	}
+	// Aggregate the feedback scores as configured, if needed
+	var engineSummaries []mm_rsengine.FeedbackSummary
+	if rs.Configuration.ScoreAggregation != nil {
+		if engineSummaries, err = mm_statistics.FeedbackSummaries(tx, rs.UseCaseID, rs.Configuration, now); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	engineFlows := toEngineFlows(flows)
+	newState, escapeTrigger, evaluated := step(rs, engineFlows, toEngineStatistics(statistics), engineSummaries, windows)
+	if !evaluated {
+		return nil, nil
+	}
+	fromState := rs.RolloutState
+	rs.RolloutState = newState
+	// Send RS-ENGINE-UPDATE event
+	e := prepareEvent(rs, fromState, engineFlows, escapeTrigger)
+	event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e)
+	if err != nil {
+		return nil, err
+	}
+	return []mm_pubsub.EventToPublish{event}, nil
 }
 
 /*
 In case a Rollout Strategy is forced to escape, run the Rollout strategy evaluation.
 */
-func (s rsEngineService) onRolloutStrategyChangeState(event mm_pubsub.RolloutStrategyEventEntity) error {
+func (s rsEngineService) onRolloutStrategyChangeState(ctx context.Context, event mm_pubsub.RolloutStrategyEventEntity) error {
 	rs := rolloutStrategyEntity{
 		ID:            event.ID,
 		UseCaseID:     event.UseCaseID,
@@ -233,99 +154,45 @@ func (s rsEngineService) onRolloutStrategyChangeState(event mm_pubsub.RolloutStr
 		Configuration: event.Configuration,
 		UpdatedAt:     event.UpdatedAt,
 	}
-	// If the RS is FORCED_ESCAPED
-	if rs.RolloutState == mm_pubsub.RolloutStateForcedEscaped {
-		// If the Escape configuration is not defined, skip it
-		if rs.Configuration.Escape == nil {
-			return nil
+	// Only FORCED_ESCAPED and FORCED_COMPLETED states are handled by the engine
+	if rs.RolloutState != mm_pubsub.RolloutStateForcedEscaped && rs.RolloutState != mm_pubsub.RolloutStateForcedCompleted {
+		return nil
+	}
+	// Start transaction
+	eventsToPublish := []mm_pubsub.EventToPublish{}
+	errTransaction := s.storage.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Skip the event if already processed, e.g. when redelivered after a crash
+		if isNew, err := mm_pubsub.MarkEventProcessed(tx); err != nil || !isNew {
+			return err
 		}
-		// Start transaction
-		eventsToPublish := []mm_pubsub.EventToPublish{}
-		errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
-			// Representation of Escape rules (FlowID --> Escape Rule)
-			indexedRules := map[string]mm_pubsub.RsEscapeRule{}
-			for _, rule := range rs.Configuration.Escape.Rules {
-				indexedRules[rule.FlowID.String()] = rule
-			}
-			// Retrieve all active Flows for the Use Case
-			flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
-			if err != nil {
-				return err
-			}
-			for i := range flows {
-				// Per each flow check if there is an explicit Rule for Escape
-				if rule, ok := indexedRules[flows[i].ID.String()]; ok {
-					// Representation of Escape rules (FlowID --> Escape Rule)
-					indexedRollback := map[string]mm_pubsub.RsEscapeRollback{}
-					for _, rb := range rule.Rollback {
-						indexedRollback[rb.FlowID.String()] = rb
-					}
-					// Adapt all Flows to the Rollback PCTs
-					for k := range flows {
-						// For each active Flow check if there is a Rollback rule that
-						// determine the final Pct, otherwise set to 0
-						if rb, ok := indexedRollback[flows[k].ID.String()]; ok {
-							flows[k].CurrentServePct = &rb.FinalServePct
-						} else {
-							flows[k].CurrentServePct = mm_utils.Float64Ptr(0)
-						}
-					}
-					break
-				}
-			}
-			// Send RS-ENGINE-UPDATE event
-			e := prepareEvent(rs, flows)
-			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
-				return err
-			} else {
-				eventsToPublish = append(eventsToPublish, event)
-			}
-			return nil
-		})
-		if errTransaction != nil {
-			return errTransaction
+		// Retrieve all active Flows for the Use Case
+		flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
+		if err != nil {
+			return err
+		}
+		engineFlows := toEngineFlows(flows)
+		var evaluated bool
+		if rs.RolloutState == mm_pubsub.RolloutStateForcedEscaped {
+			evaluated = mm_rsengine.ApplyForcedEscape(rs.Configuration, engineFlows)
 		} else {
-			s.pubSubAgent.PublishBulk(eventsToPublish)
+			evaluated = mm_rsengine.ApplyForcedCompleted(rs.Configuration, engineFlows)
 		}
-	}
-	// If the RS is FORCED_COMPLETED
-	if rs.RolloutState == mm_pubsub.RolloutStateForcedCompleted {
-		// If the Escape configuration is not defined, skip it
-		if rs.Configuration.StateConfigurations.CompletedFlowID == nil {
+		if !evaluated {
 			return nil
 		}
-		// Start transaction
-		eventsToPublish := []mm_pubsub.EventToPublish{}
-		errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
-			forcedFlowID := *rs.Configuration.StateConfigurations.CompletedFlowID
-
-			// Retrieve all active Flows for the Use Case
-			flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
-			if err != nil {
-				return err
-			}
-			// Per each flow, if equal to the forced Flow, put to 100%, otherwise to 0%
-			for i := range flows {
-				if flows[i].ID == forcedFlowID {
-					flows[i].CurrentServePct = mm_utils.Float64Ptr(100)
-				} else {
-					flows[i].CurrentServePct = mm_utils.Float64Ptr(0)
-				}
-			}
-			// Send RS-ENGINE-UPDATE event
-			e := prepareEvent(rs, flows)
-			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
-				return err
-			} else {
-				eventsToPublish = append(eventsToPublish, event)
-			}
-			return nil
-		})
-		if errTransaction != nil {
-			return errTransaction
+		// Send RS-ENGINE-UPDATE event
+		e := prepareEvent(rs, rs.RolloutState, engineFlows, nil)
+		if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
+			return err
 		} else {
-			s.pubSubAgent.PublishBulk(eventsToPublish)
+			eventsToPublish = append(eventsToPublish, event)
 		}
+		return nil
+	})
+	if errTransaction != nil {
+		return errTransaction
+	} else {
+		s.pubSubAgent.PublishBulk(eventsToPublish)
 	}
 	return nil
 }
@@ -338,286 +205,105 @@ func (s rsEngineService) onTimeTick() error {
 	if err != nil {
 		return err
 	}
-	// For each Rollout Strategy, run their warmup or adaptive phase
+	// Rollout Strategies waiting for their scheduled start
+	scheduledRolloutStrategies, err := s.repository.getRolloutStrategiesWithScheduledStart(s.storage)
+	if err != nil {
+		return err
+	}
+	rolloutStrategies = append(rolloutStrategies, scheduledRolloutStrategies...)
+	// For each Rollout Strategy, run their scheduled start, warmup or adaptive phase
 	for _, rs := range rolloutStrategies {
 		go func() {
-			if err := s.tickOnRolloutStrategy(rs); err != nil {
+			startedAt := time.Now()
+			if err := s.tickOnRolloutStrategy(rs, startedAt); err != nil {
 				zap.L().Error("Something went wrong during RS Engine execution", zap.String("Use Case ID", rs.UseCaseID.String()), zap.Error(err), zap.String("service", "rs-engine-service"))
 			}
+			tickDuration.WithLabelValues(rs.UseCaseID.String(), string(rs.RolloutState)).Observe(time.Since(startedAt).Seconds())
 		}()
 	}
 	return nil
 }
 
-func (s rsEngineService) tickOnRolloutStrategy(rs rolloutStrategyEntity) error {
-	//
-	//	WARMUP Phase
-	//
-	if rs.RolloutState == mm_pubsub.RolloutStateWarmup {
-		// If the Warmup configuration is not base on Time rules, skip it
-		if rs.Configuration.Warmup.IntervalMins == nil {
-			return nil
-		}
-		// List of Flows without an explicit Warmup goal
-		activeFlowsWithoutGoalIndexes := []int{}
-		// Representation of Warmup goal (FlowID --> Pct Goal)
-		indexedGoals := map[string]float64{}
-		for _, goal := range rs.Configuration.Warmup.Goals {
-			indexedGoals[goal.FlowID.String()] = goal.FinalServePct
-		}
-		// Start transaction
-		eventsToPublish := []mm_pubsub.EventToPublish{}
-		errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
-			// Retrieve all active Flows for the Use Case
-			flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
-			if err != nil {
-				return err
-			}
-			// If there are no active Flows, mark the RS as completed
-			if len(flows) == 0 {
-				rs.RolloutState = mm_pubsub.RolloutStateCompleted
-				// Send RS-ENGINE-UPDATE event
-				e := prepareEvent(rs, flows)
-				if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
-					return err
-				} else {
-					eventsToPublish = append(eventsToPublish, event)
-				}
-				return nil
-			}
-			// From now, check how many minutes are missing to reach the target (start of WARMUP + Interval Mins)
-			missingMinutes := int64(math.Round(time.Until((rs.UpdatedAt.Add(time.Duration(*rs.Configuration.Warmup.IntervalMins * int64(time.Minute))))).Minutes()))
-			// Specific case, should never happen, force to 0, so move automatically to ADAPTIVE
-			if missingMinutes < 0 {
-				missingMinutes = 0
-			}
-			// Indicates if all Flows have achieved their goal
-			var flowGoalAchieved bool = true
-			var totalPctReservedForGoals float64 = 0
-			for i := range flows {
-				// Per each flow update PCT and check if there is an explicit Warmup goal achieved
-				if pctGoal, ok := indexedGoals[flows[i].ID.String()]; !ok {
-					// No explicit Warmup goal, add to the list
-					activeFlowsWithoutGoalIndexes = append(activeFlowsWithoutGoalIndexes, i)
-					continue
-				} else {
-					// Update the Flow PCT based on statistics
-					flows[i].CurrentServePct = mm_utils.Float64Ptr(calculateNewServePct(*flows[i].CurrentServePct, pctGoal, 0, missingMinutes))
-					totalPctReservedForGoals += pctGoal
-					// Check if the goal has been achieved
-					if *flows[i].CurrentServePct != pctGoal {
-						flowGoalAchieved = false
-					}
-				}
-			}
-			// Now that we updated all Flows with an explicit Warmup goal, proceed with others
-			remainingPctPerFlow := (100.0 - totalPctReservedForGoals) / float64(len(activeFlowsWithoutGoalIndexes))
-			for _, i := range activeFlowsWithoutGoalIndexes {
-				flows[i].CurrentServePct = mm_utils.Float64Ptr(calculateNewServePct(*flows[i].CurrentServePct, remainingPctPerFlow, 0, missingMinutes))
-				if *flows[i].CurrentServePct != remainingPctPerFlow {
-					flowGoalAchieved = false
-				}
-			}
-			// If all flows have achieved their goal, we can move to the next state
-			if flowGoalAchieved {
-				rs.RolloutState = mm_pubsub.RolloutStateAdaptive
-			}
-			// Send RS-ENGINE-UPDATE event
-			e := prepareEvent(rs, flows)
-			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
-				return err
-			} else {
-				eventsToPublish = append(eventsToPublish, event)
-			}
-			return nil
-		})
-		if errTransaction != nil {
-			return errTransaction
-		} else {
-			s.pubSubAgent.PublishBulk(eventsToPublish)
-		}
-		// Avoid Adaptive phase is executed immediately once this one is completed
+/*
+Return all the active Flows, to expose their current serve PCT as metrics.
+*/
+func (s rsEngineService) getActiveFlows() ([]flowEntity, error) {
+	return s.repository.getActiveFlows(s.storage)
+}
+
+func (s rsEngineService) tickOnRolloutStrategy(rs rolloutStrategyEntity, now time.Time) error {
+	phaseStartedAt := mm_rsengine.PhaseStartedAt(rs.Configuration, rs.UpdatedAt)
+	// If the scheduled start is not reached yet, skip it
+	if _, evaluated := mm_rsengine.ApplyScheduledStart(rs.RolloutState, rs.Configuration, now); rs.RolloutState == mm_pubsub.RolloutStateInit && !evaluated {
 		return nil
 	}
-
-	//
-	//	ADAPTIVE Phase
-	//
-	if rs.RolloutState == mm_pubsub.RolloutStateAdaptive {
-		elaspedMinutes := int64(math.Round(time.Since(rs.UpdatedAt).Minutes()))
-		// If elasped time is not a multiple of the interval, skip it
-		if elaspedMinutes%rs.Configuration.Adaptive.IntervalMins != 0 {
-			return nil
+	// If the Adaptive interval is not elapsed or the RS is out of its Active Windows, skip it
+	if rs.RolloutState == mm_pubsub.RolloutStateAdaptive && !mm_rsengine.IsAdaptiveIntervalElapsed(rs.Configuration, phaseStartedAt, now) {
+		return nil
+	}
+	if rs.RolloutState == mm_pubsub.RolloutStateAdaptive && !mm_rsengine.IsWithinActiveWindows(rs.Configuration.Adaptive, now) {
+		return nil
+	}
+	// Start transaction
+	eventsToPublish := []mm_pubsub.EventToPublish{}
+	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+		// Retrieve all active Flows for the Use Case
+		flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
+		if err != nil {
+			return err
 		}
-		// Start transaction
-		eventsToPublish := []mm_pubsub.EventToPublish{}
-		errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
-			// Representation of Flow Statistics (FlowID --> Stats)
-			indexedStatistics := map[string]flowStatisticsEntity{}
+		engineFlows := toEngineFlows(flows)
+		var newState mm_pubsub.RolloutState
+		var evaluated bool
+		switch rs.RolloutState {
+		//
+		//	Scheduled start
+		//
+		case mm_pubsub.RolloutStateInit:
+			newState, evaluated = mm_rsengine.ApplyScheduledStart(rs.RolloutState, rs.Configuration, now)
+		//
+		//	WARMUP Phase
+		//
+		case mm_pubsub.RolloutStateWarmup:
+			newState, evaluated = mm_rsengine.ApplyWarmupOnTime(rs.RolloutState, rs.Configuration, engineFlows, phaseStartedAt, now)
+		//
+		//	ADAPTIVE Phase
+		//
+		case mm_pubsub.RolloutStateAdaptive:
 			statistics, err := s.repository.getFlowStatisticsByUseCaseID(tx, rs.UseCaseID)
 			if err != nil {
 				return err
 			}
-			for _, stat := range statistics {
-				indexedStatistics[stat.FlowID.String()] = stat
-			}
-			// Retrieve all active Flows for the Use Case
-			flows, err := s.repository.getActiveFlowsByUseCaseID(tx, rs.UseCaseID)
-			if err != nil {
-				return err
-			}
-			// If there are no active Flows, mark the RS as completed
-			if len(flows) == 0 {
-				rs.RolloutState = mm_pubsub.RolloutStateCompleted
-				// Send RS-ENGINE-UPDATE event
-				e := prepareEvent(rs, flows)
-				if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
+			// Feedback summaries since the start of the rollout are needed to rank the Flows on the aggregated scores,
+			// to check the significance of the winner and to sample the score distributions
+			var engineSummaries []mm_rsengine.FeedbackSummary
+			needsSummaries := rs.Configuration.ScoreAggregation != nil || rs.Configuration.Adaptive.MinConfidence != nil || rs.Configuration.Adaptive.Algorithm == mm_pubsub.RsAdaptiveAlgorithmThompsonSampling
+			if needsSummaries {
+				if engineSummaries, err = mm_statistics.FeedbackSummaries(tx, rs.UseCaseID, rs.Configuration, now); err != nil {
 					return err
-				} else {
-					eventsToPublish = append(eventsToPublish, event)
 				}
-				return nil
-			}
-			// Check for each flow if it has at least the number of needed feeedback
-			// to start the adaptive phase
-			isAdaptiveReady := true
-			for i := range flows {
-				if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
-					if stat.TotFeedback < rs.Configuration.Adaptive.MinFeedback {
-						isAdaptiveReady = false
-					}
-				}
-			}
-			// Check if we can proceed with Adaptive phase
-			if !isAdaptiveReady {
-				return nil
-			}
-
-			// Find highest score and best Flow Indexes
-			bestScore := 0.0
-			bestFlowIndexes := []int{}
-			for i := range flows {
-				if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
-					if stat.AvgScore > bestScore {
-						bestScore = stat.AvgScore
-						bestFlowIndexes = []int{i}
-					} else if stat.AvgScore == bestScore {
-						bestFlowIndexes = append(bestFlowIndexes, i)
-					}
-				}
-			}
-			// Check how much traffic is provided by worst Flows and find their Indexes
-			servedByWorst := 0.0
-			sumAvgScoreByWorst := 0.0
-			worstFlowIndexes := []int{}
-			for i := range flows {
-				if !slices.Contains(bestFlowIndexes, i) {
-					worstFlowIndexes = append(worstFlowIndexes, i)
-					servedByWorst += *flows[i].CurrentServePct
-					if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
-						sumAvgScoreByWorst += stat.AvgScore
-					}
-				}
-			}
-			sumAvgScoreByWorst = mm_utils.RoundTo2Decimals(sumAvgScoreByWorst)
-			// Calculate the maximum increment we can assign to best Flows by decrementing worst Flows
-			// keeping as limit the provided configuration
-			totalPossibleIncrement := rs.Configuration.Adaptive.MaxStepPct
-			if servedByWorst < totalPossibleIncrement {
-				totalPossibleIncrement = servedByWorst
-			}
-			// Calculate the increment per Flow (in case there are multiple best flows, split the increment between them)
-			incrementPerBestFlow := totalPossibleIncrement / float64(len(bestFlowIndexes))
-			// Increase traffic for best flows, without passing 100%. Track if one Flow reached 100%
-			flowReachedMaxPct := false
-			for _, bestFlowIndex := range bestFlowIndexes {
-				newPct := *flows[bestFlowIndex].CurrentServePct + incrementPerBestFlow
-				flows[bestFlowIndex].CurrentServePct = mm_utils.RoundTo2DecimalsPtr(&newPct)
-				if *flows[bestFlowIndex].CurrentServePct >= 100 {
-					flowReachedMaxPct = true
-					*flows[bestFlowIndex].CurrentServePct = 100
-				}
-			}
-			// If one Flow reached 100%, we need to put all others to 0%
-			if flowReachedMaxPct {
-				for i := range flows {
-					if *flows[i].CurrentServePct != 100.0 {
-						flows[i].CurrentServePct = mm_utils.Float64Ptr(0)
-					}
-				}
-			} else {
-				// Otherwise, for each worst Flow, calculate how much we need to decrement it based on the distance between its Score and Worst Score
-				totDecremented := 0.0
-				for _, i := range worstFlowIndexes {
-					if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
-						denominator := (sumAvgScoreByWorst * float64(len(worstFlowIndexes)-1))
-						if denominator == 0 {
-							denominator = 1
-						}
-						toDecrement := ((sumAvgScoreByWorst - stat.AvgScore) / denominator * totalPossibleIncrement)
-						newPct := *flows[i].CurrentServePct - toDecrement
-
-						if newPct < 0.0 {
-							totDecremented += *flows[i].CurrentServePct
-							flows[i].CurrentServePct = mm_utils.Float64Ptr(0)
-						} else {
-							totDecremented += toDecrement
-							flows[i].CurrentServePct = mm_utils.RoundTo2DecimalsPtr(&newPct)
-						}
-					}
-				}
-				if totalPossibleIncrement-totDecremented > 0 {
-					missingDecrement := totalPossibleIncrement - totDecremented
-					// If there is additional PCT to decrement, proceed in order, by sorting all Flows based on their Scores
-					slices.SortFunc(flows, func(a flowEntity, b flowEntity) int {
-						if statA, okA := indexedStatistics[a.ID.String()]; okA {
-							if statB, okB := indexedStatistics[b.ID.String()]; okB {
-								return int((statA.AvgScore - statB.AvgScore) * 100)
-							}
-						}
-						return 0
-					})
-					// Now, considering only Worst Flows, start reducing the traffic frome the worst Flow to better one
-					for i := range flows {
-						if missingDecrement == 0 {
-							break
-						}
-						if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
-							// Consider only worst Flows
-							if stat.AvgScore != bestScore {
-								if *flows[i].CurrentServePct >= missingDecrement {
-									newPct := *flows[i].CurrentServePct - missingDecrement
-									flows[i].CurrentServePct = mm_utils.RoundTo2DecimalsPtr(&newPct)
-									missingDecrement = 0
-								} else {
-									missingDecrement -= *flows[i].CurrentServePct
-									flows[i].CurrentServePct = mm_utils.Float64Ptr(0)
-								}
-							}
-						}
-					}
-				}
-			}
-
-			// If the Adaptive phase achieved its goal, move to COMPLETED
-			if flowReachedMaxPct {
-				rs.RolloutState = mm_pubsub.RolloutStateCompleted
-			}
-			// Send RS-ENGINE-UPDATE event
-			e := prepareEvent(rs, flows)
-			if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
-				return err
-			} else {
-				eventsToPublish = append(eventsToPublish, event)
 			}
+			random := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+			newState, evaluated = mm_rsengine.ApplyAdaptive(rs.RolloutState, rs.Configuration, engineFlows, toEngineStatistics(statistics), engineSummaries, random)
+		}
+		if !evaluated {
 			return nil
-		})
-		if errTransaction != nil {
-			return errTransaction
+		}
+		fromState := rs.RolloutState
+		rs.RolloutState = newState
+		// Send RS-ENGINE-UPDATE event
+		e := prepareEvent(rs, fromState, engineFlows, nil)
+		if event, err := s.pubSubAgent.Persist(tx, mm_pubsub.TopicRsEngineV1, e); err != nil {
+			return err
 		} else {
-			s.pubSubAgent.PublishBulk(eventsToPublish)
+			eventsToPublish = append(eventsToPublish, event)
 		}
 		return nil
+	})
+	if errTransaction != nil {
+		return errTransaction
+	} else {
+		s.pubSubAgent.PublishBulk(eventsToPublish)
 	}
 	return nil
 }
@@ -0,0 +1,25 @@
+package useCaseReport
+
+/*
+Confidence level of the intervals of the mean scores
+*/
+const scoreConfidenceLevel float64 = 0.95
+
+/*
+Feedback scores are reported in a histogram with a bin per star, rounding the scores
+*/
+const (
+	minHistogramScore int64 = 1
+	maxHistogramScore int64 = 5
+)
+
+/*
+State left by a Rollout Strategy when a rollout starts, as recorded in its history
+*/
+const rolloutStartFromState string = "INIT"
+
+/*
+Seed of the draws estimating the probability of each Flow being the best one, so the same feedback always
+produces the same report
+*/
+const bestProbabilitySeed uint64 = 42
@@ -0,0 +1,32 @@
+package useCaseReport
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type getUseCaseReportInputDto struct {
+	UseCaseID string `uri:"useCaseId"`
+}
+
+func (r getUseCaseReportInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.UseCaseID, validation.Required, is.UUID),
+	)
+}
+
+type getUseCaseFunnelInputDto struct {
+	UseCaseID string  `uri:"useCaseId"`
+	From      *string `form:"from"`
+	To        *string `form:"to"`
+}
+
+func (r getUseCaseFunnelInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.UseCaseID, validation.Required, is.UUID),
+		validation.Field(&r.From, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+		validation.Field(&r.To, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+	)
+}
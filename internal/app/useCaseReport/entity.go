@@ -0,0 +1,127 @@
+package useCaseReport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type useCaseEntity struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type flowEntity struct {
+	ID              uuid.UUID `json:"id"`
+	Title           string    `json:"title"`
+	Active          bool      `json:"active"`
+	CurrentServePct float64   `json:"currentServePct"`
+}
+
+type flowStatisticsEntity struct {
+	FlowID             uuid.UUID `json:"flowId"`
+	TotSessionRequests int64     `json:"totSessionRequests"`
+}
+
+type feedbackSummaryEntity struct {
+	FlowID      uuid.UUID `json:"flowId"`
+	TotFeedback int64     `json:"totFeedback"`
+	AvgScore    float64   `json:"avgScore"`
+	VarScore    float64   `json:"varScore"`
+}
+
+type feedbackHistogramBinEntity struct {
+	FlowID      uuid.UUID `json:"flowId"`
+	Score       int64     `json:"score"`
+	TotFeedback int64     `json:"totFeedback"`
+}
+
+type useCaseStepEntity struct {
+	ID       uuid.UUID `json:"id"`
+	Code     string    `json:"code"`
+	Position int64     `json:"position"`
+}
+
+/*
+Sessions of a Flow that reached a Use Case Step, with how many of them stopped there and the median time
+since the step reached before in the same session
+*/
+type funnelStepStatisticsEntity struct {
+	FlowID                     uuid.UUID `json:"flowId"`
+	UseCaseStepID              uuid.UUID `json:"useCaseStepId"`
+	TotSessions                int64     `json:"totSessions"`
+	TotExitedSessions          int64     `json:"totExitedSessions"`
+	MedianSecsFromPreviousStep *float64  `json:"medianSecsFromPreviousStep"`
+}
+
+type flowStepStatisticsEntity struct {
+	FlowID          uuid.UUID `json:"flowId"`
+	FlowStepID      uuid.UUID `json:"flowStepId"`
+	UseCaseStepID   uuid.UUID `json:"useCaseStepId"`
+	UseCaseStepCode string    `json:"useCaseStepCode"`
+	Position        int64     `json:"position"`
+	TotRequests     int64     `json:"totRequests"`
+}
+
+/*
+Side by side comparison of the Flows of a Use Case, on the statistics of the current rollout
+*/
+type useCaseReportEntity struct {
+	UseCaseID        uuid.UUID          `json:"useCaseId"`
+	RolloutStartedAt *time.Time         `json:"rolloutStartedAt"`
+	ConfidenceLevel  float64            `json:"confidenceLevel"`
+	Flows            []flowReportEntity `json:"flows"`
+}
+
+type flowReportEntity struct {
+	FlowID                  uuid.UUID                  `json:"flowId"`
+	Title                   string                     `json:"title"`
+	Active                  bool                       `json:"active"`
+	CurrentServePct         float64                    `json:"currentServePct"`
+	TotSessionRequests      int64                      `json:"totSessionRequests"`
+	TotFeedback             int64                      `json:"totFeedback"`
+	AvgScore                float64                    `json:"avgScore"`
+	ScoreConfidenceInterval *scoreIntervalEntity       `json:"scoreConfidenceInterval"`
+	ScoreHistogram          []scoreHistogramBinEntity  `json:"scoreHistogram"`
+	BestProbability         float64                    `json:"bestProbability"`
+	Steps                   []flowStepStatisticsEntity `json:"steps"`
+}
+
+type scoreIntervalEntity struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+type scoreHistogramBinEntity struct {
+	Score       int64 `json:"score"`
+	TotFeedback int64 `json:"totFeedback"`
+}
+
+/*
+Funnel of the sessions served by each Flow of a Use Case across the Use Case Steps, ordered by position
+*/
+type useCaseFunnelEntity struct {
+	UseCaseID uuid.UUID          `json:"useCaseId"`
+	From      *time.Time         `json:"from"`
+	To        *time.Time         `json:"to"`
+	Flows     []flowFunnelEntity `json:"flows"`
+}
+
+type flowFunnelEntity struct {
+	FlowID               uuid.UUID              `json:"flowId"`
+	Title                string                 `json:"title"`
+	TotSessions          int64                  `json:"totSessions"`
+	TotAbandonedSessions int64                  `json:"totAbandonedSessions"`
+	AbandonmentPct       float64                `json:"abandonmentPct"`
+	Steps                []flowFunnelStepEntity `json:"steps"`
+}
+
+type flowFunnelStepEntity struct {
+	UseCaseStepID              uuid.UUID `json:"useCaseStepId"`
+	UseCaseStepCode            string    `json:"useCaseStepCode"`
+	Position                   int64     `json:"position"`
+	TotSessions                int64     `json:"totSessions"`
+	ReachPct                   float64   `json:"reachPct"`
+	DropOffPct                 float64   `json:"dropOffPct"`
+	TotExitedSessions          int64     `json:"totExitedSessions"`
+	MedianSecsFromPreviousStep *float64  `json:"medianSecsFromPreviousStep"`
+}
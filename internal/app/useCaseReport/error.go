@@ -0,0 +1,5 @@
+package useCaseReport
+
+import "errors"
+
+var errUseCaseNotFound = errors.New("use-case-not-found")
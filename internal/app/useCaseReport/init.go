@@ -0,0 +1,24 @@
+package useCaseReport
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_env"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+Init the module by registering new APIs.
+*/
+func Init(envs *mm_env.Envs, dbStorage *gorm.DB, routerGroup *gin.RouterGroup) {
+	zap.L().Info("Initialize UseCaseReport package...")
+	var repository useCaseReportRepositoryInterface
+	var service useCaseReportServiceInterface
+	var router useCaseReportRouterInterface
+
+	repository = newUseCaseReportRepository()
+	service = newUseCaseReportService(dbStorage, repository)
+	router = newUseCaseReportRouter(service)
+	router.register(routerGroup)
+	zap.L().Info("UseCaseReport package initialized")
+}
@@ -0,0 +1,105 @@
+package useCaseReport
+
+import (
+	"github.com/google/uuid"
+)
+
+type useCaseModel struct {
+	ID uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+}
+
+func (m useCaseModel) TableName() string {
+	return "mm_use_case"
+}
+
+func (m useCaseModel) toEntity() useCaseEntity {
+	return useCaseEntity(m)
+}
+
+type flowModel struct {
+	ID              uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+	Title           string    `gorm:"column:title;type:varchar(255)"`
+	Active          bool      `gorm:"column:active;type:bool"`
+	CurrentServePct float64   `gorm:"column:current_pct;type:double precision"`
+}
+
+func (m flowModel) TableName() string {
+	return "mm_flow"
+}
+
+func (m flowModel) toEntity() flowEntity {
+	return flowEntity(m)
+}
+
+type flowStatisticsModel struct {
+	FlowID             uuid.UUID `gorm:"column:flow_id;type:varchar(36)"`
+	TotSessionRequests int64     `gorm:"column:tot_sess_req;type:bigint"`
+}
+
+func (m flowStatisticsModel) TableName() string {
+	return "mm_flow_statistics"
+}
+
+func (m flowStatisticsModel) toEntity() flowStatisticsEntity {
+	return flowStatisticsEntity(m)
+}
+
+type feedbackSummaryModel struct {
+	FlowID      uuid.UUID `gorm:"column:flow_id;type:varchar(36)"`
+	TotFeedback int64     `gorm:"column:tot_feedback;type:bigint"`
+	AvgScore    float64   `gorm:"column:avg_score;type:double precision"`
+	VarScore    float64   `gorm:"column:var_score;type:double precision"`
+}
+
+func (m feedbackSummaryModel) toEntity() feedbackSummaryEntity {
+	return feedbackSummaryEntity(m)
+}
+
+type feedbackHistogramBinModel struct {
+	FlowID      uuid.UUID `gorm:"column:flow_id;type:varchar(36)"`
+	Score       int64     `gorm:"column:score;type:bigint"`
+	TotFeedback int64     `gorm:"column:tot_feedback;type:bigint"`
+}
+
+func (m feedbackHistogramBinModel) toEntity() feedbackHistogramBinEntity {
+	return feedbackHistogramBinEntity(m)
+}
+
+type useCaseStepModel struct {
+	ID       uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+	Code     string    `gorm:"column:code;type:varchar(255)"`
+	Position int64     `gorm:"column:position;type:bigint"`
+}
+
+func (m useCaseStepModel) TableName() string {
+	return "mm_use_case_step"
+}
+
+func (m useCaseStepModel) toEntity() useCaseStepEntity {
+	return useCaseStepEntity(m)
+}
+
+type funnelStepStatisticsModel struct {
+	FlowID                     uuid.UUID `gorm:"column:flow_id;type:varchar(36)"`
+	UseCaseStepID              uuid.UUID `gorm:"column:use_case_step_id;type:varchar(36)"`
+	TotSessions                int64     `gorm:"column:tot_sessions;type:bigint"`
+	TotExitedSessions          int64     `gorm:"column:tot_exited_sessions;type:bigint"`
+	MedianSecsFromPreviousStep *float64  `gorm:"column:median_secs_from_previous_step;type:double precision"`
+}
+
+func (m funnelStepStatisticsModel) toEntity() funnelStepStatisticsEntity {
+	return funnelStepStatisticsEntity(m)
+}
+
+type flowStepStatisticsModel struct {
+	FlowID          uuid.UUID `gorm:"column:flow_id;type:varchar(36)"`
+	FlowStepID      uuid.UUID `gorm:"column:flow_step_id;type:varchar(36)"`
+	UseCaseStepID   uuid.UUID `gorm:"column:use_case_step_id;type:varchar(36)"`
+	UseCaseStepCode string    `gorm:"column:use_case_step_code;type:varchar(255)"`
+	Position        int64     `gorm:"column:position;type:bigint"`
+	TotRequests     int64     `gorm:"column:tot_req;type:bigint"`
+}
+
+func (m flowStepStatisticsModel) toEntity() flowStepStatisticsEntity {
+	return flowStepStatisticsEntity(m)
+}
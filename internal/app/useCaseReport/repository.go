@@ -0,0 +1,229 @@
+package useCaseReport
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type useCaseReportRepositoryInterface interface {
+	getUseCaseByID(tx *gorm.DB, useCaseID uuid.UUID) (useCaseEntity, error)
+	getRolloutStartedAt(tx *gorm.DB, useCaseID uuid.UUID) (*time.Time, error)
+	listFlowsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowEntity, error)
+	listFlowStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowStatisticsEntity, error)
+	listFeedbackSummariesByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, since *time.Time) ([]feedbackSummaryEntity, error)
+	listFeedbackHistogramsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, since *time.Time) ([]feedbackHistogramBinEntity, error)
+	listFlowStepStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowStepStatisticsEntity, error)
+	listUseCaseStepsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]useCaseStepEntity, error)
+	listFunnelStepStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, from *time.Time, to *time.Time) ([]funnelStepStatisticsEntity, error)
+}
+
+type useCaseReportRepository struct {
+}
+
+func newUseCaseReportRepository() useCaseReportRepository {
+	return useCaseReportRepository{}
+}
+
+func (r useCaseReportRepository) getUseCaseByID(tx *gorm.DB, useCaseID uuid.UUID) (useCaseEntity, error) {
+	var model *useCaseModel
+	query := tx.Where("id = ?", useCaseID)
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return useCaseEntity{}, result.Error
+	}
+	if result.RowsAffected == 0 || mm_utils.IsEmpty(model) {
+		return useCaseEntity{}, nil
+	}
+	return model.toEntity(), nil
+}
+
+/*
+Retrieve when the current rollout of the Use Case started, that is its last transition from the INIT state
+*/
+func (r useCaseReportRepository) getRolloutStartedAt(tx *gorm.DB, useCaseID uuid.UUID) (*time.Time, error) {
+	var startedAt *time.Time
+	result := tx.Table("mm_rollout_strategy_history").
+		Select("MAX(created_at)").
+		Where("use_case_id = ?", useCaseID).
+		Where("from_state = ?", rolloutStartFromState).
+		Scan(&startedAt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return startedAt, nil
+}
+
+func (r useCaseReportRepository) listFlowsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowEntity, error) {
+	var models []flowModel
+	query := tx.Model(flowModel{}).Where("use_case_id = ?", useCaseID).Order("created_at ASC")
+
+	result := query.Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]flowEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+func (r useCaseReportRepository) listFlowStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowStatisticsEntity, error) {
+	var models []flowStatisticsModel
+	query := tx.Model(flowStatisticsModel{}).Where("use_case_id = ?", useCaseID)
+
+	result := query.Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]flowStatisticsEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+/*
+Aggregate the feedback scores of each Flow of the Use Case received since the given time
+*/
+func (r useCaseReportRepository) listFeedbackSummariesByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, since *time.Time) ([]feedbackSummaryEntity, error) {
+	var models []feedbackSummaryModel
+	query := tx.Table("mm_feedback").
+		Select("flow_id, COUNT(*) AS tot_feedback, AVG(score) AS avg_score, COALESCE(VAR_SAMP(score), 0) AS var_score").
+		Where("use_case_id = ?", useCaseID)
+	if since != nil {
+		query = query.Where("created_at >= ?", since)
+	}
+	query = query.Group("flow_id")
+	result := query.Scan(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]feedbackSummaryEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+/*
+Count the feedback of each Flow of the Use Case received since the given time, per score rounded to the closest star
+*/
+func (r useCaseReportRepository) listFeedbackHistogramsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, since *time.Time) ([]feedbackHistogramBinEntity, error) {
+	var models []feedbackHistogramBinModel
+	query := tx.Table("mm_feedback").
+		Select("flow_id, ROUND(score) AS score, COUNT(*) AS tot_feedback").
+		Where("use_case_id = ?", useCaseID)
+	if since != nil {
+		query = query.Where("created_at >= ?", since)
+	}
+	query = query.Group("flow_id, ROUND(score)")
+	result := query.Scan(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]feedbackHistogramBinEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+/*
+Retrieve the requests served by each Flow Step of the Use Case, with the position of its Use Case Step
+*/
+func (r useCaseReportRepository) listFlowStepStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]flowStepStatisticsEntity, error) {
+	var models []flowStepStatisticsModel
+	query := tx.Table("mm_flow_step_statistics AS fss").
+		Select("fs.flow_id, fs.id AS flow_step_id, ucs.id AS use_case_step_id, ucs.code AS use_case_step_code, ucs.position, fss.tot_req").
+		Joins("JOIN mm_flow_step AS fs ON fs.id = fss.flow_step_id").
+		Joins("JOIN mm_use_case_step AS ucs ON ucs.id = fs.use_case_step_id").
+		Where("fs.use_case_id = ?", useCaseID).
+		Order("ucs.position ASC")
+	result := query.Scan(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]flowStepStatisticsEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+func (r useCaseReportRepository) listUseCaseStepsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID) ([]useCaseStepEntity, error) {
+	var models []useCaseStepModel
+	query := tx.Model(useCaseStepModel{}).Where("use_case_id = ?", useCaseID).Order("position ASC")
+
+	result := query.Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entities := make([]useCaseStepEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
+
+/*
+Follow each session (Correlation ID) of the Flows of the Use Case across the Use Case Steps, from the first
+request on each step. For each Flow and step, it counts the sessions that reached it, the ones for which it was
+the last step reached, and the median time since the step reached before in the same session.
+*/
+func (r useCaseReportRepository) listFunnelStepStatisticsByUseCaseID(tx *gorm.DB, useCaseID uuid.UUID, from *time.Time, to *time.Time) ([]funnelStepStatisticsEntity, error) {
+	var models []funnelStepStatisticsModel
+	conditions := []string{"pr.use_case_id = ?"}
+	args := []any{useCaseID}
+	if from != nil {
+		conditions = append(conditions, "pr.created_at >= ?")
+		args = append(args, *from)
+	}
+	if to != nil {
+		conditions = append(conditions, "pr.created_at < ?")
+		args = append(args, *to)
+	}
+	query := `
+		WITH first_requests AS (
+			SELECT
+				pr.flow_id,
+				pr.correlation_id,
+				pr.use_case_step_id,
+				ucs.position,
+				MIN(pr.created_at) AS first_request_at
+			FROM mm_picker_request pr
+			JOIN mm_use_case_step ucs ON ucs.id = pr.use_case_step_id
+			WHERE ` + strings.Join(conditions, " AND ") + `
+			GROUP BY pr.flow_id, pr.correlation_id, pr.use_case_step_id, ucs.position
+		),
+		sessions AS (
+			SELECT
+				flow_id,
+				use_case_step_id,
+				first_request_at,
+				LAG(first_request_at) OVER (PARTITION BY flow_id, correlation_id ORDER BY position ASC) AS previous_request_at,
+				LEAD(position) OVER (PARTITION BY flow_id, correlation_id ORDER BY position ASC) AS next_position
+			FROM first_requests
+		)
+		SELECT
+			flow_id,
+			use_case_step_id,
+			COUNT(*) AS tot_sessions,
+			COUNT(*) FILTER (WHERE next_position IS NULL) AS tot_exited_sessions,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM first_request_at - previous_request_at)) AS median_secs_from_previous_step
+		FROM sessions
+		GROUP BY flow_id, use_case_step_id
+	`
+	if err := tx.Raw(query, args...).Scan(&models).Error; err != nil {
+		return nil, err
+	}
+	entities := make([]funnelStepStatisticsEntity, len(models))
+	for i, model := range models {
+		entities[i] = model.toEntity()
+	}
+	return entities, nil
+}
@@ -0,0 +1,89 @@
+package useCaseReport
+
+import (
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_auth"
+	"github.com/ai-model-match/backend/internal/pkg/mm_router"
+	"github.com/ai-model-match/backend/internal/pkg/mm_timeout"
+	"go.uber.org/zap"
+
+	"github.com/gin-gonic/gin"
+)
+
+type useCaseReportRouterInterface interface {
+	register(engine *gin.RouterGroup)
+}
+
+type useCaseReportRouter struct {
+	service useCaseReportServiceInterface
+}
+
+func newUseCaseReportRouter(service useCaseReportServiceInterface) useCaseReportRouter {
+	return useCaseReportRouter{
+		service: service,
+	}
+}
+
+// Implementation
+func (r useCaseReportRouter) register(router *gin.RouterGroup) {
+	router.GET(
+		"/use-cases/:useCaseId/report",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(5)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request getUseCaseReportInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.getUseCaseReport(ctx, request)
+			if err == errUseCaseNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "use-case-report-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
+
+	router.GET(
+		"/use-cases/:useCaseId/funnel",
+		mm_auth.AuthMiddleware([]string{mm_auth.READ}),
+		mm_timeout.TimeoutMiddleware(time.Duration(5)*time.Second),
+		func(ctx *gin.Context) {
+			// Input validation
+			var request getUseCaseFunnelInputDto
+			if err := mm_router.BindParameters(ctx, &request); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			if err := request.validate(); err != nil {
+				mm_router.ReturnValidationError(ctx, err)
+				return
+			}
+			// Business Logic
+			item, err := r.service.getUseCaseFunnel(ctx, request)
+			if err == errUseCaseNotFound {
+				mm_router.ReturnNotFoundError(ctx, err)
+				return
+			}
+			// Errors and output handler
+			if err != nil {
+				zap.L().Error("Something went wrong", zap.String("service", "use-case-report-router"), zap.Error(err))
+				mm_router.ReturnGenericError(ctx)
+				return
+			}
+			mm_router.ReturnOk(ctx, &gin.H{"item": item})
+		})
+}
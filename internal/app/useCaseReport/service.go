@@ -0,0 +1,228 @@
+package useCaseReport
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_err"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/ai-model-match/backend/internal/pkg/mm_stats"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type useCaseReportServiceInterface interface {
+	getUseCaseReport(ctx *gin.Context, input getUseCaseReportInputDto) (useCaseReportEntity, error)
+	getUseCaseFunnel(ctx *gin.Context, input getUseCaseFunnelInputDto) (useCaseFunnelEntity, error)
+}
+
+type useCaseReportService struct {
+	storage    *gorm.DB
+	repository useCaseReportRepositoryInterface
+}
+
+func newUseCaseReportService(storage *gorm.DB, repository useCaseReportRepositoryInterface) useCaseReportService {
+	return useCaseReportService{
+		storage:    storage,
+		repository: repository,
+	}
+}
+
+/*
+Compare all Flows of the Use Case side by side, on the feedback received since the start of the current rollout
+*/
+func (s useCaseReportService) getUseCaseReport(ctx *gin.Context, input getUseCaseReportInputDto) (useCaseReportEntity, error) {
+	useCaseID := uuid.MustParse(input.UseCaseID)
+	useCase, err := s.repository.getUseCaseByID(s.storage, useCaseID)
+	if err != nil {
+		return useCaseReportEntity{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(useCase) {
+		return useCaseReportEntity{}, errUseCaseNotFound
+	}
+	rolloutStartedAt, err := s.repository.getRolloutStartedAt(s.storage, useCaseID)
+	if err != nil {
+		return useCaseReportEntity{}, mm_err.ErrGeneric
+	}
+	flows, err := s.repository.listFlowsByUseCaseID(s.storage, useCaseID)
+	if err != nil {
+		return useCaseReportEntity{}, mm_err.ErrGeneric
+	}
+	statistics, err := s.repository.listFlowStatisticsByUseCaseID(s.storage, useCaseID)
+	if err != nil {
+		return useCaseReportEntity{}, mm_err.ErrGeneric
+	}
+	summaries, err := s.repository.listFeedbackSummariesByUseCaseID(s.storage, useCaseID, rolloutStartedAt)
+	if err != nil {
+		return useCaseReportEntity{}, mm_err.ErrGeneric
+	}
+	histograms, err := s.repository.listFeedbackHistogramsByUseCaseID(s.storage, useCaseID, rolloutStartedAt)
+	if err != nil {
+		return useCaseReportEntity{}, mm_err.ErrGeneric
+	}
+	stepStatistics, err := s.repository.listFlowStepStatisticsByUseCaseID(s.storage, useCaseID)
+	if err != nil {
+		return useCaseReportEntity{}, mm_err.ErrGeneric
+	}
+	// Index all data by Flow
+	indexedStatistics := map[uuid.UUID]flowStatisticsEntity{}
+	for _, stat := range statistics {
+		indexedStatistics[stat.FlowID] = stat
+	}
+	indexedSummaries := map[uuid.UUID]feedbackSummaryEntity{}
+	for _, summary := range summaries {
+		indexedSummaries[summary.FlowID] = summary
+	}
+	indexedHistograms := map[uuid.UUID]map[int64]int64{}
+	for _, bin := range histograms {
+		if _, ok := indexedHistograms[bin.FlowID]; !ok {
+			indexedHistograms[bin.FlowID] = map[int64]int64{}
+		}
+		indexedHistograms[bin.FlowID][bin.Score] += bin.TotFeedback
+	}
+	indexedSteps := map[uuid.UUID][]flowStepStatisticsEntity{}
+	for _, step := range stepStatistics {
+		indexedSteps[step.FlowID] = append(indexedSteps[step.FlowID], step)
+	}
+	// Estimate the probability of being the best one among active Flows, as done by the engine
+	activeFlows := []mm_rsengine.Flow{}
+	activeSummaries := []mm_rsengine.FeedbackSummary{}
+	for _, flow := range flows {
+		if flow.Active {
+			activeFlows = append(activeFlows, mm_rsengine.Flow{ID: flow.ID, CurrentServePct: flow.CurrentServePct})
+			activeSummaries = append(activeSummaries, mm_rsengine.FeedbackSummary(indexedSummaries[flow.ID]))
+		}
+	}
+	indexedBestProbabilities := map[uuid.UUID]float64{}
+	if len(activeFlows) > 0 {
+		random := rand.New(rand.NewPCG(bestProbabilitySeed, bestProbabilitySeed))
+		for i, probability := range mm_rsengine.BestProbabilities(activeFlows, activeSummaries, random) {
+			indexedBestProbabilities[activeFlows[i].ID] = probability
+		}
+	}
+	// Build the report
+	report := useCaseReportEntity{
+		UseCaseID:        useCaseID,
+		RolloutStartedAt: rolloutStartedAt,
+		ConfidenceLevel:  scoreConfidenceLevel,
+		Flows:            make([]flowReportEntity, len(flows)),
+	}
+	for i, flow := range flows {
+		summary := indexedSummaries[flow.ID]
+		item := flowReportEntity{
+			FlowID:             flow.ID,
+			Title:              flow.Title,
+			Active:             flow.Active,
+			CurrentServePct:    flow.CurrentServePct,
+			TotSessionRequests: indexedStatistics[flow.ID].TotSessionRequests,
+			TotFeedback:        summary.TotFeedback,
+			AvgScore:           mm_utils.RoundTo2Decimals(summary.AvgScore),
+			ScoreHistogram:     []scoreHistogramBinEntity{},
+			BestProbability:    mm_utils.RoundTo2Decimals(indexedBestProbabilities[flow.ID]),
+			Steps:              []flowStepStatisticsEntity{},
+		}
+		sample := mm_stats.Sample{Count: summary.TotFeedback, Mean: summary.AvgScore, Variance: summary.VarScore}
+		if lower, upper, ok := mm_stats.MeanConfidenceInterval(sample, scoreConfidenceLevel); ok {
+			item.ScoreConfidenceInterval = &scoreIntervalEntity{
+				Lower: mm_utils.RoundTo2Decimals(lower),
+				Upper: mm_utils.RoundTo2Decimals(upper),
+			}
+		}
+		for score := minHistogramScore; score <= maxHistogramScore; score++ {
+			item.ScoreHistogram = append(item.ScoreHistogram, scoreHistogramBinEntity{Score: score, TotFeedback: indexedHistograms[flow.ID][score]})
+		}
+		if steps, ok := indexedSteps[flow.ID]; ok {
+			item.Steps = steps
+		}
+		report.Flows[i] = item
+	}
+	return report, nil
+}
+
+/*
+Compute for each Flow of the Use Case how many sessions reach each step, how many of them drop off and how long
+it takes to move between steps. Sessions that did not reach the last step are abandoned.
+*/
+func (s useCaseReportService) getUseCaseFunnel(ctx *gin.Context, input getUseCaseFunnelInputDto) (useCaseFunnelEntity, error) {
+	useCaseID := uuid.MustParse(input.UseCaseID)
+	from := mm_utils.GetOptionalTimeFromString(input.From)
+	to := mm_utils.GetOptionalTimeFromString(input.To)
+	useCase, err := s.repository.getUseCaseByID(s.storage, useCaseID)
+	if err != nil {
+		return useCaseFunnelEntity{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(useCase) {
+		return useCaseFunnelEntity{}, errUseCaseNotFound
+	}
+	flows, err := s.repository.listFlowsByUseCaseID(s.storage, useCaseID)
+	if err != nil {
+		return useCaseFunnelEntity{}, mm_err.ErrGeneric
+	}
+	steps, err := s.repository.listUseCaseStepsByUseCaseID(s.storage, useCaseID)
+	if err != nil {
+		return useCaseFunnelEntity{}, mm_err.ErrGeneric
+	}
+	stepStatistics, err := s.repository.listFunnelStepStatisticsByUseCaseID(s.storage, useCaseID, from, to)
+	if err != nil {
+		return useCaseFunnelEntity{}, mm_err.ErrGeneric
+	}
+	indexedStepStatistics := map[uuid.UUID]map[uuid.UUID]funnelStepStatisticsEntity{}
+	for _, stat := range stepStatistics {
+		if _, ok := indexedStepStatistics[stat.FlowID]; !ok {
+			indexedStepStatistics[stat.FlowID] = map[uuid.UUID]funnelStepStatisticsEntity{}
+		}
+		indexedStepStatistics[stat.FlowID][stat.UseCaseStepID] = stat
+	}
+	funnel := useCaseFunnelEntity{
+		UseCaseID: useCaseID,
+		From:      from,
+		To:        to,
+		Flows:     make([]flowFunnelEntity, len(flows)),
+	}
+	for i, flow := range flows {
+		item := flowFunnelEntity{
+			FlowID: flow.ID,
+			Title:  flow.Title,
+			Steps:  make([]flowFunnelStepEntity, len(steps)),
+		}
+		// Every session has exactly one last step reached
+		for _, stat := range indexedStepStatistics[flow.ID] {
+			item.TotSessions += stat.TotExitedSessions
+		}
+		var previousSessions int64 = 0
+		for j, step := range steps {
+			stat := indexedStepStatistics[flow.ID][step.ID]
+			item.Steps[j] = flowFunnelStepEntity{
+				UseCaseStepID:              step.ID,
+				UseCaseStepCode:            step.Code,
+				Position:                   step.Position,
+				TotSessions:                stat.TotSessions,
+				ReachPct:                   percentage(stat.TotSessions, item.TotSessions),
+				TotExitedSessions:          stat.TotExitedSessions,
+				MedianSecsFromPreviousStep: mm_utils.RoundTo2DecimalsPtr(stat.MedianSecsFromPreviousStep),
+			}
+			if j > 0 {
+				item.Steps[j].DropOffPct = percentage(previousSessions-stat.TotSessions, previousSessions)
+			}
+			previousSessions = stat.TotSessions
+		}
+		if len(steps) > 0 {
+			item.TotAbandonedSessions = item.TotSessions - indexedStepStatistics[flow.ID][steps[len(steps)-1].ID].TotExitedSessions
+			item.AbandonmentPct = percentage(item.TotAbandonedSessions, item.TotSessions)
+		}
+		funnel.Flows[i] = item
+	}
+	return funnel, nil
+}
+
+/*
+Percentage of the value over the total, rounded to 2 decimals (0 without a total)
+*/
+func percentage(value int64, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return mm_utils.RoundTo2Decimals(math.Max(float64(value), 0) / float64(total) * 100)
+}
@@ -38,10 +38,11 @@ func (r getUseCaseStepInputDto) validate() error {
 }
 
 type createUseCaseStepInputDto struct {
-	UseCaseID   string `json:"useCaseId"`
-	Title       string `json:"title"`
-	Code        string `json:"code"`
-	Description string `json:"description"`
+	UseCaseID   string                   `json:"useCaseId"`
+	Title       string                   `json:"title"`
+	Code        string                   `json:"code"`
+	Description string                   `json:"description"`
+	Variables   []useCaseStepVariableDto `json:"variables"`
 }
 
 func (r createUseCaseStepInputDto) validate() error {
@@ -50,15 +51,19 @@ func (r createUseCaseStepInputDto) validate() error {
 		validation.Field(&r.Title, validation.Required, validation.Length(1, 255)),
 		validation.Field(&r.Code, validation.Required, validation.Length(1, 255)),
 		validation.Field(&r.Description, validation.Required),
+		validation.Field(&r.Variables, validation.By(func(value interface{}) error {
+			return validateVariables(r.Variables)
+		})),
 	)
 }
 
 type updateUseCaseStepInputDto struct {
-	ID          string  `uri:"useCaseStepId"`
-	Title       *string `json:"title"`
-	Code        *string `json:"code"`
-	Description *string `json:"description"`
-	Position    *int64  `json:"position"`
+	ID          string                    `uri:"useCaseStepId"`
+	Title       *string                   `json:"title"`
+	Code        *string                   `json:"code"`
+	Description *string                   `json:"description"`
+	Position    *int64                    `json:"position"`
+	Variables   *[]useCaseStepVariableDto `json:"variables"`
 }
 
 func (r updateUseCaseStepInputDto) validate() error {
@@ -68,6 +73,9 @@ func (r updateUseCaseStepInputDto) validate() error {
 		validation.Field(&r.Code, validation.NilOrNotEmpty, validation.Length(1, 255)),
 		validation.Field(&r.Description, validation.NilOrNotEmpty),
 		validation.Field(&r.Position, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(&r.Variables, validation.When(r.Variables != nil, validation.By(func(value interface{}) error {
+			return validateVariables(*r.Variables)
+		}))),
 	)
 }
 
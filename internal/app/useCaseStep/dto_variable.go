@@ -0,0 +1,93 @@
+package useCaseStep
+
+import (
+	"errors"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_template"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type useCaseStepVariableDto struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Required  bool     `json:"required"`
+	Default   *string  `json:"default"`
+	MaxLength *int     `json:"maxLength"`
+	Enum      []string `json:"enum"`
+}
+
+func (r useCaseStepVariableDto) validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 255), validation.Match(mm_template.NameRegex).Error("must contain only letters, numbers, underscores and dashes")),
+		validation.Field(&r.Type, validation.Required, validation.In(mm_utils.TransformToStrings(mm_template.AvailableVariableType)...)),
+		validation.Field(&r.MaxLength, validation.NilOrNotEmpty, validation.Min(1)),
+	); err != nil {
+		return err
+	}
+	// Allowed values must respect the type and the max length of the variable
+	variable := r.toEntity()
+	variable.Enum = nil
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Enum, validation.Each(validation.By(func(value interface{}) error {
+			return variable.ValidateValue(value.(string))
+		}))),
+	); err != nil {
+		return err
+	}
+	// A required variable is always provided by the caller, so a default makes no sense
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Default, validation.When(r.Required, validation.Nil.Error("not allowed on required variables")).Else(validation.By(func(value interface{}) error {
+			if r.Default == nil {
+				return nil
+			}
+			return r.toEntity().ValidateValue(*r.Default)
+		}))),
+	)
+}
+
+func (r useCaseStepVariableDto) toEntity() mm_template.Variable {
+	enum := r.Enum
+	if enum == nil {
+		enum = []string{}
+	}
+	return mm_template.Variable{
+		Name:      r.Name,
+		Type:      mm_template.VariableType(r.Type),
+		Required:  r.Required,
+		Default:   r.Default,
+		MaxLength: r.MaxLength,
+		Enum:      enum,
+	}
+}
+
+/*
+Validate all the variables of a schema, checking their names are unique.
+*/
+func validateVariables(variables []useCaseStepVariableDto) error {
+	if err := validation.Validate(variables, validation.Each(validation.By(func(value interface{}) error {
+		v := value.(useCaseStepVariableDto)
+		return v.validate()
+	}))); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, variable := range variables {
+		if _, exists := seen[variable.Name]; exists {
+			return errors.New("variable names must be unique")
+		}
+		seen[variable.Name] = true
+	}
+	return nil
+}
+
+/*
+Convert the variables in the schema stored on the Use Case Step.
+*/
+func variablesToEntity(variables []useCaseStepVariableDto) []mm_template.Variable {
+	schema := make([]mm_template.Variable, len(variables))
+	for i, variable := range variables {
+		schema[i] = variable.toEntity()
+	}
+	return schema
+}
@@ -1,6 +1,7 @@
 package useCaseStep
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
@@ -16,14 +17,15 @@ func (m useCaseModel) TableName() string {
 }
 
 type useCaseStepModel struct {
-	ID          uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
-	UseCaseID   uuid.UUID `gorm:"column:use_case_id;type:varchar(36)"`
-	Title       string    `gorm:"column:title;type:varchar(255)"`
-	Code        string    `gorm:"column:code;type:varchar(255)"`
-	Description string    `gorm:"column:description;type:text"`
-	Position    *int64    `gorm:"column:position;type:bigint"`
-	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime:false"`
+	ID          uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	UseCaseID   uuid.UUID       `gorm:"column:use_case_id;type:varchar(36)"`
+	Title       string          `gorm:"column:title;type:varchar(255)"`
+	Code        string          `gorm:"column:code;type:varchar(255)"`
+	Description string          `gorm:"column:description;type:text"`
+	Position    *int64          `gorm:"column:position;type:bigint"`
+	Variables   json.RawMessage `gorm:"column:variables;type:json"`
+	CreatedAt   time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
+	UpdatedAt   time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime:false"`
 }
 
 func (m useCaseStepModel) TableName() string {
@@ -34,6 +36,16 @@ func (m useCaseStepModel) toEntity() useCaseStepEntity {
 	return useCaseStepEntity(m)
 }
 
+type flowStepModel struct {
+	ID            uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	UseCaseStepID uuid.UUID       `gorm:"column:use_case_step_id;type:varchar(36)"`
+	Placeholders  json.RawMessage `gorm:"column:placeholders;type:json"`
+}
+
+func (m flowStepModel) TableName() string {
+	return "mm_flow_step"
+}
+
 type useCaseStepOrderBy string
 
 const (
@@ -1,7 +1,9 @@
 package useCaseStep
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
@@ -18,6 +20,7 @@ type useCaseStepRepositoryInterface interface {
 	saveUseCaseStep(tx *gorm.DB, useCaseStep useCaseStepEntity, operation mm_db.SaveOperation) (useCaseStepEntity, error)
 	deleteUseCaseStep(tx *gorm.DB, useCaseStep useCaseStepEntity) (useCaseStepEntity, error)
 	recalculateUseCaseStepPosition(tx *gorm.DB, useCaseID uuid.UUID) ([]useCaseStepEntity, error)
+	getFlowStepsPlaceholders(tx *gorm.DB, useCaseStepID uuid.UUID) ([]string, error)
 }
 
 type useCaseStepRepository struct {
@@ -166,3 +169,25 @@ func (r useCaseStepRepository) recalculateUseCaseStepPosition(tx *gorm.DB, useCa
 	}
 	return entities, nil
 }
+
+func (r useCaseStepRepository) getFlowStepsPlaceholders(tx *gorm.DB, useCaseStepID uuid.UUID) ([]string, error) {
+	var models []*flowStepModel
+	err := tx.Where("use_case_step_id = ?", useCaseStepID).Find(&models).Error
+	if err != nil {
+		return []string{}, err
+	}
+	// Merge the placeholders used by the steps of all Flows, without duplicates
+	placeholders := []string{}
+	for _, model := range models {
+		var items []string
+		if err := json.Unmarshal(model.Placeholders, &items); err != nil {
+			return []string{}, err
+		}
+		for _, item := range items {
+			if !slices.Contains(placeholders, item) {
+				placeholders = append(placeholders, item)
+			}
+		}
+	}
+	return placeholders, nil
+}
@@ -1,6 +1,7 @@
 package useCaseStep
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_auth"
@@ -142,6 +143,10 @@ func (r useCaseStepRouter) register(router *gin.RouterGroup) {
 			}
 			// Business Logic
 			item, err := r.service.updateUseCaseStep(ctx, request)
+			if errors.Is(err, errUseCaseStepUndeclaredPlaceholders) {
+				mm_router.ReturnBadRequestError(ctx, err)
+				return
+			}
 			if err == errUseCaseNotFound {
 				mm_router.ReturnNotFoundError(ctx, err)
 				return
@@ -1,12 +1,16 @@
 package useCaseStep
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_db"
 	"github.com/ai-model-match/backend/internal/pkg/mm_err"
 	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_template"
 	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -68,6 +72,7 @@ func (s useCaseStepService) createUseCaseStep(ctx *gin.Context, input createUseC
 	now := time.Now()
 	useCaseID := uuid.MustParse(input.UseCaseID)
 	maxValue := int64(math.MaxInt64)
+	variables, _ := json.Marshal(variablesToEntity(input.Variables))
 	newUseCaseStep := useCaseStepEntity{
 		ID:          uuid.New(),
 		UseCaseID:   useCaseID,
@@ -75,6 +80,7 @@ func (s useCaseStepService) createUseCaseStep(ctx *gin.Context, input createUseC
 		Code:        input.Code,
 		Description: input.Description,
 		Position:    &maxValue,
+		Variables:   json.RawMessage(variables),
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -109,6 +115,7 @@ func (s useCaseStepService) createUseCaseStep(ctx *gin.Context, input createUseC
 					Code:        newUseCaseStep.Code,
 					Description: newUseCaseStep.Description,
 					Position:    newUseCaseStep.Position,
+					Variables:   newUseCaseStep.Variables,
 					CreatedAt:   newUseCaseStep.CreatedAt,
 					UpdatedAt:   newUseCaseStep.UpdatedAt,
 				},
@@ -136,6 +143,7 @@ func (s useCaseStepService) createUseCaseStep(ctx *gin.Context, input createUseC
 						Code:        updatedPosEntity.Code,
 						Description: updatedPosEntity.Description,
 						Position:    updatedPosEntity.Position,
+						Variables:   updatedPosEntity.Variables,
 						CreatedAt:   updatedPosEntity.CreatedAt,
 						UpdatedAt:   updatedPosEntity.UpdatedAt,
 					},
@@ -194,6 +202,21 @@ func (s useCaseStepService) updateUseCaseStep(ctx *gin.Context, input updateUseC
 		if input.Code != nil {
 			updatedUseCaseStep.Code = *input.Code
 		}
+		if input.Variables != nil {
+			// All the placeholders already used by the Flows must stay declared
+			placeholders, err := s.repository.getFlowStepsPlaceholders(tx, currentUseCaseStep.ID)
+			if err != nil {
+				return mm_err.ErrGeneric
+			}
+			schema := variablesToEntity(*input.Variables)
+			if len(schema) > 0 {
+				if undeclared := mm_template.UndeclaredPlaceholders(schema, placeholders); len(undeclared) > 0 {
+					return fmt.Errorf("%w: %s", errUseCaseStepUndeclaredPlaceholders, strings.Join(undeclared, ", "))
+				}
+			}
+			variables, _ := json.Marshal(schema)
+			updatedUseCaseStep.Variables = json.RawMessage(variables)
+		}
 		if input.Position != nil {
 			// If the step is moving in a lower position (e.g. from 10 to 3),
 			// we need to move it one step more, so that, the algorith to re-sort all steps correctly
@@ -224,6 +247,7 @@ func (s useCaseStepService) updateUseCaseStep(ctx *gin.Context, input updateUseC
 					Code:        updatedUseCaseStep.Code,
 					Description: updatedUseCaseStep.Description,
 					Position:    updatedUseCaseStep.Position,
+					Variables:   updatedUseCaseStep.Variables,
 					CreatedAt:   updatedUseCaseStep.CreatedAt,
 					UpdatedAt:   updatedUseCaseStep.UpdatedAt,
 				},
@@ -251,6 +275,7 @@ func (s useCaseStepService) updateUseCaseStep(ctx *gin.Context, input updateUseC
 						Code:        updatedPosEntity.Code,
 						Description: updatedPosEntity.Description,
 						Position:    updatedPosEntity.Position,
+						Variables:   updatedPosEntity.Variables,
 						CreatedAt:   updatedPosEntity.CreatedAt,
 						UpdatedAt:   updatedPosEntity.UpdatedAt,
 					},
@@ -302,6 +327,7 @@ func (s useCaseStepService) deleteUseCaseStep(ctx *gin.Context, input deleteUseC
 					Code:        currentUseCaseStep.Code,
 					Description: currentUseCaseStep.Description,
 					Position:    currentUseCaseStep.Position,
+					Variables:   currentUseCaseStep.Variables,
 					CreatedAt:   currentUseCaseStep.CreatedAt,
 					UpdatedAt:   currentUseCaseStep.UpdatedAt,
 				},
@@ -330,6 +356,7 @@ func (s useCaseStepService) deleteUseCaseStep(ctx *gin.Context, input deleteUseC
 							Code:        updatedPosEntity.Code,
 							Description: updatedPosEntity.Description,
 							Position:    updatedPosEntity.Position,
+							Variables:   updatedPosEntity.Variables,
 							CreatedAt:   updatedPosEntity.CreatedAt,
 							UpdatedAt:   updatedPosEntity.UpdatedAt,
 						},
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+)
+
+/*
+Event types a webhook can subscribe to
+*/
+var availableWebhookEventTypes = []interface{}{
+	mm_pubsub.RolloutStrategyCreatedEvent,
+	mm_pubsub.RolloutStrategyUpdatedEvent,
+	mm_pubsub.RsEngineUpdatedEvent,
+	mm_pubsub.FlowCreatedEvent,
+	mm_pubsub.FlowUpdatedEvent,
+	mm_pubsub.FlowDeletedEvent,
+}
+
+type webhookDeliveryStatus string
+
+const (
+	webhookDeliveryStatusPending webhookDeliveryStatus = "PENDING"
+	webhookDeliveryStatusSuccess webhookDeliveryStatus = "SUCCESS"
+	webhookDeliveryStatusRetry   webhookDeliveryStatus = "RETRY"
+	webhookDeliveryStatusFailed  webhookDeliveryStatus = "FAILED"
+)
+
+var availableWebhookDeliveryStatus = []interface{}{
+	webhookDeliveryStatusPending,
+	webhookDeliveryStatusSuccess,
+	webhookDeliveryStatusRetry,
+	webhookDeliveryStatusFailed,
+}
+
+/*
+Max number of attempts before marking a delivery as failed,
+with an exponential backoff starting from the base delay
+*/
+const webhookMaxDeliveryAttempts = 5
+const webhookRetryBaseDelay = 1 * time.Minute
+
+/*
+Timeout of a single HTTP call to the webhook URL
+*/
+const webhookRequestTimeout = 5 * time.Second
+
+/*
+Max number of deliveries sent in a single run of the scheduler
+*/
+const webhookSendBatchSize = 100
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"slices"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_log"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"go.uber.org/zap"
+)
+
+type webhookConsumerInterface interface {
+	subscribe()
+}
+
+type webhookConsumer struct {
+	pubSub  *mm_pubsub.PubSubAgent
+	service webhookServiceInterface
+}
+
+func newWebhookConsumer(pubSub *mm_pubsub.PubSubAgent, service webhookServiceInterface) webhookConsumer {
+	consumer := webhookConsumer{
+		pubSub:  pubSub,
+		service: service,
+	}
+	return consumer
+}
+
+func (r webhookConsumer) subscribe() {
+	for _, topic := range []mm_pubsub.PubSubTopic{
+		mm_pubsub.TopicRolloutStrategyV1,
+		mm_pubsub.TopicRsEngineV1,
+		mm_pubsub.TopicFlowV1,
+	} {
+		r.subscribeTopic(topic)
+	}
+}
+
+func (r webhookConsumer) subscribeTopic(topic mm_pubsub.PubSubTopic) {
+	go func() {
+		messageChannel := r.pubSub.Subscribe(topic, "webhook")
+		isChannelOpen := true
+		for isChannelOpen {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						mm_log.LogPanicError(r, "webhook-consumer", "Panic occurred in handling a new message")
+					}
+				}()
+				msg, channelOpen := <-messageChannel
+				if !channelOpen {
+					isChannelOpen = false
+					zap.L().Info(
+						"Channel closed. No more events to listen... quit!",
+						zap.String("service", "webhook-consumer"),
+					)
+					return
+				}
+				// ACK message
+				defer msg.Message.EventState.Done()
+				zap.L().Info(
+					"Received Event Message",
+					zap.String("service", "webhook-consumer"),
+					zap.String("event-id", msg.Message.EventID.String()),
+					zap.String("event-type", string(msg.Message.EventType)),
+				)
+				if !slices.Contains(availableWebhookEventTypes, any(msg.Message.EventType)) {
+					return
+				}
+				if err := r.service.dispatchEvent(msg.Message); err != nil {
+					zap.L().Error("Impossible to dispatch the event to webhooks", zap.String("service", "webhook-consumer"))
+					msg.Message.EventState.Fail(err)
+					return
+				}
+			}()
+		}
+	}()
+}
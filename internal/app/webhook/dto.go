@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_db"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type listWebhooksInputDto struct {
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize"`
+	OrderBy  string `form:"orderBy"`
+	OrderDir string `form:"orderDir"`
+}
+
+func (r listWebhooksInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Page, validation.Required, validation.Min(1)),
+		validation.Field(&r.PageSize, validation.Required, validation.Min(1), validation.Max(200)),
+		validation.Field(&r.OrderBy, validation.Required, validation.In(mm_utils.TransformToStrings(availableWebhookOrderBy)...)),
+		validation.Field(&r.OrderDir, validation.Required, validation.In(mm_utils.TransformToStrings(mm_db.AvailableOrderDir)...)),
+	)
+}
+
+type getWebhookInputDto struct {
+	ID string `uri:"webhookId"`
+}
+
+func (r getWebhookInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID),
+	)
+}
+
+type createWebhookInputDto struct {
+	Title         string   `json:"title"`
+	Url           string   `json:"url"`
+	Secret        string   `json:"secret"`
+	EventTypes    []string `json:"eventTypes"`
+	UseCaseID     *string  `json:"useCaseId"`
+	RolloutStates []string `json:"rolloutStates"`
+	Active        *bool    `json:"active"`
+}
+
+func (r createWebhookInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Title, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Url, validation.Required, is.URL),
+		validation.Field(&r.Secret, validation.Required, validation.Length(16, 255)),
+		validation.Field(&r.EventTypes, validation.Required, validation.Length(1, 0), validation.Each(validation.In(mm_utils.TransformToStrings(availableWebhookEventTypes)...))),
+		validation.Field(&r.UseCaseID, validation.NilOrNotEmpty, is.UUID),
+		validation.Field(&r.RolloutStates, validation.Each(validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableRolloutState)...))),
+		validation.Field(&r.Active, validation.Required, validation.In(true, false)),
+	)
+}
+
+type updateWebhookInputDto struct {
+	ID            string    `uri:"webhookId"`
+	Title         *string   `json:"title"`
+	Url           *string   `json:"url"`
+	Secret        *string   `json:"secret"`
+	EventTypes    *[]string `json:"eventTypes"`
+	RolloutStates *[]string `json:"rolloutStates"`
+	Active        *bool     `json:"active"`
+}
+
+func (r updateWebhookInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID),
+		validation.Field(&r.Title, validation.NilOrNotEmpty, validation.Length(1, 255)),
+		validation.Field(&r.Url, validation.NilOrNotEmpty, is.URL),
+		validation.Field(&r.Secret, validation.NilOrNotEmpty, validation.Length(16, 255)),
+		validation.Field(&r.EventTypes, validation.NilOrNotEmpty, validation.Each(validation.In(mm_utils.TransformToStrings(availableWebhookEventTypes)...))),
+		validation.Field(&r.RolloutStates, validation.Each(validation.In(mm_utils.TransformToStrings(mm_pubsub.AvailableRolloutState)...))),
+		validation.Field(&r.Active, validation.In(true, false)),
+	)
+}
+
+type deleteWebhookInputDto struct {
+	ID string `uri:"webhookId"`
+}
+
+func (r deleteWebhookInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID),
+	)
+}
+
+type listWebhookDeliveriesInputDto struct {
+	WebhookID string  `uri:"webhookId"`
+	Page      int     `form:"page"`
+	PageSize  int     `form:"pageSize"`
+	Status    *string `form:"status"`
+}
+
+func (r listWebhookDeliveriesInputDto) validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.WebhookID, validation.Required, is.UUID),
+		validation.Field(&r.Page, validation.Required, validation.Min(1)),
+		validation.Field(&r.PageSize, validation.Required, validation.Min(1), validation.Max(200)),
+		validation.Field(&r.Status, validation.NilOrNotEmpty, validation.In(mm_utils.TransformToStrings(availableWebhookDeliveryStatus)...)),
+	)
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/google/uuid"
+)
+
+type webhookEntity struct {
+	ID            uuid.UUID                   `json:"id"`
+	Title         string                      `json:"title"`
+	Url           string                      `json:"url"`
+	Secret        string                      `json:"-"`
+	EventTypes    []mm_pubsub.PubSubEventType `json:"eventTypes"`
+	UseCaseID     *uuid.UUID                  `json:"useCaseId"`
+	RolloutStates []mm_pubsub.RolloutState    `json:"rolloutStates"`
+	Active        *bool                       `json:"active"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+	UpdatedAt     time.Time                   `json:"updatedAt"`
+}
+
+type webhookDeliveryEntity struct {
+	ID             uuid.UUID                 `json:"id"`
+	WebhookID      uuid.UUID                 `json:"webhookId"`
+	EventID        uuid.UUID                 `json:"eventId"`
+	EventType      mm_pubsub.PubSubEventType `json:"eventType"`
+	Status         webhookDeliveryStatus     `json:"status"`
+	Attempts       int                       `json:"attempts"`
+	ResponseStatus *int                      `json:"responseStatus"`
+	LastError      *string                   `json:"lastError"`
+	NextAttemptAt  *time.Time                `json:"nextAttemptAt"`
+	Payload        json.RawMessage           `json:"payload"`
+	CreatedAt      time.Time                 `json:"createdAt"`
+	UpdatedAt      time.Time                 `json:"updatedAt"`
+}
+
+/*
+Body sent to the webhook URL for each delivery
+*/
+type webhookPayloadEntity struct {
+	WebhookID          uuid.UUID                 `json:"webhookId"`
+	DeliveryID         uuid.UUID                 `json:"deliveryId"`
+	EventID            uuid.UUID                 `json:"eventId"`
+	EventTime          time.Time                 `json:"eventTime"`
+	EventType          mm_pubsub.PubSubEventType `json:"eventType"`
+	EventEntity        interface{}               `json:"eventEntity"`
+	EventChangedFields []string                  `json:"eventChangedFields"`
+}
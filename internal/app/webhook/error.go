@@ -0,0 +1,6 @@
+package webhook
+
+import "errors"
+
+var errWebhookNotFound = errors.New("webhook-not-found")
+var errUseCaseNotFound = errors.New("use-case-not-found")
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_env"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_scheduler"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+Init the module by registering new APIs and PubSub consumers.
+*/
+func Init(envs *mm_env.Envs, dbStorage *gorm.DB, pubSubAgent *mm_pubsub.PubSubAgent, cron *mm_scheduler.Scheduler, routerGroup *gin.RouterGroup) {
+	zap.L().Info("Initialize Webhook package...")
+	var repository webhookRepositoryInterface
+	var service webhookServiceInterface
+	var consumer webhookConsumerInterface
+	var scheduler webhookSchedulerInterface
+	var router webhookRouterInterface
+
+	repository = newWebhookRepository()
+	service = newWebhookService(dbStorage, repository)
+	consumer = newWebhookConsumer(pubSubAgent, service)
+	consumer.subscribe()
+	scheduler = newWebhookScheduler(dbStorage, cron, service)
+	scheduler.init()
+	router = newWebhookRouter(service)
+	router.register(routerGroup)
+	zap.L().Info("Webhook package initialized")
+}
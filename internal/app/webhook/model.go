@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/google/uuid"
+)
+
+type useCaseModel struct {
+	ID uuid.UUID `gorm:"primaryKey;column:id;type:varchar(36)"`
+}
+
+func (m useCaseModel) TableName() string {
+	return "mm_use_case"
+}
+
+type webhookModel struct {
+	ID            uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	Title         string          `gorm:"column:title;type:varchar(255)"`
+	Url           string          `gorm:"column:url;type:text"`
+	Secret        string          `gorm:"column:secret;type:varchar(255)"`
+	EventTypes    json.RawMessage `gorm:"column:event_types;type:json"`
+	UseCaseID     *uuid.UUID      `gorm:"column:use_case_id;type:varchar(36)"`
+	RolloutStates json.RawMessage `gorm:"column:rollout_states;type:json"`
+	Active        *bool           `gorm:"column:active;type:boolean"`
+	CreatedAt     time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
+	UpdatedAt     time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime:false"`
+}
+
+func (m webhookModel) TableName() string {
+	return "mm_webhook"
+}
+
+func (m webhookModel) toEntity() webhookEntity {
+	// Remap the stored JSON lists in the object lists
+	eventTypes := []mm_pubsub.PubSubEventType{}
+	if err := json.Unmarshal(m.EventTypes, &eventTypes); err != nil {
+		return webhookEntity{}
+	}
+	rolloutStates := []mm_pubsub.RolloutState{}
+	if err := json.Unmarshal(m.RolloutStates, &rolloutStates); err != nil {
+		return webhookEntity{}
+	}
+	return webhookEntity{
+		ID:            m.ID,
+		Title:         m.Title,
+		Url:           m.Url,
+		Secret:        m.Secret,
+		EventTypes:    eventTypes,
+		UseCaseID:     m.UseCaseID,
+		RolloutStates: rolloutStates,
+		Active:        m.Active,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func (m *webhookModel) fromEntity(e webhookEntity) error {
+	// Convert the object lists in JSON for saving
+	eventTypes, err := json.Marshal(e.EventTypes)
+	if err != nil {
+		return err
+	}
+	rolloutStates, err := json.Marshal(e.RolloutStates)
+	if err != nil {
+		return err
+	}
+	m.ID = e.ID
+	m.Title = e.Title
+	m.Url = e.Url
+	m.Secret = e.Secret
+	m.EventTypes = eventTypes
+	m.UseCaseID = e.UseCaseID
+	m.RolloutStates = rolloutStates
+	m.Active = e.Active
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+	return nil
+}
+
+type webhookDeliveryModel struct {
+	ID             uuid.UUID                 `gorm:"primaryKey;column:id;type:varchar(36)"`
+	WebhookID      uuid.UUID                 `gorm:"column:webhook_id;type:varchar(36)"`
+	EventID        uuid.UUID                 `gorm:"column:event_id;type:varchar(36)"`
+	EventType      mm_pubsub.PubSubEventType `gorm:"column:event_type;type:varchar(255)"`
+	Status         webhookDeliveryStatus     `gorm:"column:status;type:varchar(32)"`
+	Attempts       int                       `gorm:"column:attempts;type:integer"`
+	ResponseStatus *int                      `gorm:"column:response_status;type:integer"`
+	LastError      *string                   `gorm:"column:last_error;type:text"`
+	NextAttemptAt  *time.Time                `gorm:"column:next_attempt_at;type:timestamp"`
+	Payload        json.RawMessage           `gorm:"column:payload;type:json"`
+	CreatedAt      time.Time                 `gorm:"column:created_at;type:timestamp;autoCreateTime:false"`
+	UpdatedAt      time.Time                 `gorm:"column:updated_at;type:timestamp;autoUpdateTime:false"`
+}
+
+func (m webhookDeliveryModel) TableName() string {
+	return "mm_webhook_delivery"
+}
+
+func (m webhookDeliveryModel) toEntity() webhookDeliveryEntity {
+	return webhookDeliveryEntity(m)
+}
+
+type webhookOrderBy string
+
+const (
+	webhookOrderByTitle     webhookOrderBy = "title"
+	webhookOrderByActive    webhookOrderBy = "active"
+	webhookOrderByCreatedAt webhookOrderBy = "created_at"
+	webhookOrderByUpdatedAt webhookOrderBy = "updated_at"
+)
+
+var availableWebhookOrderBy = []interface{}{
+	webhookOrderByTitle,
+	webhookOrderByActive,
+	webhookOrderByCreatedAt,
+	webhookOrderByUpdatedAt,
+}
@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_db"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepositoryInterface interface {
+	checkUseCaseExists(tx *gorm.DB, useCaseID uuid.UUID) (bool, error)
+	listWebhooks(tx *gorm.DB, limit int, offset int, orderBy webhookOrderBy, orderDir mm_db.OrderDir, forUpdate bool) ([]webhookEntity, int64, error)
+	listActiveWebhooks(tx *gorm.DB) ([]webhookEntity, error)
+	getWebhookByID(tx *gorm.DB, webhookID uuid.UUID, forUpdate bool) (webhookEntity, error)
+	saveWebhook(tx *gorm.DB, webhook webhookEntity, operation mm_db.SaveOperation) (webhookEntity, error)
+	deleteWebhook(tx *gorm.DB, webhook webhookEntity) (webhookEntity, error)
+	listWebhookDeliveries(tx *gorm.DB, webhookID uuid.UUID, status *webhookDeliveryStatus, limit int, offset int) ([]webhookDeliveryEntity, int64, error)
+	listWebhookDeliveriesToSend(tx *gorm.DB, now time.Time, limit int) ([]webhookDeliveryEntity, error)
+	createWebhookDeliveryIfNotExists(tx *gorm.DB, delivery webhookDeliveryEntity) (bool, error)
+	saveWebhookDelivery(tx *gorm.DB, delivery webhookDeliveryEntity, operation mm_db.SaveOperation) (webhookDeliveryEntity, error)
+}
+
+type webhookRepository struct {
+}
+
+func newWebhookRepository() webhookRepository {
+	return webhookRepository{}
+}
+
+func (r webhookRepository) checkUseCaseExists(tx *gorm.DB, useCaseID uuid.UUID) (bool, error) {
+	var model *useCaseModel
+	query := tx.Where("id = ?", useCaseID)
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 || mm_utils.IsEmpty(model) {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (r webhookRepository) listWebhooks(tx *gorm.DB, limit int, offset int, orderBy webhookOrderBy, orderDir mm_db.OrderDir, forUpdate bool) ([]webhookEntity, int64, error) {
+	var totalCount int64
+	var models []*webhookModel
+	query := tx.Model(webhookModel{})
+	queryCount := tx.Model(webhookModel{})
+	if forUpdate {
+		query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	result := query.Limit(limit).Offset(offset).Order(fmt.Sprintf("%s %s", orderBy, orderDir)).Find(&models)
+	queryCount.Count(&totalCount)
+	if result.Error != nil {
+		return []webhookEntity{}, 0, result.Error
+	}
+	var entities []webhookEntity = []webhookEntity{}
+	for _, model := range models {
+		entities = append(entities, model.toEntity())
+	}
+	return entities, totalCount, nil
+}
+
+func (r webhookRepository) listActiveWebhooks(tx *gorm.DB) ([]webhookEntity, error) {
+	var models []*webhookModel
+	result := tx.Where("active IS TRUE").Order("created_at ASC").Find(&models)
+	if result.Error != nil {
+		return []webhookEntity{}, result.Error
+	}
+	var entities []webhookEntity = []webhookEntity{}
+	for _, model := range models {
+		entities = append(entities, model.toEntity())
+	}
+	return entities, nil
+}
+
+func (r webhookRepository) getWebhookByID(tx *gorm.DB, webhookID uuid.UUID, forUpdate bool) (webhookEntity, error) {
+	var model *webhookModel
+	query := tx.Where("id = ?", webhookID)
+	if forUpdate {
+		query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	result := query.Limit(1).Find(&model)
+	if result.Error != nil {
+		return webhookEntity{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return webhookEntity{}, nil
+	}
+	return model.toEntity(), nil
+}
+
+func (r webhookRepository) saveWebhook(tx *gorm.DB, webhook webhookEntity, operation mm_db.SaveOperation) (webhookEntity, error) {
+	var err error
+	var model webhookModel
+	if err = model.fromEntity(webhook); err != nil {
+		return webhookEntity{}, err
+	}
+	switch operation {
+	case mm_db.Create:
+		err = tx.Create(model).Error
+	case mm_db.Update:
+		err = tx.Updates(model).Error
+	case mm_db.Upsert:
+		err = tx.Save(model).Error
+	}
+	if err != nil {
+		return webhookEntity{}, err
+	}
+	return webhook, nil
+}
+
+func (r webhookRepository) deleteWebhook(tx *gorm.DB, webhook webhookEntity) (webhookEntity, error) {
+	var model webhookModel
+	if err := model.fromEntity(webhook); err != nil {
+		return webhookEntity{}, err
+	}
+	if err := tx.Delete(model).Error; err != nil {
+		return webhookEntity{}, err
+	}
+	return webhook, nil
+}
+
+func (r webhookRepository) listWebhookDeliveries(tx *gorm.DB, webhookID uuid.UUID, status *webhookDeliveryStatus, limit int, offset int) ([]webhookDeliveryEntity, int64, error) {
+	var totalCount int64
+	var models []*webhookDeliveryModel
+	query := tx.Model(webhookDeliveryModel{}).Where("webhook_id = ?", webhookID)
+	queryCount := tx.Model(webhookDeliveryModel{}).Where("webhook_id = ?", webhookID)
+	if status != nil {
+		query.Where("status = ?", *status)
+		queryCount.Where("status = ?", *status)
+	}
+	result := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&models)
+	queryCount.Count(&totalCount)
+	if result.Error != nil {
+		return []webhookDeliveryEntity{}, 0, result.Error
+	}
+	var entities []webhookDeliveryEntity = []webhookDeliveryEntity{}
+	for _, model := range models {
+		entities = append(entities, model.toEntity())
+	}
+	return entities, totalCount, nil
+}
+
+func (r webhookRepository) listWebhookDeliveriesToSend(tx *gorm.DB, now time.Time, limit int) ([]webhookDeliveryEntity, error) {
+	var models []*webhookDeliveryModel
+	result := tx.Where("status IN ? AND next_attempt_at <= ?", []webhookDeliveryStatus{webhookDeliveryStatusPending, webhookDeliveryStatusRetry}, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&models)
+	if result.Error != nil {
+		return []webhookDeliveryEntity{}, result.Error
+	}
+	var entities []webhookDeliveryEntity = []webhookDeliveryEntity{}
+	for _, model := range models {
+		entities = append(entities, model.toEntity())
+	}
+	return entities, nil
+}
+
+func (r webhookRepository) createWebhookDeliveryIfNotExists(tx *gorm.DB, delivery webhookDeliveryEntity) (bool, error) {
+	var model = webhookDeliveryModel(delivery)
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r webhookRepository) saveWebhookDelivery(tx *gorm.DB, delivery webhookDeliveryEntity, operation mm_db.SaveOperation) (webhookDeliveryEntity, error) {
+	var model = webhookDeliveryModel(delivery)
+	var err error
+	switch operation {
+	case mm_db.Create:
+		err = tx.Create(model).Error
+	case mm_db.Update:
+		err = tx.Updates(model).Error
+	case mm_db.Upsert:
+		err = tx.Save(model).Error
+	}
+	if err != nil {
+		return webhookDeliveryEntity{}, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_log"
+	"github.com/ai-model-match/backend/internal/pkg/mm_scheduler"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type webhookSchedulerInterface interface {
+	init()
+}
+
+type webhookScheduler struct {
+	scheduler        *mm_scheduler.Scheduler
+	storage          *gorm.DB
+	singleConnection *mm_scheduler.SingleConnection
+	service          webhookServiceInterface
+}
+
+func newWebhookScheduler(storage *gorm.DB, scheduler *mm_scheduler.Scheduler, service webhookServiceInterface) webhookScheduler {
+	singleConnection := scheduler.GetSingleConnection(storage)
+	return webhookScheduler{
+		scheduler:        scheduler,
+		storage:          storage,
+		singleConnection: singleConnection,
+		service:          service,
+	}
+}
+
+func (s webhookScheduler) init() {
+	// Declare all jobs to be scheduled
+	var jobsToSchedule []mm_scheduler.ScheduledJob = []mm_scheduler.ScheduledJob{
+		{
+			Schedule: "* * * * *", // Every minute
+			Handler:  s.sendWebhookDeliveries,
+			Parameters: mm_scheduler.ScheduledJobParameter{
+				JobID: 47382915,
+				Title: "SendWebhookDeliveries",
+			},
+		},
+	}
+	// Schedule all jobs
+	for _, jobToSchedule := range jobsToSchedule {
+		s.scheduler.AddJob(mm_scheduler.ScheduledJob{
+			Schedule:   jobToSchedule.Schedule,
+			Handler:    jobToSchedule.Handler,
+			Parameters: jobToSchedule.Parameters,
+		})
+	}
+}
+
+/*
+Scheduled function to run. It sends the new webhook deliveries and the ones waiting for a retry
+*/
+func (s webhookScheduler) sendWebhookDeliveries(p mm_scheduler.ScheduledJobParameter) error {
+	defer func() {
+		if r := recover(); r != nil {
+			mm_log.LogPanicError(r, "SendWebhookDeliveries", "Panic occurred in cron activity")
+		}
+	}()
+	// If this istance acquires the lock, executre the business logic
+	if lockAcquired := s.scheduler.AcquireLock(s.singleConnection, p.JobID); lockAcquired {
+		zap.L().Info("Starting Cron Job...", zap.String("job", p.Title))
+		if err := s.service.sendPendingDeliveries(); err != nil {
+			zap.L().Error("Cron Job Failed", zap.String("job", p.Title), zap.Error(err), zap.String("service", "webhook-scheduler"))
+			return err
+		}
+		zap.L().Info("Cron Job executed!", zap.String("job", p.Title))
+	}
+	return nil
+}
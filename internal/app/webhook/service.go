@@ -0,0 +1,349 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_db"
+	"github.com/ai-model-match/backend/internal/pkg/mm_err"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type webhookServiceInterface interface {
+	listWebhooks(ctx *gin.Context, input listWebhooksInputDto) ([]webhookEntity, int64, error)
+	getWebhookByID(ctx *gin.Context, input getWebhookInputDto) (webhookEntity, error)
+	createWebhook(ctx *gin.Context, input createWebhookInputDto) (webhookEntity, error)
+	updateWebhook(ctx *gin.Context, input updateWebhookInputDto) (webhookEntity, error)
+	deleteWebhook(ctx *gin.Context, input deleteWebhookInputDto) (webhookEntity, error)
+	listWebhookDeliveries(ctx *gin.Context, input listWebhookDeliveriesInputDto) ([]webhookDeliveryEntity, int64, error)
+	dispatchEvent(event mm_pubsub.PubSubEvent) error
+	sendPendingDeliveries() error
+}
+
+type webhookService struct {
+	storage    *gorm.DB
+	repository webhookRepositoryInterface
+	httpClient *http.Client
+}
+
+func newWebhookService(storage *gorm.DB, repository webhookRepositoryInterface) webhookService {
+	return webhookService{
+		storage:    storage,
+		repository: repository,
+		httpClient: &http.Client{
+			Timeout: webhookRequestTimeout,
+		},
+	}
+}
+
+func (s webhookService) listWebhooks(ctx *gin.Context, input listWebhooksInputDto) ([]webhookEntity, int64, error) {
+	limit, offset := mm_utils.PagePageSizeToLimitOffset(input.Page, input.PageSize)
+	items, totalCount, err := s.repository.listWebhooks(s.storage, limit, offset, webhookOrderBy(input.OrderBy), mm_db.OrderDir(input.OrderDir), false)
+	if err != nil || items == nil {
+		return []webhookEntity{}, 0, mm_err.ErrGeneric
+	}
+	return items, totalCount, nil
+}
+
+func (s webhookService) getWebhookByID(ctx *gin.Context, input getWebhookInputDto) (webhookEntity, error) {
+	webhookID := uuid.MustParse(input.ID)
+	item, err := s.repository.getWebhookByID(s.storage, webhookID, false)
+	if err != nil {
+		return webhookEntity{}, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(item) {
+		return webhookEntity{}, errWebhookNotFound
+	}
+	return item, nil
+}
+
+func (s webhookService) createWebhook(ctx *gin.Context, input createWebhookInputDto) (webhookEntity, error) {
+	now := time.Now()
+	newWebhook := webhookEntity{
+		ID:            uuid.New(),
+		Title:         input.Title,
+		Url:           input.Url,
+		Secret:        input.Secret,
+		EventTypes:    toEventTypes(input.EventTypes),
+		UseCaseID:     mm_utils.GetOptionalUUIDFromString(input.UseCaseID),
+		RolloutStates: toRolloutStates(input.RolloutStates),
+		Active:        input.Active,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+		if newWebhook.UseCaseID != nil {
+			exists, err := s.repository.checkUseCaseExists(tx, *newWebhook.UseCaseID)
+			if err != nil {
+				return mm_err.ErrGeneric
+			}
+			if !exists {
+				return errUseCaseNotFound
+			}
+		}
+		if _, err := s.repository.saveWebhook(tx, newWebhook, mm_db.Create); err != nil {
+			return mm_err.ErrGeneric
+		}
+		return nil
+	})
+	if errTransaction != nil {
+		return webhookEntity{}, errTransaction
+	}
+	return newWebhook, nil
+}
+
+func (s webhookService) updateWebhook(ctx *gin.Context, input updateWebhookInputDto) (webhookEntity, error) {
+	var updatedWebhook webhookEntity
+	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+		// Check if the webhook exists
+		webhookID := uuid.MustParse(input.ID)
+		currentWebhook, err := s.repository.getWebhookByID(tx, webhookID, true)
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		if mm_utils.IsEmpty(currentWebhook) {
+			return errWebhookNotFound
+		}
+		// Update webhook information based on inputs
+		updatedWebhook = currentWebhook
+		updatedWebhook.UpdatedAt = time.Now()
+		if input.Title != nil {
+			updatedWebhook.Title = *input.Title
+		}
+		if input.Url != nil {
+			updatedWebhook.Url = *input.Url
+		}
+		if input.Secret != nil {
+			updatedWebhook.Secret = *input.Secret
+		}
+		if input.EventTypes != nil {
+			updatedWebhook.EventTypes = toEventTypes(*input.EventTypes)
+		}
+		if input.RolloutStates != nil {
+			updatedWebhook.RolloutStates = toRolloutStates(*input.RolloutStates)
+		}
+		if input.Active != nil {
+			updatedWebhook.Active = input.Active
+		}
+		if _, err := s.repository.saveWebhook(tx, updatedWebhook, mm_db.Update); err != nil {
+			return mm_err.ErrGeneric
+		}
+		return nil
+	})
+	if errTransaction != nil {
+		return webhookEntity{}, errTransaction
+	}
+	return updatedWebhook, nil
+}
+
+func (s webhookService) deleteWebhook(ctx *gin.Context, input deleteWebhookInputDto) (webhookEntity, error) {
+	var currentWebhook webhookEntity
+	errTransaction := s.storage.Transaction(func(tx *gorm.DB) error {
+		// Check if the webhook exists
+		webhookID := uuid.MustParse(input.ID)
+		var err error
+		currentWebhook, err = s.repository.getWebhookByID(tx, webhookID, true)
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		if mm_utils.IsEmpty(currentWebhook) {
+			return errWebhookNotFound
+		}
+		if _, err := s.repository.deleteWebhook(tx, currentWebhook); err != nil {
+			return mm_err.ErrGeneric
+		}
+		return nil
+	})
+	if errTransaction != nil {
+		return webhookEntity{}, errTransaction
+	}
+	return currentWebhook, nil
+}
+
+func (s webhookService) listWebhookDeliveries(ctx *gin.Context, input listWebhookDeliveriesInputDto) ([]webhookDeliveryEntity, int64, error) {
+	webhookID := uuid.MustParse(input.WebhookID)
+	webhook, err := s.repository.getWebhookByID(s.storage, webhookID, false)
+	if err != nil {
+		return []webhookDeliveryEntity{}, 0, mm_err.ErrGeneric
+	}
+	if mm_utils.IsEmpty(webhook) {
+		return []webhookDeliveryEntity{}, 0, errWebhookNotFound
+	}
+	var status *webhookDeliveryStatus
+	if input.Status != nil {
+		deliveryStatus := webhookDeliveryStatus(*input.Status)
+		status = &deliveryStatus
+	}
+	limit, offset := mm_utils.PagePageSizeToLimitOffset(input.Page, input.PageSize)
+	items, totalCount, err := s.repository.listWebhookDeliveries(s.storage, webhookID, status, limit, offset)
+	if err != nil || items == nil {
+		return []webhookDeliveryEntity{}, 0, mm_err.ErrGeneric
+	}
+	return items, totalCount, nil
+}
+
+/*
+Create a pending delivery for each active webhook subscribed to the event. Deliveries are sent by the
+scheduler, so slow webhooks never block the consumers of the events.
+Deliveries are idempotent: the same event is never delivered twice to the same webhook.
+*/
+func (s webhookService) dispatchEvent(event mm_pubsub.PubSubEvent) error {
+	useCaseID, rolloutState := extractEventFilters(event)
+	return s.storage.Transaction(func(tx *gorm.DB) error {
+		webhooks, err := s.repository.listActiveWebhooks(tx)
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		now := time.Now()
+		for _, webhook := range webhooks {
+			if !webhookMatchesEvent(webhook, event.EventType, useCaseID, rolloutState) {
+				continue
+			}
+			delivery := webhookDeliveryEntity{
+				ID:            uuid.New(),
+				WebhookID:     webhook.ID,
+				EventID:       event.EventID,
+				EventType:     event.EventType,
+				Status:        webhookDeliveryStatusPending,
+				Attempts:      0,
+				NextAttemptAt: &now,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			payload, err := json.Marshal(webhookPayloadEntity{
+				WebhookID:          webhook.ID,
+				DeliveryID:         delivery.ID,
+				EventID:            event.EventID,
+				EventTime:          event.EventTime,
+				EventType:          event.EventType,
+				EventEntity:        event.EventEntity,
+				EventChangedFields: event.EventChangedFields,
+			})
+			if err != nil {
+				return mm_err.ErrGeneric
+			}
+			delivery.Payload = payload
+			if _, err := s.repository.createWebhookDeliveryIfNotExists(tx, delivery); err != nil {
+				return mm_err.ErrGeneric
+			}
+		}
+		return nil
+	})
+}
+
+/*
+Send all the new deliveries and the failed ones ready for a new attempt.
+A delivery that cannot be stored does not prevent the others from being sent.
+*/
+func (s webhookService) sendPendingDeliveries() error {
+	deliveries, err := s.repository.listWebhookDeliveriesToSend(s.storage, time.Now(), webhookSendBatchSize)
+	if err != nil {
+		return mm_err.ErrGeneric
+	}
+	for _, delivery := range deliveries {
+		webhook, err := s.repository.getWebhookByID(s.storage, delivery.WebhookID, false)
+		if err != nil {
+			return mm_err.ErrGeneric
+		}
+		// Skip deliveries of disabled webhooks
+		if mm_utils.IsEmpty(webhook) || !*webhook.Active {
+			lastError := "webhook-disabled"
+			delivery.Status = webhookDeliveryStatusFailed
+			delivery.LastError = &lastError
+			delivery.NextAttemptAt = nil
+			delivery.UpdatedAt = time.Now()
+			if _, err := s.repository.saveWebhookDelivery(s.storage, delivery, mm_db.Upsert); err != nil {
+				return mm_err.ErrGeneric
+			}
+			continue
+		}
+		if _, err := s.sendDelivery(webhook, delivery); err != nil {
+			zap.L().Error("Impossible to store the webhook delivery outcome", zap.String("service", "webhook-service"), zap.String("delivery-id", delivery.ID.String()), zap.Error(err))
+			continue
+		}
+	}
+	return nil
+}
+
+/*
+Send the signed payload to the webhook URL and store the outcome in the delivery log.
+*/
+func (s webhookService) sendDelivery(webhook webhookEntity, delivery webhookDeliveryEntity) (webhookDeliveryEntity, error) {
+	delivery.Attempts = delivery.Attempts + 1
+	responseStatus, sendErr := s.postPayload(webhook, delivery)
+	delivery.ResponseStatus = responseStatus
+	delivery.UpdatedAt = time.Now()
+	if sendErr == nil {
+		delivery.Status = webhookDeliveryStatusSuccess
+		delivery.LastError = nil
+		delivery.NextAttemptAt = nil
+	} else {
+		lastError := sendErr.Error()
+		delivery.LastError = &lastError
+		if delivery.Attempts >= webhookMaxDeliveryAttempts {
+			delivery.Status = webhookDeliveryStatusFailed
+			delivery.NextAttemptAt = nil
+		} else {
+			nextAttemptAt := time.Now().Add(calculateRetryDelay(delivery.Attempts))
+			delivery.Status = webhookDeliveryStatusRetry
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+		zap.L().Info("Webhook delivery failed", zap.String("service", "webhook-service"), zap.String("delivery-id", delivery.ID.String()), zap.Int("attempts", delivery.Attempts), zap.Error(sendErr))
+	}
+	if _, err := s.repository.saveWebhookDelivery(s.storage, delivery, mm_db.Upsert); err != nil {
+		return webhookDeliveryEntity{}, mm_err.ErrGeneric
+	}
+	return delivery, nil
+}
+
+/*
+Perform the HTTP call. Any response other than 2xx is considered a failure.
+*/
+func (s webhookService) postPayload(webhook webhookEntity, delivery webhookDeliveryEntity) (*int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", webhook.ID.String())
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", signPayload(webhook.Secret, timestamp, delivery.Payload))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return &resp.StatusCode, nil
+}
+
+/*
+Check if the webhook is interested in the event based on its subscriptions and filters.
+*/
+func webhookMatchesEvent(webhook webhookEntity, eventType mm_pubsub.PubSubEventType, useCaseID *uuid.UUID, rolloutState *mm_pubsub.RolloutState) bool {
+	if !slices.Contains(webhook.EventTypes, eventType) {
+		return false
+	}
+	if webhook.UseCaseID != nil && (useCaseID == nil || *webhook.UseCaseID != *useCaseID) {
+		return false
+	}
+	if len(webhook.RolloutStates) > 0 && rolloutState != nil && !slices.Contains(webhook.RolloutStates, *rolloutState) {
+		return false
+	}
+	return true
+}
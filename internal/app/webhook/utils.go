@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/google/uuid"
+)
+
+/*
+Sign the payload with HMAC-SHA256, using the webhook secret as key.
+The timestamp is part of the signed content, so receivers can reject replayed requests.
+*/
+func signPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+/*
+Calculate the delay before the next attempt with an exponential backoff.
+*/
+func calculateRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay = delay * 2
+	}
+	return delay
+}
+
+/*
+Extract the Use Case and the Rollout State (if any) of the event, used to filter webhooks.
+*/
+func extractEventFilters(event mm_pubsub.PubSubEvent) (*uuid.UUID, *mm_pubsub.RolloutState) {
+	switch entity := event.EventEntity.(type) {
+	case *mm_pubsub.RolloutStrategyEventEntity:
+		return &entity.UseCaseID, &entity.RolloutState
+	case *mm_pubsub.RsEngineEventEntity:
+		return &entity.UseCaseID, &entity.RolloutState
+	case *mm_pubsub.FlowEventEntity:
+		return &entity.UseCaseID, nil
+	}
+	return nil, nil
+}
+
+func toEventTypes(input []string) []mm_pubsub.PubSubEventType {
+	eventTypes := []mm_pubsub.PubSubEventType{}
+	for _, eventType := range input {
+		eventTypes = append(eventTypes, mm_pubsub.PubSubEventType(eventType))
+	}
+	return eventTypes
+}
+
+func toRolloutStates(input []string) []mm_pubsub.RolloutState {
+	rolloutStates := []mm_pubsub.RolloutState{}
+	for _, rolloutState := range input {
+		rolloutStates = append(rolloutStates, mm_pubsub.RolloutState(rolloutState))
+	}
+	return rolloutStates
+}
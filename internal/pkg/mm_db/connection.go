@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ai-model-match/backend/internal/pkg/mm_tracing"
 	"go.uber.org/zap"
 	"moul.io/zapgorm2"
 
@@ -47,6 +48,11 @@ func NewDatabaseConnection(dbHost string, dbUsername string, dbPassword string,
 		zap.L().Error("Connection to DB failed!", zap.String("service", "db-connection"), zap.Error(err))
 		panic(err)
 	}
+	// Trace queries executed within a traced operation
+	if err := database.Use(mm_tracing.GormPlugin()); err != nil {
+		zap.L().Error("Impossible to register the tracing plugin", zap.String("service", "db-connection"), zap.Error(err))
+		panic(err)
+	}
 	zap.L().Info("Connection to DB done!", zap.String("service", "db-connection"))
 	return database
 }
@@ -30,6 +30,19 @@ type Envs struct {
 	PubSubPersistEventsOnDb          bool
 	PubSubPersistEventsRetentionDays int
 	PubSubSyncMode                   bool
+	PubSubDistributedMode            bool
+	PubSubMaxDeliveryAttempts        int
+	OutboxWebhookEnabled             bool
+	OutboxWebhookUrl                 string
+	OutboxNatsEnabled                bool
+	OutboxNatsUrl                    string
+	OutboxNatsSubjectPrefix          string
+	OutboxSinkTimeoutSeconds         int
+	OutboxMaxAttempts                int
+	TracingExporter                  string
+	TracingOtlpEndpoint              string
+	TracingServiceName               string
+	TracingSampleRatio               float64
 	PickerCorrelationValidityHours   int
 	AuthUserReadOnlyUsername         string
 	AuthUserReadOnlyPassword         string
@@ -64,6 +77,19 @@ func ReadEnvs() *Envs {
 		PubSubPersistEventsOnDb:          getMandatoryBooleanValue("PUBSUB_PERSIST_EVENTS_ON_DB"),
 		PubSubPersistEventsRetentionDays: getMandatoryIntValue("PUBSUB_PERSIST_EVENTS_RETENTION_DAYS"),
 		PubSubSyncMode:                   getMandatoryBooleanValue("PUBSUB_SYNC_MODE"),
+		PubSubDistributedMode:            getMandatoryBooleanValue("PUBSUB_DISTRIBUTED_MODE"),
+		PubSubMaxDeliveryAttempts:        getMandatoryIntValue("PUBSUB_MAX_DELIVERY_ATTEMPTS"),
+		OutboxWebhookEnabled:             getMandatoryBooleanValue("OUTBOX_WEBHOOK_ENABLED"),
+		OutboxWebhookUrl:                 getMandatoryStringValue("OUTBOX_WEBHOOK_URL"),
+		OutboxNatsEnabled:                getMandatoryBooleanValue("OUTBOX_NATS_ENABLED"),
+		OutboxNatsUrl:                    getMandatoryStringValue("OUTBOX_NATS_URL"),
+		OutboxNatsSubjectPrefix:          getMandatoryStringValue("OUTBOX_NATS_SUBJECT_PREFIX"),
+		OutboxSinkTimeoutSeconds:         getMandatoryIntValue("OUTBOX_SINK_TIMEOUT_SECONDS"),
+		OutboxMaxAttempts:                getMandatoryIntValue("OUTBOX_MAX_ATTEMPTS"),
+		TracingExporter:                  getMandatoryStringValue("TRACING_EXPORTER"),
+		TracingOtlpEndpoint:              getMandatoryStringValue("TRACING_OTLP_ENDPOINT"),
+		TracingServiceName:               getMandatoryStringValue("TRACING_SERVICE_NAME"),
+		TracingSampleRatio:               getMandatoryFloatValue("TRACING_SAMPLE_RATIO"),
 		PickerCorrelationValidityHours:   getMandatoryIntValue("PICKER_CORRELATION_VALIDITY_HOURS"),
 		AuthUserReadOnlyUsername:         getMandatoryStringValue("AUTH_USER_READ_ONLY_USERNAME"),
 		AuthUserReadOnlyPassword:         getMandatoryStringValue("AUTH_USER_READ_ONLY_PASSWORD"),
@@ -0,0 +1,64 @@
+package mm_outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type eventModel struct {
+	ID        uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	Topic     string          `gorm:"column:topic;type:varchar(255)"`
+	EventType string          `gorm:"column:event_type;type:varchar(255)"`
+	EventDate time.Time       `gorm:"column:event_date;type:timestamp"`
+	EventBody json.RawMessage `gorm:"column:event_body;type:json"`
+	TxID      int64           `gorm:"column:txid;type:bigint"`
+	Seq       int64           `gorm:"column:seq;type:bigint"`
+}
+
+func (m eventModel) TableName() string {
+	return "mm_event"
+}
+
+func (m eventModel) toEntity() OutboxEvent {
+	return OutboxEvent{
+		ID:        m.ID,
+		Topic:     m.Topic,
+		EventType: m.EventType,
+		EventDate: m.EventDate,
+		EventBody: m.EventBody,
+	}
+}
+
+type cursorModel struct {
+	Sink          string     `gorm:"primaryKey;column:sink;type:varchar(255)"`
+	Topic         string     `gorm:"primaryKey;column:topic;type:varchar(255)"`
+	LastEventID   *uuid.UUID `gorm:"column:last_event_id;type:varchar(36)"`
+	LastTxID      int64      `gorm:"column:last_txid;type:bigint"`
+	LastSeq       int64      `gorm:"column:last_seq;type:bigint"`
+	Attempts      int        `gorm:"column:attempts;type:integer"`
+	LastError     *string    `gorm:"column:last_error;type:text"`
+	NextAttemptAt *time.Time `gorm:"column:next_attempt_at;type:timestamp"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;type:timestamp"`
+}
+
+func (m cursorModel) TableName() string {
+	return "mm_outbox_cursor"
+}
+
+type deadLetterModel struct {
+	ID        uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	Sink      string          `gorm:"column:sink;type:varchar(255)"`
+	EventID   uuid.UUID       `gorm:"column:event_id;type:varchar(36)"`
+	Topic     string          `gorm:"column:topic;type:varchar(255)"`
+	EventType string          `gorm:"column:event_type;type:varchar(255)"`
+	EventBody json.RawMessage `gorm:"column:event_body;type:json"`
+	Attempts  int             `gorm:"column:attempts;type:integer"`
+	LastError *string         `gorm:"column:last_error;type:text"`
+	CreatedAt time.Time       `gorm:"column:created_at;type:timestamp"`
+}
+
+func (m deadLetterModel) TableName() string {
+	return "mm_outbox_dead_letter"
+}
@@ -0,0 +1,200 @@
+package mm_outbox
+
+import (
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_log"
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_scheduler"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+Number of events read per query and max number of batches relayed per topic in a single run.
+*/
+const relayBatchSize = 500
+const relayMaxBatchesPerRun = 10
+
+/*
+Delay before the first retry. It doubles at each attempt up to the max delay.
+*/
+const retryBaseDelay = 1 * time.Minute
+const retryMaxDelay = 1 * time.Hour
+
+/*
+OutboxRelay streams the events persisted by the pub-sub agent (mm_event) to external sinks.
+For each sink and topic, events are relayed in order and a cursor tracks the last delivered one.
+When a sink fails, the relay of that topic stops and it is retried later with exponential backoff,
+until the max number of attempts is reached and the event is moved to the dead-letter table.
+*/
+type OutboxRelay struct {
+	store            relayStore
+	scheduler        *mm_scheduler.Scheduler
+	singleConnection *mm_scheduler.SingleConnection
+	sinks            []Sink
+	maxAttempts      int
+}
+
+/*
+NewOutboxRelay initializes a new relay for the given sinks, scheduling the relay job.
+*/
+func NewOutboxRelay(dbStorage *gorm.DB, scheduler *mm_scheduler.Scheduler, sinks []Sink, maxAttempts int) *OutboxRelay {
+	zap.L().Info("Start creating Outbox relay...", zap.String("service", "outbox-relay"))
+	relay := &OutboxRelay{
+		store:            newDbRelayStore(dbStorage),
+		scheduler:        scheduler,
+		singleConnection: scheduler.GetSingleConnection(dbStorage),
+		sinks:            sinks,
+		maxAttempts:      maxAttempts,
+	}
+	if len(sinks) > 0 {
+		relay.init()
+	}
+	zap.L().Info("Outbox relay created!", zap.String("service", "outbox-relay"))
+	return relay
+}
+
+func (r *OutboxRelay) init() {
+	r.scheduler.AddJob(mm_scheduler.ScheduledJob{
+		Schedule: "* * * * *", // Every minute
+		Handler:  r.relayEvents,
+		Parameters: mm_scheduler.ScheduledJobParameter{
+			JobID: 29471063,
+			Title: "RelayOutboxEvents",
+		},
+	})
+}
+
+/*
+Scheduled function to run. It relays new events to all the sinks
+*/
+func (r *OutboxRelay) relayEvents(p mm_scheduler.ScheduledJobParameter) error {
+	defer func() {
+		if rec := recover(); rec != nil {
+			mm_log.LogPanicError(rec, "RelayOutboxEvents", "Panic occurred in cron activity")
+		}
+	}()
+	// If this istance acquires the lock, executre the business logic
+	if lockAcquired := r.scheduler.AcquireLock(r.singleConnection, p.JobID); lockAcquired {
+		zap.L().Info("Starting Cron Job...", zap.String("job", p.Title))
+		if err := r.relayAll(); err != nil {
+			zap.L().Error("Cron Job Failed", zap.String("job", p.Title), zap.Error(err))
+			return err
+		}
+		zap.L().Info("Cron Job executed!", zap.String("job", p.Title))
+	}
+	return nil
+}
+
+/*
+Relay the new events of all the topics to all the sinks.
+*/
+func (r *OutboxRelay) relayAll() error {
+	for _, sink := range r.sinks {
+		for _, topic := range mm_pubsub.AvailableTopics {
+			if err := r.relayTopic(sink, topic); err != nil {
+				zap.L().Error("Impossible to relay topic", zap.String("service", "outbox-relay"), zap.String("sink", sink.Name()), zap.String("topic", string(topic)), zap.Error(err))
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Relay in order the events of a topic committed after the sink cursor.
+*/
+func (r *OutboxRelay) relayTopic(sink Sink, topic mm_pubsub.PubSubTopic) error {
+	cursor, err := r.store.getCursor(sink.Name(), topic)
+	if err != nil {
+		return err
+	}
+	// Wait for the backoff after a failure
+	if cursor.NextAttemptAt != nil && cursor.NextAttemptAt.After(time.Now()) {
+		return nil
+	}
+	for range relayMaxBatchesPerRun {
+		events, err := r.store.listEvents(topic, cursor, relayBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if sendErr := sink.Send(event.toEntity()); sendErr != nil {
+				if cursor.Attempts+1 < r.maxAttempts {
+					return r.markFailure(&cursor, sendErr)
+				}
+				// Max attempts reached: move the event in the dead-letter table and go on with the next ones
+				if err := r.moveToDeadLetter(cursor, event, sendErr); err != nil {
+					return err
+				}
+			}
+			if err := r.moveCursor(&cursor, event); err != nil {
+				return err
+			}
+		}
+		if len(events) < relayBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+/*
+Move the cursor after the delivered event, resetting previous failures.
+*/
+func (r *OutboxRelay) moveCursor(cursor *cursorModel, event eventModel) error {
+	cursor.LastEventID = &event.ID
+	cursor.LastTxID = event.TxID
+	cursor.LastSeq = event.Seq
+	cursor.Attempts = 0
+	cursor.LastError = nil
+	cursor.NextAttemptAt = nil
+	cursor.UpdatedAt = time.Now()
+	return r.store.saveCursor(*cursor)
+}
+
+/*
+Keep the cursor on the failed event and schedule the next attempt.
+*/
+func (r *OutboxRelay) markFailure(cursor *cursorModel, sendErr error) error {
+	zap.L().Error("Impossible to relay event", zap.String("service", "outbox-relay"), zap.String("sink", cursor.Sink), zap.String("topic", cursor.Topic), zap.Int("attempts", cursor.Attempts+1), zap.Error(sendErr))
+	lastError := sendErr.Error()
+	nextAttemptAt := time.Now().Add(retryDelay(cursor.Attempts + 1))
+	cursor.Attempts = cursor.Attempts + 1
+	cursor.LastError = &lastError
+	cursor.NextAttemptAt = &nextAttemptAt
+	cursor.UpdatedAt = time.Now()
+	return r.store.saveCursor(*cursor)
+}
+
+/*
+Store the event that the sink failed to accept too many times, so it does not block the next ones.
+*/
+func (r *OutboxRelay) moveToDeadLetter(cursor cursorModel, event eventModel, sendErr error) error {
+	zap.L().Warn("Event moved to outbox dead-letter", zap.String("service", "outbox-relay"), zap.String("sink", cursor.Sink), zap.String("topic", cursor.Topic), zap.String("event-id", event.ID.String()), zap.Error(sendErr))
+	lastError := sendErr.Error()
+	return r.store.saveDeadLetter(deadLetterModel{
+		ID:        uuid.New(),
+		Sink:      cursor.Sink,
+		EventID:   event.ID,
+		Topic:     event.Topic,
+		EventType: event.EventType,
+		EventBody: event.EventBody,
+		Attempts:  cursor.Attempts + 1,
+		LastError: &lastError,
+		CreatedAt: time.Now(),
+	})
+}
+
+/*
+Calculate the delay before the next attempt, based on the number of attempts already performed.
+*/
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay = delay * 2
+	}
+	return min(delay, retryMaxDelay)
+}
@@ -0,0 +1,285 @@
+package mm_outbox
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/google/uuid"
+)
+
+/*
+fakeSink is an in-process sink that keeps relayed events in memory.
+*/
+type fakeSink struct {
+	mu     sync.Mutex
+	name   string
+	events []OutboxEvent
+	err    error
+}
+
+func newFakeSink(name string) *fakeSink {
+	return &fakeSink{
+		name: name,
+	}
+}
+
+func (s *fakeSink) Name() string {
+	return s.name
+}
+
+func (s *fakeSink) Send(event OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+/*
+Make the sink reject all the next events with the given error (nil to accept them again).
+*/
+func (s *fakeSink) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+/*
+Return the IDs of the events received so far, in order.
+*/
+func (s *fakeSink) eventIDs() []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]uuid.UUID, len(s.events))
+	for i, event := range s.events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+/*
+memoryRelayStore keeps events and cursors in memory. Events of transactions not older than
+runningTxID are considered still in progress, as the oldest running transaction on DB.
+*/
+type memoryRelayStore struct {
+	events      []eventModel
+	cursors     map[string]cursorModel
+	deadLetters []deadLetterModel
+	runningTxID int64
+}
+
+func newMemoryRelayStore() *memoryRelayStore {
+	return &memoryRelayStore{
+		cursors:     map[string]cursorModel{},
+		runningTxID: 1 << 62,
+	}
+}
+
+func (s *memoryRelayStore) addEvent(topic mm_pubsub.PubSubTopic, txID int64, seq int64) uuid.UUID {
+	event := eventModel{
+		ID:        uuid.New(),
+		Topic:     string(topic),
+		EventType: "test.created",
+		EventDate: time.Now(),
+		EventBody: []byte(`{}`),
+		TxID:      txID,
+		Seq:       seq,
+	}
+	s.events = append(s.events, event)
+	return event.ID
+}
+
+func (s *memoryRelayStore) getCursor(sink string, topic mm_pubsub.PubSubTopic) (cursorModel, error) {
+	key := sink + "|" + string(topic)
+	if _, ok := s.cursors[key]; !ok {
+		s.cursors[key] = cursorModel{Sink: sink, Topic: string(topic), UpdatedAt: time.Now()}
+	}
+	return s.cursors[key], nil
+}
+
+func (s *memoryRelayStore) saveCursor(cursor cursorModel) error {
+	s.cursors[cursor.Sink+"|"+cursor.Topic] = cursor
+	return nil
+}
+
+func (s *memoryRelayStore) listEvents(topic mm_pubsub.PubSubTopic, cursor cursorModel, limit int) ([]eventModel, error) {
+	events := []eventModel{}
+	for _, event := range s.events {
+		afterCursor := event.TxID > cursor.LastTxID || (event.TxID == cursor.LastTxID && event.Seq > cursor.LastSeq)
+		if event.Topic == string(topic) && afterCursor && event.TxID < s.runningTxID {
+			events = append(events, event)
+		}
+	}
+	slices.SortFunc(events, func(a eventModel, b eventModel) int {
+		if a.TxID != b.TxID {
+			return int(a.TxID - b.TxID)
+		}
+		return int(a.Seq - b.Seq)
+	})
+	return events[:min(limit, len(events))], nil
+}
+
+func (s *memoryRelayStore) saveDeadLetter(deadLetter deadLetterModel) error {
+	s.deadLetters = append(s.deadLetters, deadLetter)
+	return nil
+}
+
+func newTestRelay(store relayStore, sink Sink, maxAttempts int) *OutboxRelay {
+	return &OutboxRelay{
+		store:       store,
+		sinks:       []Sink{sink},
+		maxAttempts: maxAttempts,
+	}
+}
+
+func TestRelayMovesCursorAfterDeliveredEvents(t *testing.T) {
+	store := newMemoryRelayStore()
+	sink := newFakeSink("fake")
+	relay := newTestRelay(store, sink, 3)
+	first := store.addEvent(mm_pubsub.TopicPickerV1, 10, 1)
+	second := store.addEvent(mm_pubsub.TopicPickerV1, 11, 2)
+
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cursor, _ := store.getCursor(sink.Name(), mm_pubsub.TopicPickerV1)
+	if *cursor.LastEventID != second || cursor.LastTxID != 11 || cursor.LastSeq != 2 {
+		t.Fatalf("cursor not moved after the last event: %+v", cursor)
+	}
+	// Events already relayed are not sent again
+	third := store.addEvent(mm_pubsub.TopicPickerV1, 12, 3)
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sink.eventIDs(); !slices.Equal(got, []uuid.UUID{first, second, third}) {
+		t.Fatalf("unexpected events relayed: %v", got)
+	}
+}
+
+func TestRelayKeepsTransactionOrderAndWaitsForRunningTransactions(t *testing.T) {
+	store := newMemoryRelayStore()
+	sink := newFakeSink("fake")
+	relay := newTestRelay(store, sink, 3)
+	// The transaction 20 inserted its events before the transaction 21, but it is still running
+	slow := store.addEvent(mm_pubsub.TopicPickerV1, 20, 1)
+	fastFirst := store.addEvent(mm_pubsub.TopicPickerV1, 21, 3)
+	fastSecond := store.addEvent(mm_pubsub.TopicPickerV1, 21, 2)
+	store.addEvent(mm_pubsub.TopicFeedbackV1, 19, 4)
+	store.runningTxID = 20
+
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sink.eventIDs(); len(got) != 0 {
+		t.Fatalf("events relayed before older transactions completed: %v", got)
+	}
+	// Once the slow transaction completes, no event is left behind the cursor
+	store.runningTxID = 100
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sink.eventIDs(); !slices.Equal(got, []uuid.UUID{slow, fastSecond, fastFirst}) {
+		t.Fatalf("unexpected order of events: %v", got)
+	}
+}
+
+func TestRelayRetriesWithBackoff(t *testing.T) {
+	store := newMemoryRelayStore()
+	sink := newFakeSink("fake")
+	relay := newTestRelay(store, sink, 5)
+	event := store.addEvent(mm_pubsub.TopicPickerV1, 10, 1)
+	sink.setError(errors.New("sink unavailable"))
+
+	before := time.Now()
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cursor, _ := store.getCursor(sink.Name(), mm_pubsub.TopicPickerV1)
+	if cursor.Attempts != 1 || cursor.LastEventID != nil || cursor.LastError == nil {
+		t.Fatalf("failure not tracked on the cursor: %+v", cursor)
+	}
+	if cursor.NextAttemptAt == nil || cursor.NextAttemptAt.Before(before.Add(retryBaseDelay)) {
+		t.Fatalf("next attempt not delayed: %+v", cursor.NextAttemptAt)
+	}
+	// Nothing is sent during the backoff, even if the sink is available again
+	sink.setError(nil)
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sink.eventIDs(); len(got) != 0 {
+		t.Fatalf("events relayed during the backoff: %v", got)
+	}
+	// Once the backoff expires, the event is delivered and the failures are reset
+	expired := time.Now().Add(-time.Second)
+	cursor.NextAttemptAt = &expired
+	store.saveCursor(cursor)
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cursor, _ = store.getCursor(sink.Name(), mm_pubsub.TopicPickerV1)
+	if got := sink.eventIDs(); !slices.Equal(got, []uuid.UUID{event}) {
+		t.Fatalf("unexpected events relayed: %v", got)
+	}
+	if cursor.Attempts != 0 || cursor.LastError != nil || cursor.NextAttemptAt != nil {
+		t.Fatalf("failures not reset: %+v", cursor)
+	}
+}
+
+func TestRetryDelayDoublesUpToTheMaxDelay(t *testing.T) {
+	expected := map[int]time.Duration{
+		1:  retryBaseDelay,
+		2:  2 * retryBaseDelay,
+		3:  4 * retryBaseDelay,
+		20: retryMaxDelay,
+	}
+	for attempts, delay := range expected {
+		if got := retryDelay(attempts); got != delay {
+			t.Errorf("retryDelay(%d) = %s, expected %s", attempts, got, delay)
+		}
+	}
+}
+
+func TestRelayMovesEventToDeadLetterAfterMaxAttempts(t *testing.T) {
+	store := newMemoryRelayStore()
+	sink := newFakeSink("fake")
+	relay := newTestRelay(store, sink, 2)
+	poison := store.addEvent(mm_pubsub.TopicPickerV1, 10, 1)
+	sink.setError(errors.New("rejected"))
+
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cursor, _ := store.getCursor(sink.Name(), mm_pubsub.TopicPickerV1)
+	cursor.NextAttemptAt = nil
+	store.saveCursor(cursor)
+	next := store.addEvent(mm_pubsub.TopicPickerV1, 11, 2)
+	// The last attempt fails again: the event is dead-lettered and the next one is not blocked anymore
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.deadLetters) != 1 || store.deadLetters[0].EventID != poison || store.deadLetters[0].Attempts != 2 {
+		t.Fatalf("event not moved to dead-letter: %+v", store.deadLetters)
+	}
+	cursor, _ = store.getCursor(sink.Name(), mm_pubsub.TopicPickerV1)
+	if *cursor.LastEventID != poison || cursor.Attempts != 1 {
+		t.Fatalf("cursor not moved after the dead-lettered event: %+v", cursor)
+	}
+	cursor.NextAttemptAt = nil
+	store.saveCursor(cursor)
+	sink.setError(nil)
+	if err := relay.relayTopic(sink, mm_pubsub.TopicPickerV1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sink.eventIDs(); !slices.Equal(got, []uuid.UUID{next}) {
+		t.Fatalf("unexpected events relayed: %v", got)
+	}
+}
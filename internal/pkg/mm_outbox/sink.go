@@ -0,0 +1,30 @@
+package mm_outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+OutboxEvent represents an event persisted by the pub-sub agent, ready to be relayed to an external sink.
+*/
+type OutboxEvent struct {
+	ID        uuid.UUID       `json:"id"`
+	Topic     string          `json:"topic"`
+	EventType string          `json:"eventType"`
+	EventDate time.Time       `json:"eventDate"`
+	EventBody json.RawMessage `json:"eventBody"`
+}
+
+/*
+Sink represents an external system where events are relayed.
+Events of the same topic are sent one at a time, in order, and the next one is sent only
+once the previous has been accepted: Send must return an error if the event is not delivered.
+*/
+type Sink interface {
+	// Unique name of the sink, used to track its delivery cursors
+	Name() string
+	Send(event OutboxEvent) error
+}
@@ -0,0 +1,83 @@
+package mm_outbox
+
+import (
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+relayStore reads the events to relay and keeps track of the cursors of the sinks.
+*/
+type relayStore interface {
+	getCursor(sink string, topic mm_pubsub.PubSubTopic) (cursorModel, error)
+	saveCursor(cursor cursorModel) error
+	listEvents(topic mm_pubsub.PubSubTopic, cursor cursorModel, limit int) ([]eventModel, error)
+	saveDeadLetter(deadLetter deadLetterModel) error
+}
+
+type dbRelayStore struct {
+	storage *gorm.DB
+}
+
+func newDbRelayStore(storage *gorm.DB) dbRelayStore {
+	return dbRelayStore{
+		storage: storage,
+	}
+}
+
+/*
+Return the cursor of the sink for a topic. New cursors start from the oldest event available.
+*/
+func (s dbRelayStore) getCursor(sink string, topic mm_pubsub.PubSubTopic) (cursorModel, error) {
+	cursor := cursorModel{
+		Sink:      sink,
+		Topic:     string(topic),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.storage.Clauses(clause.OnConflict{DoNothing: true}).Create(&cursor).Error; err != nil {
+		return cursorModel{}, err
+	}
+	if err := s.storage.Where("sink = ? AND topic = ?", sink, topic).Limit(1).Find(&cursor).Error; err != nil {
+		return cursorModel{}, err
+	}
+	return cursor, nil
+}
+
+func (s dbRelayStore) saveCursor(cursor cursorModel) error {
+	return s.storage.Model(&cursorModel{}).
+		Where("sink = ? AND topic = ?", cursor.Sink, cursor.Topic).
+		Updates(map[string]interface{}{
+			"last_event_id":   cursor.LastEventID,
+			"last_txid":       cursor.LastTxID,
+			"last_seq":        cursor.LastSeq,
+			"attempts":        cursor.Attempts,
+			"last_error":      cursor.LastError,
+			"next_attempt_at": cursor.NextAttemptAt,
+			"updated_at":      cursor.UpdatedAt,
+		}).Error
+}
+
+/*
+Return the events of a topic after the cursor, ordered by transaction and insert order.
+Only the events of transactions older than the oldest one still running are returned: all of them are
+already committed (or rolled back), so no event can be committed later behind the cursor.
+*/
+func (s dbRelayStore) listEvents(topic mm_pubsub.PubSubTopic, cursor cursorModel, limit int) ([]eventModel, error) {
+	var events []eventModel
+	if err := s.storage.
+		Where("topic = ? AND (txid, seq) > (?, ?)", topic, cursor.LastTxID, cursor.LastSeq).
+		Where("txid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint").
+		Order("txid ASC, seq ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s dbRelayStore) saveDeadLetter(deadLetter deadLetterModel) error {
+	return s.storage.Create(&deadLetter).Error
+}
@@ -0,0 +1,288 @@
+package mm_provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+jsonSchema is the subset of JSON Schema (draft 2020-12) used to describe the provider configurations:
+types, enums, object properties, array items, string and number ranges, patterns, anyOf and local $ref to $defs.
+Annotations like title and description are kept only to be exposed to the clients.
+*/
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Type                 schemaTypes            `json:"type"`
+	Enum                 []any                  `json:"enum"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	pattern              *regexp.Regexp
+}
+
+/*
+schemaTypes accepts both a single type and a list of types.
+*/
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*t = schemaTypes(multiple)
+	return nil
+}
+
+var availableSchemaTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+/*
+Parse a JSON Schema, compiling its patterns and checking that all the references can be resolved.
+*/
+func compileSchema(data json.RawMessage) (*jsonSchema, error) {
+	var root *jsonSchema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("empty schema")
+	}
+	if err := root.compile(root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (s *jsonSchema) compile(root *jsonSchema) error {
+	for _, t := range s.Type {
+		if !slices.Contains(availableSchemaTypes, t) {
+			return fmt.Errorf("unknown type %s", t)
+		}
+	}
+	if s.Ref != "" {
+		if _, err := root.resolve(s.Ref); err != nil {
+			return err
+		}
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return err
+		}
+		s.pattern = pattern
+	}
+	children := []*jsonSchema{s.Items}
+	children = append(children, s.AnyOf...)
+	for _, child := range s.Properties {
+		children = append(children, child)
+	}
+	for _, child := range s.Defs {
+		children = append(children, child)
+	}
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if err := child.compile(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Resolve a local reference, e.g. #/$defs/message
+*/
+func (s *jsonSchema) resolve(ref string) (*jsonSchema, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %s", ref)
+	}
+	def, ok := s.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown reference %s", ref)
+	}
+	return def, nil
+}
+
+/*
+schemaValidator validates a decoded JSON value against a compiled schema.
+Strings resolved only at runtime (e.g. with placeholders) are checked on their type only. Since they are
+still rendered as strings, they are reported with a dedicated violation where a string is not allowed.
+*/
+type schemaValidator struct {
+	root       *jsonSchema
+	skipString func(s string) bool
+}
+
+/*
+Validate the value and return the violations as "<path> <reason>", where the path is a JSON Pointer
+without the leading slash (empty for the root value).
+*/
+func (v schemaValidator) validate(s *jsonSchema, value any, path string) []string {
+	if s.Ref != "" {
+		s, _ = v.root.resolve(s.Ref)
+	}
+	str, isString := value.(string)
+	runtimeString := isString && v.skipString != nil && v.skipString(str)
+	if len(s.AnyOf) > 0 {
+		matched := slices.ContainsFunc(s.AnyOf, func(sub *jsonSchema) bool {
+			return len(v.validate(sub, value, path)) == 0
+		})
+		if !matched && runtimeString {
+			return []string{violation(path, "cannot contain placeholders, since they are rendered as strings")}
+		}
+		if !matched {
+			return []string{violation(path, "does not match any of the allowed formats")}
+		}
+	}
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return matchesType(value, t) }) {
+		if runtimeString {
+			return []string{violation(path, "cannot contain placeholders, since they are rendered as strings")}
+		}
+		return []string{violation(path, fmt.Sprintf("must be of type %s", strings.Join(s.Type, " or ")))}
+	}
+	violations := []string{}
+	if runtimeString {
+		return violations
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return equalValues(e, value) }) {
+		allowed := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			allowed[i] = fmt.Sprint(e)
+		}
+		violations = append(violations, violation(path, fmt.Sprintf("must be one of %s", strings.Join(allowed, ", "))))
+	}
+	switch val := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(val)
+		if s.MinLength != nil && length < *s.MinLength {
+			violations = append(violations, violation(path, fmt.Sprintf("must be at least %d characters", *s.MinLength)))
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			violations = append(violations, violation(path, fmt.Sprintf("must be no more than %d characters", *s.MaxLength)))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			violations = append(violations, violation(path, "must be in a valid format"))
+		}
+	case json.Number:
+		number, _ := val.Float64()
+		if s.Minimum != nil && number < *s.Minimum {
+			violations = append(violations, violation(path, fmt.Sprintf("must be no less than %s", formatNumber(*s.Minimum))))
+		}
+		if s.Maximum != nil && number > *s.Maximum {
+			violations = append(violations, violation(path, fmt.Sprintf("must be no greater than %s", formatNumber(*s.Maximum))))
+		}
+		if s.ExclusiveMinimum != nil && number <= *s.ExclusiveMinimum {
+			violations = append(violations, violation(path, fmt.Sprintf("must be greater than %s", formatNumber(*s.ExclusiveMinimum))))
+		}
+		if s.ExclusiveMaximum != nil && number >= *s.ExclusiveMaximum {
+			violations = append(violations, violation(path, fmt.Sprintf("must be less than %s", formatNumber(*s.ExclusiveMaximum))))
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			violations = append(violations, violation(path, fmt.Sprintf("must contain at least %d items", *s.MinItems)))
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			violations = append(violations, violation(path, fmt.Sprintf("must contain no more than %d items", *s.MaxItems)))
+		}
+		if s.Items != nil {
+			for i, item := range val {
+				violations = append(violations, v.validate(s.Items, item, childPath(path, strconv.Itoa(i)))...)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				violations = append(violations, violation(childPath(path, name), "is required"))
+			}
+		}
+		// Visit the properties in alphabetical order, to return the violations always in the same order
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			if property, ok := s.Properties[name]; ok {
+				violations = append(violations, v.validate(property, val[name], childPath(path, name))...)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				violations = append(violations, violation(childPath(path, name), "is not allowed"))
+			}
+		}
+	}
+	return violations
+}
+
+func matchesType(value any, t string) bool {
+	switch val := value.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		number, err := val.Float64()
+		return t == "integer" && err == nil && number == math.Trunc(number)
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	}
+	return false
+}
+
+/*
+Compare two JSON values, ignoring the different representations of numbers.
+*/
+func equalValues(a any, b any) bool {
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
+
+func childPath(path string, name string) string {
+	name = strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+	if path == "" {
+		return name
+	}
+	return path + "/" + name
+}
+
+func violation(path string, reason string) string {
+	if path == "" {
+		return reason
+	}
+	return path + " " + reason
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,140 @@
+package mm_provider
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_template"
+)
+
+/*
+Built-in schemas, one file per modality and provider named <modality>.<provider>.json
+*/
+//go:embed schemas/*.json
+var builtinSchemas embed.FS
+
+var ErrUnsupportedProvider = errors.New("unsupported-provider")
+
+/*
+Schema describes the parameters accepted by a provider for a modality, as a JSON Schema.
+*/
+type Schema struct {
+	Modality string          `json:"modality"`
+	Provider string          `json:"provider"`
+	Title    string          `json:"title"`
+	Schema   json.RawMessage `json:"schema"`
+}
+
+type registeredSchema struct {
+	schema   Schema
+	compiled *jsonSchema
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]registeredSchema{}
+)
+
+func init() {
+	files, err := builtinSchemas.ReadDir("schemas")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		modality, provider, ok := strings.Cut(strings.TrimSuffix(file.Name(), ".json"), ".")
+		if !ok {
+			panic(fmt.Sprintf("invalid schema file name %s", file.Name()))
+		}
+		data, err := builtinSchemas.ReadFile(path.Join("schemas", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		if err := Register(modality, provider, data); err != nil {
+			panic(fmt.Sprintf("invalid schema %s: %s", file.Name(), err.Error()))
+		}
+	}
+}
+
+/*
+Register the schema of the parameters accepted by a provider for a modality, replacing the previous one if any.
+*/
+func Register(modality string, provider string, schema json.RawMessage) error {
+	compiled, err := compileSchema(schema)
+	if err != nil {
+		return err
+	}
+	var annotations struct {
+		Title string `json:"title"`
+	}
+	json.Unmarshal(schema, &annotations)
+	mu.Lock()
+	defer mu.Unlock()
+	registry[key(modality, provider)] = registeredSchema{
+		schema: Schema{
+			Modality: modality,
+			Provider: provider,
+			Title:    annotations.Title,
+			Schema:   schema,
+		},
+		compiled: compiled,
+	}
+	return nil
+}
+
+/*
+Return all the registered schemas, sorted by modality and provider.
+*/
+func Schemas() []Schema {
+	mu.RLock()
+	defer mu.RUnlock()
+	schemas := make([]Schema, 0, len(registry))
+	for _, item := range registry {
+		schemas = append(schemas, item.schema)
+	}
+	slices.SortFunc(schemas, func(a Schema, b Schema) int {
+		return strings.Compare(key(a.Modality, a.Provider), key(b.Modality, b.Provider))
+	})
+	return schemas
+}
+
+/*
+Check if a schema is registered for the modality and the provider.
+*/
+func IsSupported(modality string, provider string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := registry[key(modality, provider)]
+	return ok
+}
+
+/*
+Validate the parameters against the schema of the modality and the provider, returning the violations found.
+Strings with placeholders are rendered only by the picker, so only their type is checked.
+*/
+func Validate(modality string, provider string, parameters json.RawMessage) ([]string, error) {
+	mu.RLock()
+	item, ok := registry[key(modality, provider)]
+	mu.RUnlock()
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(parameters))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	validator := schemaValidator{root: item.compiled, skipString: mm_template.HasPlaceholders}
+	return validator.validate(item.compiled, value, ""), nil
+}
+
+func key(modality string, provider string) string {
+	return modality + "/" + provider
+}
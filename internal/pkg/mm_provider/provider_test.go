@@ -0,0 +1,129 @@
+package mm_provider
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+/*
+Schema registered only by the tests, to not depend on the built-in ones.
+*/
+const testSchema = `{
+	"title": "Test Provider",
+	"type": "object",
+	"required": ["model"],
+	"additionalProperties": false,
+	"properties": {
+		"model": { "type": "string", "pattern": "^test-[a-z0-9]+$" },
+		"temperature": { "type": "number", "minimum": 0, "maximum": 2 },
+		"max_tokens": { "type": "integer", "minimum": 1 },
+		"stop": {
+			"anyOf": [
+				{ "type": "string" },
+				{ "type": "array", "maxItems": 2, "items": { "type": "string" } }
+			]
+		},
+		"messages": {
+			"type": "array",
+			"minItems": 1,
+			"items": { "$ref": "#/$defs/message" }
+		}
+	},
+	"$defs": {
+		"message": {
+			"type": "object",
+			"required": ["role"],
+			"properties": {
+				"role": { "enum": ["user", "assistant"] },
+				"content": { "type": "string", "maxLength": 10 }
+			}
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	if err := Register("test", "provider", json.RawMessage(testSchema)); err != nil {
+		t.Fatalf("failed to register the schema: %v", err)
+	}
+	tests := []struct {
+		name           string
+		parameters     string
+		wantViolations []string
+	}{
+		{
+			name:           "valid parameters",
+			parameters:     `{"model": "test-1", "temperature": 0.7, "max_tokens": 100, "stop": ["a", "b"], "messages": [{"role": "user", "content": "hello"}]}`,
+			wantViolations: []string{},
+		},
+		{
+			name:           "missing required parameter",
+			parameters:     `{"temperature": 1}`,
+			wantViolations: []string{"model is required"},
+		},
+		{
+			name:           "unknown parameter",
+			parameters:     `{"model": "test-1", "top_k": 3}`,
+			wantViolations: []string{"top_k is not allowed"},
+		},
+		{
+			name:       "constraints on values",
+			parameters: `{"model": "other", "temperature": 3, "max_tokens": 1.5}`,
+			wantViolations: []string{
+				"max_tokens must be of type integer",
+				"model must be in a valid format",
+				"temperature must be no greater than 2",
+			},
+		},
+		{
+			name:           "none of the allowed formats",
+			parameters:     `{"model": "test-1", "stop": ["a", "b", "c"]}`,
+			wantViolations: []string{"stop does not match any of the allowed formats"},
+		},
+		{
+			name:       "nested violations use the path of the value",
+			parameters: `{"model": "test-1", "messages": [{"role": "system", "content": "a very long content"}]}`,
+			wantViolations: []string{
+				"messages/0/content must be no more than 10 characters",
+				"messages/0/role must be one of user, assistant",
+			},
+		},
+		{
+			name:           "placeholders in strings are checked on their type only",
+			parameters:     `{"model": "<<model>>", "messages": [{"role": "<<role>>", "content": "<<a-long-placeholder>>"}]}`,
+			wantViolations: []string{},
+		},
+		{
+			name:           "placeholders where a string is not allowed",
+			parameters:     `{"model": "test-1", "temperature": "<<temperature>>"}`,
+			wantViolations: []string{"temperature cannot contain placeholders, since they are rendered as strings"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := Validate("test", "provider", json.RawMessage(tt.parameters))
+			if err != nil {
+				t.Fatalf("failed to validate the parameters: %v", err)
+			}
+			if !slices.Equal(violations, tt.wantViolations) {
+				t.Fatalf("unexpected violations: got %q, want %q", violations, tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestValidateUnsupportedProvider(t *testing.T) {
+	if _, err := Validate("test", "unknown", json.RawMessage(`{}`)); !errors.Is(err, ErrUnsupportedProvider) {
+		t.Fatalf("expected an unsupported provider error, got %v", err)
+	}
+}
+
+func TestValidateInvalidParameters(t *testing.T) {
+	if err := Register("test", "provider", json.RawMessage(testSchema)); err != nil {
+		t.Fatalf("failed to register the schema: %v", err)
+	}
+	if _, err := Validate("test", "provider", json.RawMessage(`{"model": `)); err == nil {
+		t.Fatalf("expected an error for invalid JSON")
+	}
+}
@@ -5,6 +5,7 @@ const (
 	RolloutStateWarmup          RolloutState = "WARMUP"
 	RolloutStateEscaped         RolloutState = "ESCAPED"
 	RolloutStateAdaptive        RolloutState = "ADAPTIVE"
+	RolloutStatePaused          RolloutState = "PAUSED"
 	RolloutStateCompleted       RolloutState = "COMPLETED"
 	RolloutStateForcedStop      RolloutState = "FORCED_STOP"
 	RolloutStateForcedEscaped   RolloutState = "FORCED_ESCAPED"
@@ -16,6 +17,7 @@ var AvailableRolloutState = []interface{}{
 	RolloutStateWarmup,
 	RolloutStateEscaped,
 	RolloutStateAdaptive,
+	RolloutStatePaused,
 	RolloutStateCompleted,
 	RolloutStateForcedStop,
 	RolloutStateForcedEscaped,
@@ -24,11 +26,104 @@ var AvailableRolloutState = []interface{}{
 
 var AllowedTransitions = map[RolloutState][]RolloutState{
 	RolloutStateInit:            {RolloutStateWarmup},
-	RolloutStateWarmup:          {RolloutStateForcedStop, RolloutStateForcedEscaped, RolloutStateForcedCompleted},
-	RolloutStateAdaptive:        {RolloutStateForcedStop, RolloutStateForcedEscaped, RolloutStateForcedCompleted},
+	RolloutStateWarmup:          {RolloutStatePaused, RolloutStateForcedStop, RolloutStateForcedEscaped, RolloutStateForcedCompleted},
+	RolloutStateAdaptive:        {RolloutStatePaused, RolloutStateForcedStop, RolloutStateForcedEscaped, RolloutStateForcedCompleted},
+	RolloutStatePaused:          {RolloutStateWarmup, RolloutStateAdaptive, RolloutStateForcedStop, RolloutStateForcedEscaped, RolloutStateForcedCompleted},
 	RolloutStateEscaped:         {RolloutStateInit},
 	RolloutStateCompleted:       {RolloutStateInit},
 	RolloutStateForcedStop:      {RolloutStateInit},
 	RolloutStateForcedEscaped:   {RolloutStateInit},
 	RolloutStateForcedCompleted: {RolloutStateInit},
 }
+
+const (
+	RsScoreAggregationWindow RsScoreAggregationMode = "WINDOW"
+	RsScoreAggregationDecay  RsScoreAggregationMode = "DECAY"
+)
+
+var AvailableRsScoreAggregationMode = []interface{}{
+	RsScoreAggregationWindow,
+	RsScoreAggregationDecay,
+}
+
+const (
+	// Composition of conditions
+	RsEscapeConditionAnd RsEscapeConditionType = "AND"
+	RsEscapeConditionOr  RsEscapeConditionType = "OR"
+	// Guardrails
+	RsEscapeConditionAvgScore            RsEscapeConditionType = "AVG_SCORE"
+	RsEscapeConditionBaselineDegradation RsEscapeConditionType = "BASELINE_DEGRADATION"
+	RsEscapeConditionOneStarShare        RsEscapeConditionType = "ONE_STAR_SHARE"
+	RsEscapeConditionScoreDrop           RsEscapeConditionType = "SCORE_DROP"
+	RsEscapeConditionErrorRate           RsEscapeConditionType = "ERROR_RATE"
+	RsEscapeConditionAbandonRate         RsEscapeConditionType = "ABANDON_RATE"
+)
+
+var AvailableRsEscapeConditionType = []interface{}{
+	RsEscapeConditionAnd,
+	RsEscapeConditionOr,
+	RsEscapeConditionAvgScore,
+	RsEscapeConditionBaselineDegradation,
+	RsEscapeConditionOneStarShare,
+	RsEscapeConditionScoreDrop,
+	RsEscapeConditionErrorRate,
+	RsEscapeConditionAbandonRate,
+}
+
+const (
+	SessionOutcomeError     SessionOutcome = "ERROR"
+	SessionOutcomeAbandoned SessionOutcome = "ABANDONED"
+)
+
+var AvailableSessionOutcome = []interface{}{
+	SessionOutcomeError,
+	SessionOutcomeAbandoned,
+}
+
+const (
+	RsWarmupCurveLinear      RsWarmupCurve = "LINEAR"
+	RsWarmupCurveExponential RsWarmupCurve = "EXPONENTIAL"
+	RsWarmupCurveStep        RsWarmupCurve = "STEP"
+	RsWarmupCurveCustom      RsWarmupCurve = "CUSTOM"
+)
+
+var AvailableRsWarmupCurve = []interface{}{
+	RsWarmupCurveLinear,
+	RsWarmupCurveExponential,
+	RsWarmupCurveStep,
+	RsWarmupCurveCustom,
+}
+
+const (
+	RsAdaptiveAlgorithmGreedy           RsAdaptiveAlgorithm = "GREEDY"
+	RsAdaptiveAlgorithmThompsonSampling RsAdaptiveAlgorithm = "THOMPSON_SAMPLING"
+	RsAdaptiveAlgorithmUCB1             RsAdaptiveAlgorithm = "UCB1"
+	RsAdaptiveAlgorithmEpsilonGreedy    RsAdaptiveAlgorithm = "EPSILON_GREEDY"
+)
+
+var AvailableRsAdaptiveAlgorithm = []interface{}{
+	RsAdaptiveAlgorithmGreedy,
+	RsAdaptiveAlgorithmThompsonSampling,
+	RsAdaptiveAlgorithmUCB1,
+	RsAdaptiveAlgorithmEpsilonGreedy,
+}
+
+const (
+	RsWeekdayMonday    RsWeekday = "MONDAY"
+	RsWeekdayTuesday   RsWeekday = "TUESDAY"
+	RsWeekdayWednesday RsWeekday = "WEDNESDAY"
+	RsWeekdayThursday  RsWeekday = "THURSDAY"
+	RsWeekdayFriday    RsWeekday = "FRIDAY"
+	RsWeekdaySaturday  RsWeekday = "SATURDAY"
+	RsWeekdaySunday    RsWeekday = "SUNDAY"
+)
+
+var AvailableRsWeekday = []interface{}{
+	RsWeekdayMonday,
+	RsWeekdayTuesday,
+	RsWeekdayWednesday,
+	RsWeekdayThursday,
+	RsWeekdayFriday,
+	RsWeekdaySaturday,
+	RsWeekdaySunday,
+}
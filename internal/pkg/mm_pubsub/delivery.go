@@ -0,0 +1,360 @@
+package mm_pubsub
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+deliveryStatus represents the status of the delivery of an event to a consumer group.
+*/
+type deliveryStatus string
+
+const (
+	deliveryStatusProcessing deliveryStatus = "PROCESSING"
+	deliveryStatusDone       deliveryStatus = "DONE"
+	deliveryStatusRetry      deliveryStatus = "RETRY"
+	deliveryStatusDead       deliveryStatus = "DEAD"
+)
+
+/*
+Delay before the first retry. It doubles at each attempt up to the max delay.
+*/
+const retryBaseDelay = 1 * time.Minute
+const retryMaxDelay = 1 * time.Hour
+
+/*
+Events committed in the last minute are not considered undelivered yet, to avoid racing with the live delivery.
+*/
+const undeliveredGracePeriod = 1 * time.Minute
+
+/*
+Deliveries still processing after this timeout are considered interrupted (e.g. the process died).
+Consumers record the processed events with MarkEventProcessed, so a delivery redelivered while still
+processing is not applied twice.
+*/
+const staleProcessingTimeout = 5 * time.Minute
+
+/*
+Max number of events redelivered per consumer group in a single run.
+*/
+const redeliveryBatchSize = 500
+
+/*
+deliveryContextKey stores in the context of a consumer the delivery of the event it is processing.
+*/
+type deliveryContextKey struct{}
+
+type eventDelivery struct {
+	eventID       uuid.UUID
+	consumerGroup string
+}
+
+/*
+Record that the event in the context of the transaction has been processed by its consumer group.
+Consumers call it in the same transaction of their changes, and skip the event when it returns false:
+the event was already processed, e.g. redelivered after a crash before its delivery was completed.
+Events not tracked (e.g. replayed or not persisted on DB) are always processed.
+*/
+func MarkEventProcessed(tx *gorm.DB) (bool, error) {
+	delivery, ok := tx.Statement.Context.Value(deliveryContextKey{}).(eventDelivery)
+	if !ok {
+		return true, nil
+	}
+	// A concurrent insert of the same delivery waits for the other transaction to complete
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&eventProcessedModel{
+		EventID:       delivery.eventID,
+		ConsumerGroup: delivery.consumerGroup,
+		ProcessedAt:   time.Now(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		zap.L().Info("Event already processed. Skip it", zap.String("service", "pub-sub"), zap.String("event-id", delivery.eventID.String()), zap.String("consumer-group", delivery.consumerGroup))
+		return false, nil
+	}
+	return true, nil
+}
+
+/*
+PendingEventEntityIDs returns the IDs of the entities of the events of a Use Case on the topic, committed since the
+given time and not processed yet by the consumer group: never delivered, still processing or waiting for a retry.
+Events moved to the dead-letter table will never be processed, so they are not pending.
+Only events persisted on DB are tracked.
+*/
+func PendingEventEntityIDs(tx *gorm.DB, pubsubTopic PubSubTopic, consumerGroup string, useCaseID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := tx.Model(&eventModel{}).
+		Where("topic = ? AND event_date >= ?", pubsubTopic, since).
+		Where("event_body->'eventEntity'->>'useCaseId' = ?", useCaseID.String()).
+		Where("NOT EXISTS (SELECT 1 FROM mm_event_processed p WHERE p.event_id = mm_event.id AND p.consumer_group = ?)", consumerGroup).
+		Where("NOT EXISTS (SELECT 1 FROM mm_event_claim c WHERE c.event_id = mm_event.id AND c.consumer_group = ? AND c.status IN ?)", consumerGroup, []deliveryStatus{deliveryStatusDone, deliveryStatusDead}).
+		Pluck("event_body->'eventEntity'->>'id'", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+/*
+Deliver a persisted message to all the local consumer groups subscribed to the topic,
+tracking the outcome for each of them to allow retries and redelivery.
+*/
+func (b *PubSubAgent) deliver(pubsubTopic PubSubTopic, msg PubSubMessage) {
+	claimedGroups := map[string]bool{}
+	for _, consumerGroup := range b.getConsumerGroups(pubsubTopic) {
+		claimed, err := b.claimEvent(msg.Message.EventID, consumerGroup)
+		if err != nil {
+			zap.L().Error("Impossible to claim the event", zap.String("service", "pub-sub"), zap.String("event-id", msg.Message.EventID.String()), zap.String("consumer-group", consumerGroup), zap.Error(err))
+			continue
+		}
+		if claimed {
+			claimedGroups[consumerGroup] = true
+		}
+	}
+	if len(claimedGroups) == 0 {
+		return
+	}
+	b.dispatch(pubsubTopic, msg, claimedGroups)
+}
+
+/*
+Send a message to the given consumer groups and store the outcome of the processing.
+*/
+func (b *PubSubAgent) dispatch(pubsubTopic PubSubTopic, msg PubSubMessage, consumerGroups map[string]bool) {
+	failures := b.publishMessageToConsumerGroups(pubsubTopic, msg, consumerGroups)
+	for consumerGroup := range consumerGroups {
+		if err := b.completeDelivery(pubsubTopic, msg, consumerGroup, failures[consumerGroup]); err != nil {
+			zap.L().Error("Impossible to store the delivery outcome", zap.String("service", "pub-sub"), zap.String("event-id", msg.Message.EventID.String()), zap.String("consumer-group", consumerGroup), zap.Error(err))
+		}
+	}
+}
+
+/*
+Claim an event for a consumer group. Only the first instance that claims it is allowed to consume it.
+*/
+func (b *PubSubAgent) claimEvent(eventID uuid.UUID, consumerGroup string) (bool, error) {
+	now := time.Now()
+	model := eventClaimModel{
+		EventID:       eventID,
+		ConsumerGroup: consumerGroup,
+		ClaimedBy:     b.instanceID,
+		ClaimedAt:     now,
+		Status:        deliveryStatusProcessing,
+		Attempts:      1,
+		UpdatedAt:     now,
+	}
+	result := b.storage.Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+/*
+Store the outcome of the processing of an event by a consumer group.
+Failed deliveries are scheduled for a retry with exponential backoff, until the max
+number of attempts is reached and the event is moved to the dead-letter table.
+*/
+func (b *PubSubAgent) completeDelivery(pubsubTopic PubSubTopic, msg PubSubMessage, consumerGroup string, processingErr error) error {
+	now := time.Now()
+	query := b.storage.Model(&eventClaimModel{}).Where("event_id = ? AND consumer_group = ?", msg.Message.EventID, consumerGroup)
+	if processingErr == nil {
+		return query.Updates(map[string]interface{}{
+			"status":          deliveryStatusDone,
+			"last_error":      nil,
+			"next_attempt_at": nil,
+			"updated_at":      now,
+		}).Error
+	}
+	var claim eventClaimModel
+	if err := query.Limit(1).Find(&claim).Error; err != nil {
+		return err
+	}
+	lastError := processingErr.Error()
+	zap.L().Error("Event processing failed", zap.String("service", "pub-sub"), zap.String("event-id", msg.Message.EventID.String()), zap.String("consumer-group", consumerGroup), zap.Int("attempts", claim.Attempts), zap.Error(processingErr))
+	if claim.Attempts < b.maxDeliveryAttempts {
+		nextAttemptAt := now.Add(retryDelay(claim.Attempts))
+		return query.Updates(map[string]interface{}{
+			"status":          deliveryStatusRetry,
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+			"updated_at":      now,
+		}).Error
+	}
+	// Max attempts reached: move the event in the dead-letter table
+	rawMessage, err := json.Marshal(msg.Message)
+	if err != nil {
+		return err
+	}
+	return b.storage.Transaction(func(tx *gorm.DB) error {
+		deadLetter := eventDeadLetterModel{
+			ID:            uuid.New(),
+			EventID:       msg.Message.EventID,
+			ConsumerGroup: consumerGroup,
+			Topic:         string(pubsubTopic),
+			EventType:     string(msg.Message.EventType),
+			EventBody:     rawMessage,
+			Attempts:      claim.Attempts,
+			LastError:     &lastError,
+			CreatedAt:     now,
+		}
+		if err := tx.Create(&deadLetter).Error; err != nil {
+			return err
+		}
+		zap.L().Warn("Event moved to dead-letter", zap.String("service", "pub-sub"), zap.String("event-id", msg.Message.EventID.String()), zap.String("consumer-group", consumerGroup))
+		return tx.Model(&eventClaimModel{}).Where("event_id = ? AND consumer_group = ?", msg.Message.EventID, consumerGroup).Updates(map[string]interface{}{
+			"status":          deliveryStatusDead,
+			"last_error":      lastError,
+			"next_attempt_at": nil,
+			"updated_at":      now,
+		}).Error
+	})
+}
+
+/*
+Calculate the delay before the next attempt, based on the number of attempts already performed.
+*/
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay = delay * 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+/*
+Redeliver all the events committed but not processed yet by the local consumer groups: events never
+delivered (e.g. the process died before publishing them), failed deliveries waiting for a retry and
+deliveries interrupted while processing.
+*/
+func (b *PubSubAgent) redeliverPendingEvents() error {
+	now := time.Now()
+	consumerGroups := []string{}
+	for _, pubsubTopic := range b.getSubscribedTopics() {
+		for _, consumerGroup := range b.getConsumerGroups(pubsubTopic) {
+			if err := b.redeliverUndeliveredEvents(pubsubTopic, consumerGroup, now.Add(-undeliveredGracePeriod)); err != nil {
+				return err
+			}
+			if !slices.Contains(consumerGroups, consumerGroup) {
+				consumerGroups = append(consumerGroups, consumerGroup)
+			}
+		}
+	}
+	for _, consumerGroup := range consumerGroups {
+		if err := b.redeliverFailedEvents(consumerGroup, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Deliver the events of a topic never delivered to the consumer group, starting from its checkpoint.
+The checkpoint is then moved forward, so older events are not checked anymore.
+*/
+func (b *PubSubAgent) redeliverUndeliveredEvents(pubsubTopic PubSubTopic, consumerGroup string, cutoff time.Time) error {
+	// Initialize the checkpoint for new consumer groups, so they will not receive historical events
+	checkpoint := eventCheckpointModel{
+		ConsumerGroup:  consumerGroup,
+		Topic:          string(pubsubTopic),
+		CheckpointDate: cutoff,
+		UpdatedAt:      time.Now(),
+	}
+	if err := b.storage.Clauses(clause.OnConflict{DoNothing: true}).Create(&checkpoint).Error; err != nil {
+		return err
+	}
+	if err := b.storage.Where("consumer_group = ? AND topic = ?", consumerGroup, pubsubTopic).Limit(1).Find(&checkpoint).Error; err != nil {
+		return err
+	}
+	var events []eventModel
+	if err := b.storage.
+		Where("topic = ? AND event_date >= ? AND event_date < ?", pubsubTopic, checkpoint.CheckpointDate, cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM mm_event_claim c WHERE c.event_id = mm_event.id AND c.consumer_group = ?)", consumerGroup).
+		Order("event_date ASC").
+		Limit(redeliveryBatchSize).
+		Find(&events).Error; err != nil {
+		return err
+	}
+	newCheckpointDate := cutoff
+	if len(events) == redeliveryBatchSize {
+		newCheckpointDate = events[len(events)-1].EventDate
+	}
+	for _, event := range events {
+		msg, err := decodeEventModel(event)
+		if err != nil {
+			zap.L().Error("Impossible to decode the event. Skip it", zap.String("service", "pub-sub"), zap.String("event-id", event.ID.String()), zap.Error(err))
+			continue
+		}
+		claimed, err := b.claimEvent(event.ID, consumerGroup)
+		if err != nil {
+			// Keep the checkpoint on this event to try again in the next run
+			newCheckpointDate = event.EventDate
+			break
+		}
+		if !claimed {
+			continue
+		}
+		zap.L().Info("Redelivering undelivered event", zap.String("service", "pub-sub"), zap.String("event-id", event.ID.String()), zap.String("consumer-group", consumerGroup))
+		b.dispatch(pubsubTopic, msg, map[string]bool{consumerGroup: true})
+	}
+	if !newCheckpointDate.After(checkpoint.CheckpointDate) {
+		return nil
+	}
+	return b.storage.Model(&eventCheckpointModel{}).
+		Where("consumer_group = ? AND topic = ?", consumerGroup, pubsubTopic).
+		Updates(map[string]interface{}{
+			"checkpoint_date": newCheckpointDate,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+/*
+Deliver again the events failed and waiting for a retry, and the ones interrupted while processing.
+*/
+func (b *PubSubAgent) redeliverFailedEvents(consumerGroup string, now time.Time) error {
+	var claims []eventClaimModel
+	if err := b.storage.
+		Where("consumer_group = ?", consumerGroup).
+		Where("((status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?))", deliveryStatusRetry, now, deliveryStatusProcessing, now.Add(-staleProcessingTimeout)).
+		Order("claimed_at ASC").
+		Limit(redeliveryBatchSize).
+		Find(&claims).Error; err != nil {
+		return err
+	}
+	for _, claim := range claims {
+		// Acquire the delivery, unless it has been changed in the meantime
+		result := b.storage.Model(&eventClaimModel{}).
+			Where("event_id = ? AND consumer_group = ? AND status = ? AND updated_at = ?", claim.EventID, consumerGroup, claim.Status, claim.UpdatedAt).
+			Updates(map[string]interface{}{
+				"status":     deliveryStatusProcessing,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"claimed_by": b.instanceID,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		var event eventModel
+		if err := b.storage.Where("id = ?", claim.EventID).Limit(1).Find(&event).Error; err != nil {
+			return err
+		}
+		msg, err := decodeEventModel(event)
+		if err != nil {
+			zap.L().Error("Impossible to decode the event. Skip it", zap.String("service", "pub-sub"), zap.String("event-id", claim.EventID.String()), zap.Error(err))
+			continue
+		}
+		zap.L().Info("Redelivering failed event", zap.String("service", "pub-sub"), zap.String("event-id", claim.EventID.String()), zap.String("consumer-group", consumerGroup), zap.Int("attempt", claim.Attempts+1))
+		b.dispatch(PubSubTopic(event.Topic), msg, map[string]bool{consumerGroup: true})
+	}
+	return nil
+}
@@ -18,14 +18,15 @@ type UseCaseEventEntity struct {
 }
 
 type UseCaseStepEventEntity struct {
-	ID          uuid.UUID `json:"id"`
-	UseCaseID   uuid.UUID `json:"useCaseId"`
-	Title       string    `json:"title"`
-	Code        string    `json:"code"`
-	Description string    `json:"description"`
-	Position    *int64    `json:"position"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uuid.UUID       `json:"id"`
+	UseCaseID   uuid.UUID       `json:"useCaseId"`
+	Title       string          `json:"title"`
+	Code        string          `json:"code"`
+	Description string          `json:"description"`
+	Position    *int64          `json:"position"`
+	Variables   json.RawMessage `json:"variables"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
 }
 
 type FlowEventEntity struct {
@@ -48,6 +49,9 @@ type FlowStatisticsEventEntity struct {
 	TotSessionRequests int64     `json:"totSessionRequests"`
 	TotFeedback        int64     `json:"totFeedback"`
 	AvgScore           float64   `json:"avgScore"`
+	TotOneStarFeedback int64     `json:"totOneStarFeedback"`
+	TotErrors          int64     `json:"totErrors"`
+	TotAbandons        int64     `json:"totAbandons"`
 	CreatedAt          time.Time `json:"createdAt"`
 	UpdatedAt          time.Time `json:"updatedAt"`
 }
@@ -59,6 +63,7 @@ type FlowStepEventEntity struct {
 	UseCaseStepID uuid.UUID       `json:"useCaseStepId"`
 	Configuration json.RawMessage `json:"configuration"`
 	Placeholders  json.RawMessage `json:"placeholders"`
+	Version       int64           `json:"version"`
 	CreatedAt     time.Time       `json:"createdAt"`
 	UpdatedAt     time.Time       `json:"updatedAt"`
 }
@@ -75,20 +80,53 @@ type RolloutStrategyEventEntity struct {
 }
 
 type RSConfiguration struct {
+	ScheduledStartAt    *time.Time          `json:"scheduledStartAt"`
 	Warmup              *RsWarmupPhase      `json:"warmup"`
 	Escape              *RsEscapePhase      `json:"escape"`
 	Adaptive            RsAdaptivePhase     `json:"adaptive"`
+	ScoreAggregation    *RsScoreAggregation `json:"scoreAggregation"`
 	StateConfigurations StateConfigurations `json:"stateConfigs"`
 }
 
+type RsScoreAggregationMode string
+
+/*
+How feedback scores are aggregated for the Adaptive phase and the Escape rules. Without it, the average
+score since the start of the Rollout Strategy is used.
+*/
+type RsScoreAggregation struct {
+	Mode          RsScoreAggregationMode `json:"mode"`
+	WindowHours   *int64                 `json:"windowHours"`
+	HalfLifeHours *float64               `json:"halfLifeHours"`
+}
+
 type StateConfigurations struct {
-	CompletedFlowID *uuid.UUID `json:"completedFlowId"`
+	CompletedFlowID *uuid.UUID    `json:"completedFlowId"`
+	PausedFromState *RolloutState `json:"pausedFromState"`
+	PausedAt        *time.Time    `json:"pausedAt"`
+	// Start of the current phase, when it has been resumed after a pause (shifted by the paused duration)
+	PhaseStartedAt *time.Time `json:"phaseStartedAt"`
 }
 
+type RsWarmupCurve string
+
 type RsWarmupPhase struct {
-	IntervalMins     *int64       `json:"intervalMins"`
-	IntervalSessReqs *int64       `json:"intervalSessReqs"`
-	Goals            []RsFlowGoal `json:"goals"`
+	IntervalMins     *int64               `json:"intervalMins"`
+	IntervalSessReqs *int64               `json:"intervalSessReqs"`
+	Goals            []RsFlowGoal         `json:"goals"`
+	Curve            RsWarmupCurve        `json:"curve"`
+	Doublings        *int64               `json:"doublings"`
+	Steps            *int64               `json:"steps"`
+	Checkpoints      []RsWarmupCheckpoint `json:"checkpoints"`
+}
+
+/*
+Point of a custom Warmup curve: once the offset (in minutes or session requests, as the Warmup interval)
+is reached, Flows are at the given PCT of the way toward their goals.
+*/
+type RsWarmupCheckpoint struct {
+	Offset      int64   `json:"offset"`
+	ProgressPct float64 `json:"progressPct"`
 }
 
 type RsFlowGoal struct {
@@ -101,10 +139,26 @@ type RsEscapePhase struct {
 }
 
 type RsEscapeRule struct {
-	FlowID      uuid.UUID          `json:"flowId"`
-	MinFeedback int64              `json:"minFeedback"`
-	LowerScore  float64            `json:"lowerScore"`
-	Rollback    []RsEscapeRollback `json:"rollback"`
+	FlowID             uuid.UUID          `json:"flowId"`
+	MinFeedback        int64              `json:"minFeedback"`
+	MinSessionRequests int64              `json:"minSessionRequests"`
+	LowerScore         float64            `json:"lowerScore"`
+	Condition          *RsEscapeCondition `json:"condition"`
+	Rollback           []RsEscapeRollback `json:"rollback"`
+}
+
+type RsEscapeConditionType string
+
+/*
+Condition of an Escape rule. AND and OR compose the nested conditions, while all other types are
+guardrails evaluated on the statistics of the Flow of the rule.
+*/
+type RsEscapeCondition struct {
+	Type           RsEscapeConditionType `json:"type"`
+	Conditions     []RsEscapeCondition   `json:"conditions"`
+	Threshold      *float64              `json:"threshold"`
+	BaselineFlowID *uuid.UUID            `json:"baselineFlowId"`
+	WindowMins     *int64                `json:"windowMins"`
 }
 
 type RsEscapeRollback struct {
@@ -112,10 +166,31 @@ type RsEscapeRollback struct {
 	FinalServePct float64   `json:"finalServePct"`
 }
 
+type RsAdaptiveAlgorithm string
+
 type RsAdaptivePhase struct {
-	MinFeedback  int64   `json:"minFeedback"`
-	MaxStepPct   float64 `json:"maxStepPct"`
-	IntervalMins int64   `json:"intervalMins"`
+	Algorithm          RsAdaptiveAlgorithm `json:"algorithm"`
+	MinFeedback        int64               `json:"minFeedback"`
+	MaxStepPct         float64             `json:"maxStepPct"`
+	IntervalMins       int64               `json:"intervalMins"`
+	MinBestProbability *float64            `json:"minBestProbability"`
+	ExplorationFactor  *float64            `json:"explorationFactor"`
+	Epsilon            *float64            `json:"epsilon"`
+	MinConfidence      *float64            `json:"minConfidence"`
+	ActiveWindows      *RsActiveWindows    `json:"activeWindows"`
+}
+
+type RsWeekday string
+
+type RsActiveWindows struct {
+	Timezone string         `json:"timezone"`
+	Windows  []RsTimeWindow `json:"windows"`
+}
+
+type RsTimeWindow struct {
+	Days []RsWeekday `json:"days"`
+	From string      `json:"from"`
+	To   string      `json:"to"`
 }
 
 type PickerEventEntity struct {
@@ -124,6 +199,7 @@ type PickerEventEntity struct {
 	UseCaseStepID      uuid.UUID       `json:"useCaseStepId"`
 	FlowID             uuid.UUID       `json:"flowId"`
 	FlowStepID         uuid.UUID       `json:"flowStepId"`
+	FlowStepVersion    *int64          `json:"flowStepVersion"`
 	CorrelationID      uuid.UUID       `json:"correlationId"`
 	IsFirstCorrelation *bool           `json:"isFirstCorrelation"`
 	InputMessage       json.RawMessage `json:"inputMessage"`
@@ -142,12 +218,57 @@ type FeedbackEventEntity struct {
 	CreatedAt     time.Time `json:"createdAt"`
 }
 
+type SessionOutcome string
+
+type SessionOutcomeEventEntity struct {
+	ID            uuid.UUID      `json:"id"`
+	UseCaseID     uuid.UUID      `json:"useCaseId"`
+	FlowID        uuid.UUID      `json:"flowId"`
+	CorrelationID uuid.UUID      `json:"correlationId"`
+	Outcome       SessionOutcome `json:"outcome"`
+	CreatedAt     time.Time      `json:"createdAt"`
+}
+
 type RsEngineEventEntity struct {
-	ID           uuid.UUID                 `json:"id"`
-	UseCaseID    uuid.UUID                 `json:"useCaseId"`
-	RolloutID    uuid.UUID                 `json:"rolloutId"`
-	RolloutState RolloutState              `json:"rolloutState"`
-	Flows        []RsEngineFlowEventEntity `json:"flows"`
+	ID            uuid.UUID                 `json:"id"`
+	UseCaseID     uuid.UUID                 `json:"useCaseId"`
+	RolloutID     uuid.UUID                 `json:"rolloutId"`
+	FromState     RolloutState              `json:"fromState"`
+	RolloutState  RolloutState              `json:"rolloutState"`
+	Flows         []RsEngineFlowEventEntity `json:"flows"`
+	EscapeTrigger *RsEscapeTrigger          `json:"escapeTrigger"`
+}
+
+/*
+Check if the allocation computed by the engine can still be applied to a Rollout Strategy in the given state:
+the engine evaluated it in the state the event comes from, and the Rollout Strategy could already be moved
+to the state of the event. Paused Rollout Strategies keep their allocation.
+*/
+func (e RsEngineEventEntity) AppliesTo(state RolloutState) bool {
+	if state == RolloutStatePaused {
+		return false
+	}
+	// Events sent before the state of origin was tracked
+	if e.FromState == "" {
+		return true
+	}
+	return state == e.FromState || state == e.RolloutState
+}
+
+/*
+Escape rule that fired, with the guardrails that matched and the values observed on the Flow
+*/
+type RsEscapeTrigger struct {
+	FlowID     uuid.UUID                  `json:"flowId"`
+	Guardrails []RsEscapeTriggerGuardrail `json:"guardrails"`
+}
+
+type RsEscapeTriggerGuardrail struct {
+	Type           RsEscapeConditionType `json:"type"`
+	Threshold      float64               `json:"threshold"`
+	Value          float64               `json:"value"`
+	BaselineFlowID *uuid.UUID            `json:"baselineFlowId"`
+	WindowMins     *int64                `json:"windowMins"`
 }
 
 type RsEngineFlowEventEntity struct {
@@ -1,10 +1,14 @@
 package mm_pubsub
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/ai-model-match/backend/internal/pkg/mm_tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 /*
@@ -37,6 +41,7 @@ const (
 	RolloutStrategyUpdatedEvent PubSubEventType = "rollout-strategy.updated"
 	PickerMatchedEvent          PubSubEventType = "picker.matched"
 	FeedbackCreatedEvent        PubSubEventType = "feedback.created"
+	SessionOutcomeCreatedEvent  PubSubEventType = "session-outcome.created"
 	RsEngineUpdatedEvent        PubSubEventType = "rs-engine.updated"
 )
 
@@ -63,6 +68,7 @@ var eventEntityFactories = map[PubSubEventType]func() any{
 	RolloutStrategyUpdatedEvent: func() interface{} { return &RolloutStrategyEventEntity{} },
 	PickerMatchedEvent:          func() interface{} { return &PickerEventEntity{} },
 	FeedbackCreatedEvent:        func() interface{} { return &FeedbackEventEntity{} },
+	SessionOutcomeCreatedEvent:  func() interface{} { return &SessionOutcomeEventEntity{} },
 	RsEngineUpdatedEvent:        func() interface{} { return &RsEngineEventEntity{} },
 }
 
@@ -76,5 +82,69 @@ type PubSubEvent struct {
 	EventType          PubSubEventType `json:"eventType"`
 	EventEntity        interface{}     `json:"eventEntity"`
 	EventChangedFields []string        `json:"eventChangedFields"`
-	EventState         *sync.WaitGroup `json:"-"`
+	TraceParent        string          `json:"traceParent,omitempty"`
+	EventState         *EventState     `json:"-"`
+}
+
+/*
+Return a new context that continues the trace of the event, to be used by consumers
+to trace their processing and link the events they generate.
+For tracked deliveries, it also identifies the consumer group, so the processing can be recorded with MarkEventProcessed.
+*/
+func (e PubSubEvent) Context() context.Context {
+	ctx := mm_tracing.ContextWithTraceParent(context.Background(), e.TraceParent)
+	if e.EventState != nil && e.EventState.tracked {
+		ctx = context.WithValue(ctx, deliveryContextKey{}, eventDelivery{eventID: e.EventID, consumerGroup: e.EventState.consumerGroup})
+	}
+	return ctx
+}
+
+/*
+EventState tracks the processing of an event by a single subscriber.
+The subscriber must always call Done (generally deferred) to ACK the message, and
+Fail before it to report that the processing did not succeed and needs to be retried.
+*/
+type EventState struct {
+	wg            *sync.WaitGroup
+	err           error
+	topic         string
+	consumerGroup string
+	tracked       bool
+	dispatchedAt  time.Time
+	span          trace.Span
+}
+
+/*
+Complete the processing of the event. When deferred, it also marks as failed the
+processing interrupted by a panic, then propagates the panic to the caller.
+*/
+func (s *EventState) Done() {
+	if r := recover(); r != nil {
+		s.err = fmt.Errorf("panic: %v", r)
+		s.observe()
+		s.wg.Done()
+		panic(r)
+	}
+	s.observe()
+	s.wg.Done()
+}
+
+/*
+Track the time spent by the subscriber to process the event and complete its span.
+*/
+func (s *EventState) observe() {
+	outcome := consumeOutcomeOk
+	if s.err != nil {
+		outcome = consumeOutcomeFailed
+	}
+	consumeDuration.WithLabelValues(s.topic, s.consumerGroup, outcome).Observe(time.Since(s.dispatchedAt).Seconds())
+	mm_tracing.RecordError(s.span, s.err)
+	s.span.End()
+}
+
+/*
+Mark the processing of the event as failed.
+*/
+func (s *EventState) Fail(err error) {
+	s.err = err
 }
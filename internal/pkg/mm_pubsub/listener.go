@@ -0,0 +1,133 @@
+package mm_pubsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_log"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+Postgres channel used to announce committed events to all the application instances
+*/
+const notifyChannel = "mm_event_channel"
+
+/*
+Time to wait before trying to listen again after a connection failure
+*/
+const listenerRetryDelay = 5 * time.Second
+
+/*
+pubsubListener listens on the Postgres notification channel for events committed by any
+application instance and forwards them to the local subscribers, ensuring each event is
+delivered once per consumer group across all the instances.
+*/
+type pubsubListener struct {
+	storage *gorm.DB
+	agent   *PubSubAgent
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func newPubsubListener(storage *gorm.DB, agent *PubSubAgent) pubsubListener {
+	ctx, cancel := context.WithCancel(context.Background())
+	return pubsubListener{
+		storage: storage,
+		agent:   agent,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+/*
+Start listening for notifications in background. If the connection drops, it retries until the listener is closed.
+*/
+func (l pubsubListener) init() {
+	go func() {
+		for l.ctx.Err() == nil {
+			if err := l.listen(); err != nil && l.ctx.Err() == nil {
+				zap.L().Error("Listener connection failed. Retry soon...", zap.String("service", "pub-sub"), zap.Error(err))
+				select {
+				case <-l.ctx.Done():
+				case <-time.After(listenerRetryDelay):
+				}
+			}
+		}
+		zap.L().Info("Listener stopped!", zap.String("service", "pub-sub"))
+	}()
+}
+
+/*
+Stop listening for notifications
+*/
+func (l pubsubListener) close() {
+	l.cancel()
+}
+
+/*
+Open a dedicated connection to DB and wait for notifications until the connection fails or the listener is closed.
+*/
+func (l pubsubListener) listen() error {
+	sqlDB, err := l.storage.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(l.ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		if _, err := pgxConn.Exec(l.ctx, fmt.Sprintf("LISTEN %s", pgx.Identifier{notifyChannel}.Sanitize())); err != nil {
+			return err
+		}
+		zap.L().Info(fmt.Sprintf("Listening on channel %s", notifyChannel), zap.String("service", "pub-sub"))
+		for {
+			notification, err := pgxConn.WaitForNotification(l.ctx)
+			if err != nil {
+				return err
+			}
+			l.onNotification(notification.Payload)
+		}
+	})
+}
+
+/*
+Load the notified event and deliver it to all local consumer groups that are able to claim it.
+*/
+func (l pubsubListener) onNotification(payload string) {
+	defer func() {
+		if r := recover(); r != nil {
+			mm_log.LogPanicError(r, "pub-sub", "Panic occurred in handling a notification")
+		}
+	}()
+	eventID, err := uuid.Parse(payload)
+	if err != nil {
+		zap.L().Error("Invalid notification payload", zap.String("service", "pub-sub"), zap.String("payload", payload), zap.Error(err))
+		return
+	}
+	var model eventModel
+	result := l.storage.Where("id = ?", eventID).Limit(1).Find(&model)
+	if result.Error != nil {
+		zap.L().Error("Impossible to load the notified event", zap.String("service", "pub-sub"), zap.String("event-id", payload), zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		zap.L().Info("Notified event not found. Skip it", zap.String("service", "pub-sub"), zap.String("event-id", payload))
+		return
+	}
+	msg, err := decodeEventModel(model)
+	if err != nil {
+		zap.L().Error("Impossible to decode the notified event", zap.String("service", "pub-sub"), zap.String("event-id", payload), zap.Error(err))
+		return
+	}
+	// Claim the event for each consumer group subscribed on this instance and deliver it
+	l.agent.deliver(PubSubTopic(model.Topic), msg)
+}
@@ -0,0 +1,28 @@
+package mm_pubsub
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	consumeOutcomeOk     = "ok"
+	consumeOutcomeFailed = "failed"
+)
+
+var publishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "mm_pubsub_publish_duration_seconds",
+	Help:    "Time to dispatch an event to all the subscribers of a topic, until they acknowledge it",
+	Buckets: prometheus.DefBuckets,
+}, []string{"topic"})
+
+var consumeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "mm_pubsub_consume_duration_seconds",
+	Help:    "Time spent by a consumer group to process an event",
+	Buckets: prometheus.DefBuckets,
+}, []string{"topic", "consumer_group", "outcome"})
+
+var queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "mm_pubsub_queue_depth",
+	Help: "Events of a topic waiting to be dispatched or still processed by its subscribers",
+}, []string{"topic"})
@@ -18,3 +18,56 @@ type eventModel struct {
 func (m eventModel) TableName() string {
 	return "mm_event"
 }
+
+type eventClaimModel struct {
+	EventID       uuid.UUID      `gorm:"primaryKey;column:event_id;type:varchar(36)"`
+	ConsumerGroup string         `gorm:"primaryKey;column:consumer_group;type:varchar(255)"`
+	ClaimedBy     string         `gorm:"column:claimed_by;type:varchar(255)"`
+	ClaimedAt     time.Time      `gorm:"column:claimed_at;type:timestamp"`
+	Status        deliveryStatus `gorm:"column:status;type:varchar(32)"`
+	Attempts      int            `gorm:"column:attempts;type:integer"`
+	LastError     *string        `gorm:"column:last_error;type:text"`
+	NextAttemptAt *time.Time     `gorm:"column:next_attempt_at;type:timestamp"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;type:timestamp"`
+}
+
+func (m eventClaimModel) TableName() string {
+	return "mm_event_claim"
+}
+
+type eventCheckpointModel struct {
+	ConsumerGroup  string    `gorm:"primaryKey;column:consumer_group;type:varchar(255)"`
+	Topic          string    `gorm:"primaryKey;column:topic;type:varchar(255)"`
+	CheckpointDate time.Time `gorm:"column:checkpoint_date;type:timestamp"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;type:timestamp"`
+}
+
+func (m eventCheckpointModel) TableName() string {
+	return "mm_event_checkpoint"
+}
+
+type eventDeadLetterModel struct {
+	ID            uuid.UUID       `gorm:"primaryKey;column:id;type:varchar(36)"`
+	EventID       uuid.UUID       `gorm:"column:event_id;type:varchar(36)"`
+	ConsumerGroup string          `gorm:"column:consumer_group;type:varchar(255)"`
+	Topic         string          `gorm:"column:topic;type:varchar(255)"`
+	EventType     string          `gorm:"column:event_type;type:varchar(255)"`
+	EventBody     json.RawMessage `gorm:"column:event_body;type:json"`
+	Attempts      int             `gorm:"column:attempts;type:integer"`
+	LastError     *string         `gorm:"column:last_error;type:text"`
+	CreatedAt     time.Time       `gorm:"column:created_at;type:timestamp"`
+}
+
+func (m eventDeadLetterModel) TableName() string {
+	return "mm_event_dead_letter"
+}
+
+type eventProcessedModel struct {
+	EventID       uuid.UUID `gorm:"primaryKey;column:event_id;type:varchar(36)"`
+	ConsumerGroup string    `gorm:"primaryKey;column:consumer_group;type:varchar(255)"`
+	ProcessedAt   time.Time `gorm:"column:processed_at;type:timestamp"`
+}
+
+func (m eventProcessedModel) TableName() string {
+	return "mm_event_processed"
+}
@@ -1,12 +1,19 @@
 package mm_pubsub
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/ai-model-match/backend/internal/pkg/mm_scheduler"
+	"github.com/ai-model-match/backend/internal/pkg/mm_tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -23,13 +30,26 @@ type PubSubMessage struct {
 PubSubAgent is a pub-sub agent that orchestrates channels to forward messages from producers to consumers.
 */
 type PubSubAgent struct {
-	mu                sync.Mutex
-	subs              map[string][]chan PubSubMessage
-	quit              chan struct{}
-	closed            bool
-	persistEventsOnDb bool
-	pubSubScheduler   *pubsubScheduler
-	syncMode          bool
+	mu                  sync.Mutex
+	subs                map[string][]subscriber
+	quit                chan struct{}
+	closed              bool
+	persistEventsOnDb   bool
+	pubSubScheduler     *pubsubScheduler
+	syncMode            bool
+	distributedMode     bool
+	pubSubListener      *pubsubListener
+	storage             *gorm.DB
+	instanceID          string
+	maxDeliveryAttempts int
+}
+
+/*
+subscriber represents a channel opened by a consumer group on a topic.
+*/
+type subscriber struct {
+	consumerGroup string
+	ch            chan PubSubMessage
 }
 
 type EventToPublish struct {
@@ -39,21 +59,37 @@ type EventToPublish struct {
 
 /*
 NewPubSubAgent initialies a new pub-sub Agent.
+When events are persisted on DB, the processing of each consumer group is tracked: failed events
+are retried with backoff up to the max delivery attempts, then moved to the dead-letter table.
+In distributed mode, events are announced to all the application instances via Postgres LISTEN/NOTIFY
+once committed, and each event is consumed once per consumer group across the cluster.
 */
-func NewPubSubAgent(dbStorage *gorm.DB, scheduler *mm_scheduler.Scheduler, persistEventsOnDb bool, persistRetentionDays int, syncMode bool) *PubSubAgent {
+func NewPubSubAgent(dbStorage *gorm.DB, scheduler *mm_scheduler.Scheduler, persistEventsOnDb bool, persistRetentionDays int, syncMode bool, distributedMode bool, maxDeliveryAttempts int) *PubSubAgent {
 	zap.L().Info("Start creating PubSub agent...", zap.String("service", "pub-sub"))
-	var pubSubScheduler *pubsubScheduler = nil
-	if persistEventsOnDb && persistRetentionDays > 0 {
-		ps := newPubsubScheduler(dbStorage, scheduler, persistRetentionDays)
-		pubSubScheduler = &ps
-		pubSubScheduler.init()
+	if distributedMode && !persistEventsOnDb {
+		zap.L().Error("Distributed mode requires events to be persisted on DB", zap.String("service", "pub-sub"))
+		panic("Distributed mode requires events to be persisted on DB")
 	}
+	hostname, _ := os.Hostname()
 	pubsub := &PubSubAgent{
-		subs:              make(map[string][]chan PubSubMessage),
-		quit:              make(chan struct{}),
-		persistEventsOnDb: persistEventsOnDb,
-		syncMode:          syncMode,
-		pubSubScheduler:   pubSubScheduler,
+		subs:                make(map[string][]subscriber),
+		quit:                make(chan struct{}),
+		persistEventsOnDb:   persistEventsOnDb,
+		syncMode:            syncMode,
+		distributedMode:     distributedMode,
+		storage:             dbStorage,
+		instanceID:          fmt.Sprintf("%s-%s", hostname, uuid.New().String()),
+		maxDeliveryAttempts: maxDeliveryAttempts,
+	}
+	if persistEventsOnDb {
+		ps := newPubsubScheduler(dbStorage, scheduler, pubsub, persistRetentionDays)
+		pubsub.pubSubScheduler = &ps
+		pubsub.pubSubScheduler.init()
+	}
+	if distributedMode {
+		listener := newPubsubListener(dbStorage, pubsub)
+		pubsub.pubSubListener = &listener
+		pubsub.pubSubListener.init()
 	}
 	zap.L().Info("PubSub agent created!", zap.String("service", "pub-sub"))
 	return pubsub
@@ -63,6 +99,17 @@ func NewPubSubAgent(dbStorage *gorm.DB, scheduler *mm_scheduler.Scheduler, persi
 Persist a message and its topic on DB
 */
 func (b *PubSubAgent) Persist(tx *gorm.DB, pubsubTopic PubSubTopic, msg PubSubMessage) (EventToPublish, error) {
+	ctx, span := mm_tracing.Start(tx.Statement.Context, fmt.Sprintf("pubsub.persist %s", pubsubTopic), trace.SpanKindProducer,
+		attribute.String("messaging.destination.name", string(pubsubTopic)),
+		attribute.String("messaging.message.id", msg.Message.EventID.String()),
+		attribute.String("event.type", string(msg.Message.EventType)),
+	)
+	defer span.End()
+	tx = tx.WithContext(ctx)
+	// Consumers continue the trace of the operation that generated the event
+	if msg.Message.TraceParent == "" {
+		msg.Message.TraceParent = mm_tracing.TraceParent(ctx)
+	}
 	// Skip store events based on configuration
 	if !b.persistEventsOnDb {
 		return EventToPublish{
@@ -82,8 +129,15 @@ func (b *PubSubAgent) Persist(tx *gorm.DB, pubsubTopic PubSubTopic, msg PubSubMe
 		EventBody: rawMessage,
 	}
 	if err := tx.Create(model).Error; err != nil {
+		mm_tracing.RecordError(span, err)
 		return EventToPublish{}, err
 	}
+	// Announce the event to all the instances. Notifications are sent only when the transaction commits
+	if b.distributedMode {
+		if err := tx.Exec("SELECT pg_notify(?, ?)", notifyChannel, model.ID.String()).Error; err != nil {
+			return EventToPublish{}, err
+		}
+	}
 	return EventToPublish{
 		pubsubTopic: pubsubTopic,
 		msg:         msg,
@@ -92,12 +146,21 @@ func (b *PubSubAgent) Persist(tx *gorm.DB, pubsubTopic PubSubTopic, msg PubSubMe
 
 /*
 Publish a message to a specific topic. The message will be deliver to all the active channels.
+In distributed mode the delivery is triggered by the DB notification, so there is nothing to do here.
 */
 func (b *PubSubAgent) Publish(event EventToPublish) error {
+	if b.distributedMode {
+		return nil
+	}
+	// Persisted events are tracked to allow retries and redelivery
+	publish := b.publishMessageToTopic
+	if b.persistEventsOnDb {
+		publish = b.deliver
+	}
 	if b.syncMode {
-		b.publishMessageToTopic(event.pubsubTopic, event.msg)
+		publish(event.pubsubTopic, event.msg)
 	} else {
-		go b.publishMessageToTopic(event.pubsubTopic, event.msg)
+		go publish(event.pubsubTopic, event.msg)
 	}
 	return nil
 }
@@ -116,7 +179,24 @@ func (b *PubSubAgent) PublishBulk(events []EventToPublish) error {
 Publish a message to a specific topic. The message will be sent to all the active channels.
 */
 func (b *PubSubAgent) publishMessageToTopic(pubsubTopic PubSubTopic, msg PubSubMessage) {
+	b.publishMessageToConsumerGroups(pubsubTopic, msg, nil)
+}
+
+/*
+Publish a message to a specific topic, limited to the given consumer groups (all of them if nil).
+It returns the processing errors reported by each consumer group.
+*/
+func (b *PubSubAgent) publishMessageToConsumerGroups(pubsubTopic PubSubTopic, msg PubSubMessage, consumerGroups map[string]bool) map[string]error {
 	topic := string(pubsubTopic)
+	dispatchedAt := time.Now()
+	ctx, span := mm_tracing.Start(mm_tracing.ContextWithTraceParent(context.Background(), msg.Message.TraceParent), fmt.Sprintf("pubsub.dispatch %s", topic), trace.SpanKindInternal,
+		attribute.String("messaging.destination.name", topic),
+		attribute.String("messaging.message.id", msg.Message.EventID.String()),
+		attribute.String("event.type", string(msg.Message.EventType)),
+	)
+	defer span.End()
+	queueDepth.WithLabelValues(topic).Inc()
+	defer queueDepth.WithLabelValues(topic).Dec()
 	zap.L().Info(
 		fmt.Sprintf("Dispatching %s event on Topic %s", msg.Message.EventType, topic),
 		zap.String("service", "pub-sub"),
@@ -126,19 +206,63 @@ func (b *PubSubAgent) publishMessageToTopic(pubsubTopic PubSubTopic, msg PubSubM
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	failures := map[string]error{}
 	if b.closed {
-		return
+		return failures
+	}
+	// Select the subscribers to notify
+	subs := []subscriber{}
+	for _, sub := range b.subs[topic] {
+		if consumerGroups == nil || consumerGroups[sub.consumerGroup] {
+			subs = append(subs, sub)
+		}
 	}
-	// Set Waiting status in Event
+	// Set Waiting status in Event, one for each subscriber
 	var wg sync.WaitGroup
-	wg.Add(len(b.subs[topic]))
-	msg.Message.EventState = &wg
+	wg.Add(len(subs))
+	states := make([]*EventState, len(subs))
 	// Send the message to all the subscribers
-	defer wg.Wait()
-	for _, ch := range b.subs[topic] {
-		ch <- msg
+	for i, sub := range subs {
+		consumeCtx, consumeSpan := mm_tracing.Start(ctx, fmt.Sprintf("pubsub.consume %s %s", topic, sub.consumerGroup), trace.SpanKindConsumer,
+			attribute.String("messaging.destination.name", topic),
+			attribute.String("messaging.consumer.group.name", sub.consumerGroup),
+			attribute.String("messaging.message.id", msg.Message.EventID.String()),
+			attribute.String("event.type", string(msg.Message.EventType)),
+		)
+		states[i] = &EventState{wg: &wg, topic: topic, consumerGroup: sub.consumerGroup, tracked: consumerGroups != nil, dispatchedAt: time.Now(), span: consumeSpan}
+		subMsg := msg
+		subMsg.Message.EventState = states[i]
+		// The consumer continues the trace from its own span
+		if traceParent := mm_tracing.TraceParent(consumeCtx); traceParent != "" {
+			subMsg.Message.TraceParent = traceParent
+		}
+		sub.ch <- subMsg
+	}
+	wg.Wait()
+	publishDuration.WithLabelValues(topic).Observe(time.Since(dispatchedAt).Seconds())
+	// Collect the outcome of each subscriber
+	for i, sub := range subs {
+		if states[i].err != nil {
+			failures[sub.consumerGroup] = states[i].err
+		}
 	}
+	return failures
+}
 
+/*
+Return the distinct consumer groups subscribed locally to a specific topic.
+*/
+func (b *PubSubAgent) getConsumerGroups(pubsubTopic PubSubTopic) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	consumerGroups := []string{}
+	for _, sub := range b.subs[string(pubsubTopic)] {
+		if !slices.Contains(consumerGroups, sub.consumerGroup) {
+			consumerGroups = append(consumerGroups, sub.consumerGroup)
+		}
+	}
+	return consumerGroups
 }
 
 /*
@@ -166,51 +290,81 @@ func (b *PubSubAgent) ReplayMessages(tx *gorm.DB, topicName *PubSubTopic, startF
 		if err := tx.ScanRows(rows, &model); err != nil {
 			return err
 		}
-		// Unmarhal the stored event
-		var body PubSubEvent
-		if err := json.Unmarshal(model.EventBody, &body); err != nil {
-			return err
-		}
-		// Convert EventEntity to raw bytes for further unmarshaling
-		entityBytes, err := json.Marshal(body.EventEntity)
+		message, err := decodeEventModel(model)
 		if err != nil {
 			return err
 		}
-		// Use factory to get typed struct
-		factory, ok := eventEntityFactories[body.EventType]
-		if !ok {
-			return fmt.Errorf("unsupported event type: %s", body.EventType)
-		}
-		entityPtr := factory()
-		if err := json.Unmarshal(entityBytes, entityPtr); err != nil {
-			return err
-		}
-		// Recreate new typed body
-		newBody := PubSubEvent{
-			EventID:            body.EventID,
-			EventTime:          body.EventTime,
-			EventType:          body.EventType,
-			EventEntity:        entityPtr,
-			EventChangedFields: body.EventChangedFields,
-		}
-		message := PubSubMessage{
-			Message: newBody,
-		}
 		// Resend the event, without re-storing it, in SYNCHRONOUS way
 		b.publishMessageToTopic(PubSubTopic(model.Topic), message)
 	}
 	return nil
 }
 
+/*
+Return the topics with at least one local subscriber.
+*/
+func (b *PubSubAgent) getSubscribedTopics() []PubSubTopic {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	topics := []PubSubTopic{}
+	for topic, subs := range b.subs {
+		if len(subs) > 0 {
+			topics = append(topics, PubSubTopic(topic))
+		}
+	}
+	return topics
+}
+
+/*
+Convert a stored event into a typed message ready to be dispatched.
+*/
+func decodeEventModel(model eventModel) (PubSubMessage, error) {
+	// Unmarhal the stored event
+	var body PubSubEvent
+	if err := json.Unmarshal(model.EventBody, &body); err != nil {
+		return PubSubMessage{}, err
+	}
+	// Convert EventEntity to raw bytes for further unmarshaling
+	entityBytes, err := json.Marshal(body.EventEntity)
+	if err != nil {
+		return PubSubMessage{}, err
+	}
+	// Use factory to get typed struct
+	factory, ok := eventEntityFactories[body.EventType]
+	if !ok {
+		return PubSubMessage{}, fmt.Errorf("unsupported event type: %s", body.EventType)
+	}
+	entityPtr := factory()
+	if err := json.Unmarshal(entityBytes, entityPtr); err != nil {
+		return PubSubMessage{}, err
+	}
+	// Recreate new typed body
+	newBody := PubSubEvent{
+		EventID:            body.EventID,
+		EventTime:          body.EventTime,
+		EventType:          body.EventType,
+		EventEntity:        entityPtr,
+		EventChangedFields: body.EventChangedFields,
+		TraceParent:        body.TraceParent,
+	}
+	return PubSubMessage{
+		Message: newBody,
+	}, nil
+}
+
 /*
 Subscribe to a topic by receving a dedicated channel to listen and wait published messages.
+The consumer group identifies the consumer across the instances: in distributed mode, each event
+is delivered only once per consumer group.
 */
-func (b *PubSubAgent) Subscribe(pubsubTopic PubSubTopic) <-chan PubSubMessage {
+func (b *PubSubAgent) Subscribe(pubsubTopic PubSubTopic, consumerGroup string) <-chan PubSubMessage {
 	topic := string(pubsubTopic)
 	zap.L().Info(
 		fmt.Sprintf("Subscribing to Topic %s", topic),
 		zap.String("service", "pub-sub"),
 		zap.String("topic", topic),
+		zap.String("consumer-group", consumerGroup),
 	)
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -220,10 +374,21 @@ func (b *PubSubAgent) Subscribe(pubsubTopic PubSubTopic) <-chan PubSubMessage {
 	}
 
 	ch := make(chan PubSubMessage, 1)
-	b.subs[topic] = append(b.subs[topic], ch)
+	b.subs[topic] = append(b.subs[topic], subscriber{consumerGroup: consumerGroup, ch: ch})
 	return ch
 }
 
+/*
+Redeliver in background all the events committed but not processed yet, e.g. due to a crash.
+It must be called once all the consumers are subscribed.
+*/
+func (b *PubSubAgent) Init() {
+	if b.pubSubScheduler == nil {
+		return
+	}
+	go b.pubSubScheduler.redeliverPendingEvents(redeliveryJobParameter)
+}
+
 /*
 Close the agent and all the channel avoiding publishers and consumers to send and read new events.
 */
@@ -238,10 +403,13 @@ func (b *PubSubAgent) Close() {
 
 	b.closed = true
 	close(b.quit)
+	if b.pubSubListener != nil {
+		b.pubSubListener.close()
+	}
 
-	for _, ch := range b.subs {
-		for _, sub := range ch {
-			close(sub)
+	for _, subs := range b.subs {
+		for _, sub := range subs {
+			close(sub.ch)
 		}
 	}
 	zap.L().Info("PubSub agent closed!", zap.String("service", "pub-sub"))
@@ -7,18 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
+var redeliveryJobParameter = mm_scheduler.ScheduledJobParameter{
+	JobID: 61529834,
+	Title: "RedeliverPendingPubSubEvents",
+}
+
 type pubsubScheduler struct {
 	scheduler            *mm_scheduler.Scheduler
 	storage              *gorm.DB
+	agent                *PubSubAgent
 	singleConnection     *mm_scheduler.SingleConnection
 	persistRetentionDays int
 }
 
-func newPubsubScheduler(storage *gorm.DB, scheduler *mm_scheduler.Scheduler, persistRetentionDays int) pubsubScheduler {
+func newPubsubScheduler(storage *gorm.DB, scheduler *mm_scheduler.Scheduler, agent *PubSubAgent, persistRetentionDays int) pubsubScheduler {
 	singleConnection := scheduler.GetSingleConnection(storage)
 	return pubsubScheduler{
 		scheduler:            scheduler,
 		storage:              storage,
+		agent:                agent,
 		singleConnection:     singleConnection,
 		persistRetentionDays: persistRetentionDays,
 	}
@@ -28,13 +35,20 @@ func (s pubsubScheduler) init() {
 	// Declare all jobs to be scheduled
 	var jobsToSchedule []mm_scheduler.ScheduledJob = []mm_scheduler.ScheduledJob{
 		{
+			Schedule:   "* * * * *", // Every minute
+			Handler:    s.redeliverPendingEvents,
+			Parameters: redeliveryJobParameter,
+		},
+	}
+	if s.persistRetentionDays > 0 {
+		jobsToSchedule = append(jobsToSchedule, mm_scheduler.ScheduledJob{
 			Schedule: "0 * * * *", // Every hour at HH:00
 			Handler:  s.cleanUpOldPubSubEvents,
 			Parameters: mm_scheduler.ScheduledJobParameter{
 				JobID: 83701937,
 				Title: "CleanUpOldPubSubEvents",
 			},
-		},
+		})
 	}
 	// Schedule all jobs
 	for _, jobToSchedule := range jobsToSchedule {
@@ -60,8 +74,33 @@ func (s pubsubScheduler) cleanUpOldPubSubEvents(p mm_scheduler.ScheduledJobParam
 	// If this istance acquires the lock, executre the business logic
 	if lockAcquired := s.scheduler.AcquireLock(s.singleConnection, p.JobID); lockAcquired {
 		zap.L().Info("Starting Cron Job...", zap.String("job", p.Title))
-		// Delete all old events based on retention policy
-		if err := s.storage.Where("event_date < NOW() - (? * INTERVAL '1 day')", retentionInDays).Delete(&eventModel{}).Error; err != nil {
+		// Delete all old events based on retention policy, keeping the ones not relayed yet by the outbox sinks.
+		// Cursors not updated during the retention period belong to sinks no longer relaying, so they are ignored
+		if err := s.storage.
+			Where("event_date < NOW() - (? * INTERVAL '1 day')", retentionInDays).
+			Where("NOT EXISTS (SELECT 1 FROM mm_outbox_cursor c WHERE c.topic = mm_event.topic AND c.updated_at >= NOW() - (? * INTERVAL '1 day') AND (c.last_txid, c.last_seq) < (mm_event.txid, mm_event.seq))", retentionInDays).
+			Delete(&eventModel{}).Error; err != nil {
+			zap.L().Error("Cron Job Failed", zap.String("job", p.Title), zap.Error(err))
+			return err
+		}
+		zap.L().Info("Cron Job executed!", zap.String("job", p.Title))
+	}
+	return nil
+}
+
+/*
+Scheduled function to run. It redelivers events not processed yet, retrying the failed ones
+*/
+func (s pubsubScheduler) redeliverPendingEvents(p mm_scheduler.ScheduledJobParameter) error {
+	defer func() {
+		if r := recover(); r != nil {
+			mm_log.LogPanicError(r, "RedeliverPendingPubSubEvents", "Panic occurred in cron activity")
+		}
+	}()
+	// If this istance acquires the lock, executre the business logic
+	if lockAcquired := s.scheduler.AcquireLock(s.singleConnection, p.JobID); lockAcquired {
+		zap.L().Info("Starting Cron Job...", zap.String("job", p.Title))
+		if err := s.agent.redeliverPendingEvents(); err != nil {
 			zap.L().Error("Cron Job Failed", zap.String("job", p.Title), zap.Error(err))
 			return err
 		}
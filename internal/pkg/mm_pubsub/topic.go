@@ -21,3 +21,15 @@ const (
 	TopicFeedbackV1        PubSubTopic = "topic/v1/feedback"
 	TopicRsEngineV1        PubSubTopic = "topic/v1/rs-engine"
 )
+
+var AvailableTopics = []PubSubTopic{
+	TopicUseCaseV1,
+	TopicUseCaseStepV1,
+	TopicFlowV1,
+	TopicFlowStatisticsV1,
+	TopicFlowStepV1,
+	TopicRolloutStrategyV1,
+	TopicPickerV1,
+	TopicFeedbackV1,
+	TopicRsEngineV1,
+}
@@ -0,0 +1,112 @@
+package mm_rsengine
+
+import (
+	"math"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+)
+
+/*
+Number of half-lives after which feedback is ignored by the decayed average (its weight is below one in a million)
+*/
+const decayMaxHalfLives = 20
+
+/*
+ScoreBucket aggregates the feedback received by a Flow in a time bucket, starting at the given time.
+*/
+type ScoreBucket struct {
+	FlowID          uuid.UUID
+	StartedAt       time.Time
+	TotFeedback     int64
+	SumScore        float64
+	SumSquaredScore float64
+}
+
+/*
+ScoreAggregationSince returns the start of the oldest bucket that contributes to the aggregated scores.
+*/
+func ScoreAggregationSince(aggregation mm_pubsub.RsScoreAggregation, now time.Time) time.Time {
+	switch aggregation.Mode {
+	case mm_pubsub.RsScoreAggregationWindow:
+		if aggregation.WindowHours != nil {
+			return now.Add(-time.Duration(*aggregation.WindowHours) * time.Hour)
+		}
+	case mm_pubsub.RsScoreAggregationDecay:
+		if aggregation.HalfLifeHours != nil {
+			return now.Add(-time.Duration(*aggregation.HalfLifeHours * decayMaxHalfLives * float64(time.Hour)))
+		}
+	}
+	return time.Time{}
+}
+
+/*
+AggregateScores summarizes the feedback of each Flow from its buckets, either on a sliding window (only the
+buckets started within the window) or with an exponentially decayed average, where the weight of a bucket
+halves every half-life. With the decay, the number of feedback is the effective (weighted) one.
+*/
+func AggregateScores(aggregation mm_pubsub.RsScoreAggregation, buckets []ScoreBucket, now time.Time) []FeedbackSummary {
+	since := ScoreAggregationSince(aggregation, now)
+	type weightedSums struct {
+		count      float64
+		sumScore   float64
+		sumSquared float64
+	}
+	indexedSums := map[uuid.UUID]*weightedSums{}
+	flowIDs := []uuid.UUID{}
+	for _, bucket := range buckets {
+		if bucket.StartedAt.Before(since) {
+			continue
+		}
+		weight := 1.0
+		if aggregation.Mode == mm_pubsub.RsScoreAggregationDecay && aggregation.HalfLifeHours != nil {
+			age := math.Max(now.Sub(bucket.StartedAt).Hours(), 0)
+			weight = math.Pow(0.5, age / *aggregation.HalfLifeHours)
+		}
+		sums, ok := indexedSums[bucket.FlowID]
+		if !ok {
+			sums = &weightedSums{}
+			indexedSums[bucket.FlowID] = sums
+			flowIDs = append(flowIDs, bucket.FlowID)
+		}
+		sums.count += weight * float64(bucket.TotFeedback)
+		sums.sumScore += weight * bucket.SumScore
+		sums.sumSquared += weight * bucket.SumSquaredScore
+	}
+	summaries := make([]FeedbackSummary, len(flowIDs))
+	for i, flowID := range flowIDs {
+		sums := indexedSums[flowID]
+		summaries[i] = FeedbackSummary{
+			FlowID:      flowID,
+			TotFeedback: int64(math.Round(sums.count)),
+		}
+		if sums.count > 0 {
+			summaries[i].AvgScore = sums.sumScore / sums.count
+		}
+		if sums.count > 1 {
+			summaries[i].VarScore = math.Max((sums.sumSquared/sums.count-summaries[i].AvgScore*summaries[i].AvgScore)*sums.count/(sums.count-1), 0)
+		}
+	}
+	return summaries
+}
+
+/*
+Replace the number of feedback and the average score of the Flow statistics with the aggregated ones, to rank
+the Flows by the allocators. Flows without feedback in the aggregation have no feedback.
+*/
+func withAggregatedScores(statistics []FlowStatistics, summaries []FeedbackSummary) []FlowStatistics {
+	indexedSummaries := map[uuid.UUID]FeedbackSummary{}
+	for _, summary := range summaries {
+		indexedSummaries[summary.FlowID] = summary
+	}
+	result := make([]FlowStatistics, len(statistics))
+	for i, stat := range statistics {
+		summary := indexedSummaries[stat.FlowID]
+		stat.TotFeedback = summary.TotFeedback
+		stat.AvgScore = mm_utils.RoundTo2Decimals(summary.AvgScore)
+		result[i] = stat
+	}
+	return result
+}
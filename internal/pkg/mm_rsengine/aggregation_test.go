@@ -0,0 +1,83 @@
+package mm_rsengine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+)
+
+func TestAggregateScores(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	flowA := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	flowB := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	window := mm_pubsub.RsScoreAggregation{Mode: mm_pubsub.RsScoreAggregationWindow, WindowHours: mm_utils.Int64Ptr(2)}
+	decay := mm_pubsub.RsScoreAggregation{Mode: mm_pubsub.RsScoreAggregationDecay, HalfLifeHours: mm_utils.Float64Ptr(1)}
+	tests := []struct {
+		name        string
+		aggregation mm_pubsub.RsScoreAggregation
+		buckets     []ScoreBucket
+		want        []FeedbackSummary
+	}{
+		{
+			name:        "window keeps only the recent buckets",
+			aggregation: window,
+			buckets: []ScoreBucket{
+				{FlowID: flowA, StartedAt: now.Add(-1 * time.Hour), TotFeedback: 2, SumScore: 8, SumSquaredScore: 32},
+				{FlowID: flowA, StartedAt: now.Add(-3 * time.Hour), TotFeedback: 2, SumScore: 2, SumSquaredScore: 2},
+				{FlowID: flowB, StartedAt: now.Add(-2 * time.Hour), TotFeedback: 2, SumScore: 6, SumSquaredScore: 20},
+			},
+			want: []FeedbackSummary{
+				{FlowID: flowA, TotFeedback: 2, AvgScore: 4, VarScore: 0},
+				{FlowID: flowB, TotFeedback: 2, AvgScore: 3, VarScore: 2},
+			},
+		},
+		{
+			name:        "window without recent buckets",
+			aggregation: window,
+			buckets: []ScoreBucket{
+				{FlowID: flowA, StartedAt: now.Add(-5 * time.Hour), TotFeedback: 2, SumScore: 8, SumSquaredScore: 32},
+			},
+			want: []FeedbackSummary{},
+		},
+		{
+			name:        "decay halves the weight every half-life",
+			aggregation: decay,
+			buckets: []ScoreBucket{
+				{FlowID: flowA, StartedAt: now, TotFeedback: 1, SumScore: 5, SumSquaredScore: 25},
+				{FlowID: flowA, StartedAt: now.Add(-1 * time.Hour), TotFeedback: 2, SumScore: 2, SumSquaredScore: 2},
+			},
+			want: []FeedbackSummary{
+				{FlowID: flowA, TotFeedback: 2, AvgScore: 3, VarScore: 8},
+			},
+		},
+		{
+			name:        "decay ignores buckets older than the max half-lives",
+			aggregation: decay,
+			buckets: []ScoreBucket{
+				{FlowID: flowA, StartedAt: now.Add(-1 * time.Hour), TotFeedback: 4, SumScore: 16, SumSquaredScore: 64},
+				{FlowID: flowA, StartedAt: now.Add(-(decayMaxHalfLives + 1) * time.Hour), TotFeedback: 1000000, SumScore: 1000000, SumSquaredScore: 1000000},
+			},
+			want: []FeedbackSummary{
+				{FlowID: flowA, TotFeedback: 2, AvgScore: 4, VarScore: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AggregateScores(tt.aggregation, tt.buckets, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("unexpected summaries: got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].FlowID != tt.want[i].FlowID || got[i].TotFeedback != tt.want[i].TotFeedback ||
+					math.Abs(got[i].AvgScore-tt.want[i].AvgScore) > 1e-9 || math.Abs(got[i].VarScore-tt.want[i].VarScore) > 1e-9 {
+					t.Fatalf("unexpected summary %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package mm_rsengine
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+)
+
+/*
+An adaptive allocator decides how traffic is split between the active Flows during the ADAPTIVE phase,
+based on the Flow statistics. It updates the serve PCT of the given Flows and returns true once the
+rollout can be completed, i.e. one Flow serves 100% of traffic.
+*/
+type adaptiveAllocatorInterface interface {
+	allocate(flows []Flow, indexedStatistics map[string]FlowStatistics) bool
+}
+
+/*
+Return the allocator for the algorithm configured in the Adaptive phase. Greedy is the default one.
+Feedback summaries and the random source are used by the allocators sampling from the score distributions.
+*/
+func newAdaptiveAllocator(adaptive mm_pubsub.RsAdaptivePhase, summaries []FeedbackSummary, random *rand.Rand) adaptiveAllocatorInterface {
+	switch adaptive.Algorithm {
+	case mm_pubsub.RsAdaptiveAlgorithmThompsonSampling:
+		return newThompsonSamplingAllocator(adaptive, summaries, random)
+	case mm_pubsub.RsAdaptiveAlgorithmUCB1:
+		return newUCB1Allocator(adaptive)
+	case mm_pubsub.RsAdaptiveAlgorithmEpsilonGreedy:
+		return newEpsilonGreedyAllocator(adaptive)
+	default:
+		return newGreedyAllocator(adaptive)
+	}
+}
+
+/*
+Normalize an average feedback score between 0 and 1
+*/
+func normalizeScore(avgScore float64) float64 {
+	normalizedScore := (avgScore - MinFeedbackScore) / (MaxFeedbackScore - MinFeedbackScore)
+	return math.Min(math.Max(normalizedScore, 0), 1)
+}
+
+/*
+Give 100% of traffic to the Flow at the given index and 0% to all others
+*/
+func assignAllTraffic(flows []Flow, flowIndex int) {
+	for i := range flows {
+		if i == flowIndex {
+			flows[i].CurrentServePct = 100
+		} else {
+			flows[i].CurrentServePct = 0
+		}
+	}
+}
+
+/*
+Set the serve PCT of each Flow rounded to 2 decimals, assigning the rounding leftover to the Flow
+at the given index so the total is always 100%.
+*/
+func setServePcts(flows []Flow, servePcts []float64, leftoverFlowIndex int) {
+	totalPct := 0.0
+	for i := range flows {
+		flows[i].CurrentServePct = mm_utils.RoundTo2Decimals(servePcts[i])
+		totalPct += flows[i].CurrentServePct
+	}
+	leftoverPct := flows[leftoverFlowIndex].CurrentServePct + 100 - totalPct
+	flows[leftoverFlowIndex].CurrentServePct = mm_utils.RoundTo2Decimals(leftoverPct)
+}
+
+/*
+Move the current serve PCTs toward the target ones, shifting at most maxStepPct of traffic.
+All the differences are scaled by the same factor, so the total remains 100%.
+*/
+func moveTowardTargetPcts(flows []Flow, targetPcts []float64, maxStepPct float64) []float64 {
+	totalIncrement := 0.0
+	for i := range flows {
+		if targetPcts[i] > flows[i].CurrentServePct {
+			totalIncrement += targetPcts[i] - flows[i].CurrentServePct
+		}
+	}
+	factor := 1.0
+	if totalIncrement > maxStepPct {
+		factor = maxStepPct / totalIncrement
+	}
+	servePcts := make([]float64, len(flows))
+	for i := range flows {
+		servePcts[i] = flows[i].CurrentServePct + (targetPcts[i]-flows[i].CurrentServePct)*factor
+	}
+	return servePcts
+}
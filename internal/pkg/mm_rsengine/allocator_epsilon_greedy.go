@@ -0,0 +1,54 @@
+package mm_rsengine
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+)
+
+/*
+epsilonGreedyAllocator moves traffic, at most MaxStepPct per interval, toward a split where the Flow with
+the highest average score serves (1 - epsilon) of traffic and epsilon is spread evenly across all Flows
+for exploration. Once the best Flow serves its exploitation share, it gets 100% of traffic and the rollout
+can be completed: with epsilon greater than 0 a minimum confidence is required, so the completion waits until
+the best Flow is significantly better than all others.
+*/
+type epsilonGreedyAllocator struct {
+	maxStepPct float64
+	epsilon    float64
+}
+
+func newEpsilonGreedyAllocator(adaptive mm_pubsub.RsAdaptivePhase) epsilonGreedyAllocator {
+	allocator := epsilonGreedyAllocator{
+		maxStepPct: adaptive.MaxStepPct,
+		epsilon:    0.1,
+	}
+	if adaptive.Epsilon != nil {
+		allocator.epsilon = *adaptive.Epsilon
+	}
+	return allocator
+}
+
+func (a epsilonGreedyAllocator) allocate(flows []Flow, indexedStatistics map[string]FlowStatistics) bool {
+	// Find the Flow with the highest score
+	bestFlowIndex := 0
+	bestScore := 0.0
+	for i := range flows {
+		if stat, ok := indexedStatistics[flows[i].ID.String()]; ok && stat.AvgScore > bestScore {
+			bestScore = stat.AvgScore
+			bestFlowIndex = i
+		}
+	}
+	// Exploration traffic is split evenly, exploitation traffic goes to the best Flow
+	targetPcts := make([]float64, len(flows))
+	for i := range flows {
+		targetPcts[i] = a.epsilon * 100 / float64(len(flows))
+	}
+	targetPcts[bestFlowIndex] += (1 - a.epsilon) * 100
+	setServePcts(flows, moveTowardTargetPcts(flows, targetPcts, a.maxStepPct), bestFlowIndex)
+	// Once the exploitation share is reached, exploration is over
+	if flows[bestFlowIndex].CurrentServePct >= mm_utils.RoundTo2Decimals(targetPcts[bestFlowIndex]) {
+		assignAllTraffic(flows, bestFlowIndex)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package mm_rsengine
+
+import (
+	"slices"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+)
+
+/*
+greedyAllocator shifts at most MaxStepPct of traffic from worst Flows to the Flows with the highest average score.
+*/
+type greedyAllocator struct {
+	maxStepPct float64
+}
+
+func newGreedyAllocator(adaptive mm_pubsub.RsAdaptivePhase) greedyAllocator {
+	return greedyAllocator{
+		maxStepPct: adaptive.MaxStepPct,
+	}
+}
+
+func (a greedyAllocator) allocate(flows []Flow, indexedStatistics map[string]FlowStatistics) bool {
+	// Find highest score and best Flow Indexes
+	bestScore := 0.0
+	bestFlowIndexes := []int{}
+	for i := range flows {
+		if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
+			if stat.AvgScore > bestScore {
+				bestScore = stat.AvgScore
+				bestFlowIndexes = []int{i}
+			} else if stat.AvgScore == bestScore {
+				bestFlowIndexes = append(bestFlowIndexes, i)
+			}
+		}
+	}
+	// Check how much traffic is provided by worst Flows and find their Indexes
+	servedByWorst := 0.0
+	sumAvgScoreByWorst := 0.0
+	worstFlowIndexes := []int{}
+	for i := range flows {
+		if !slices.Contains(bestFlowIndexes, i) {
+			worstFlowIndexes = append(worstFlowIndexes, i)
+			servedByWorst += flows[i].CurrentServePct
+			if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
+				sumAvgScoreByWorst += stat.AvgScore
+			}
+		}
+	}
+	sumAvgScoreByWorst = mm_utils.RoundTo2Decimals(sumAvgScoreByWorst)
+	// Calculate the maximum increment we can assign to best Flows by decrementing worst Flows
+	// keeping as limit the provided configuration
+	totalPossibleIncrement := a.maxStepPct
+	if servedByWorst < totalPossibleIncrement {
+		totalPossibleIncrement = servedByWorst
+	}
+	// Calculate the increment per Flow (in case there are multiple best flows, split the increment between them)
+	incrementPerBestFlow := totalPossibleIncrement / float64(len(bestFlowIndexes))
+	// Increase traffic for best flows, without passing 100%. Track if one Flow reached 100%
+	flowReachedMaxPct := false
+	for _, bestFlowIndex := range bestFlowIndexes {
+		newPct := flows[bestFlowIndex].CurrentServePct + incrementPerBestFlow
+		flows[bestFlowIndex].CurrentServePct = mm_utils.RoundTo2Decimals(newPct)
+		if flows[bestFlowIndex].CurrentServePct >= 100 {
+			flowReachedMaxPct = true
+			flows[bestFlowIndex].CurrentServePct = 100
+		}
+	}
+	// If one Flow reached 100%, we need to put all others to 0%
+	if flowReachedMaxPct {
+		for i := range flows {
+			if flows[i].CurrentServePct != 100.0 {
+				flows[i].CurrentServePct = 0
+			}
+		}
+		return true
+	}
+	// Otherwise, for each worst Flow, calculate how much we need to decrement it based on the distance between its Score and Worst Score
+	totDecremented := 0.0
+	for _, i := range worstFlowIndexes {
+		if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
+			denominator := (sumAvgScoreByWorst * float64(len(worstFlowIndexes)-1))
+			if denominator == 0 {
+				denominator = 1
+			}
+			toDecrement := ((sumAvgScoreByWorst - stat.AvgScore) / denominator * totalPossibleIncrement)
+			newPct := flows[i].CurrentServePct - toDecrement
+
+			if newPct < 0.0 {
+				totDecremented += flows[i].CurrentServePct
+				flows[i].CurrentServePct = 0
+			} else {
+				totDecremented += toDecrement
+				flows[i].CurrentServePct = mm_utils.RoundTo2Decimals(newPct)
+			}
+		}
+	}
+	if totalPossibleIncrement-totDecremented > 0 {
+		missingDecrement := totalPossibleIncrement - totDecremented
+		// If there is additional PCT to decrement, proceed in order, by sorting all Flows based on their Scores
+		slices.SortFunc(flows, func(a Flow, b Flow) int {
+			if statA, okA := indexedStatistics[a.ID.String()]; okA {
+				if statB, okB := indexedStatistics[b.ID.String()]; okB {
+					return int((statA.AvgScore - statB.AvgScore) * 100)
+				}
+			}
+			return 0
+		})
+		// Now, considering only Worst Flows, start reducing the traffic frome the worst Flow to better one
+		for i := range flows {
+			if missingDecrement == 0 {
+				break
+			}
+			if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
+				// Consider only worst Flows
+				if stat.AvgScore != bestScore {
+					if flows[i].CurrentServePct >= missingDecrement {
+						newPct := flows[i].CurrentServePct - missingDecrement
+						flows[i].CurrentServePct = mm_utils.RoundTo2Decimals(newPct)
+						missingDecrement = 0
+					} else {
+						missingDecrement -= flows[i].CurrentServePct
+						flows[i].CurrentServePct = 0
+					}
+				}
+			}
+		}
+	}
+	return false
+}
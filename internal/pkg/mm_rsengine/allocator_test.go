@@ -0,0 +1,176 @@
+package mm_rsengine
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+)
+
+var (
+	testFlowA = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	testFlowB = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+)
+
+/*
+Return the serve PCTs of the Flows, indexed by Flow ID, to compare them regardless of the order.
+*/
+func servePcts(flows []Flow) map[uuid.UUID]float64 {
+	pcts := map[uuid.UUID]float64{}
+	for _, flow := range flows {
+		pcts[flow.ID] = flow.CurrentServePct
+	}
+	return pcts
+}
+
+func TestAdaptiveAllocators(t *testing.T) {
+	tests := []struct {
+		name       string
+		adaptive   mm_pubsub.RsAdaptivePhase
+		flows      []Flow
+		statistics []FlowStatistics
+		summaries  []FeedbackSummary
+		wantPcts   map[uuid.UUID]float64
+		wantDone   bool
+	}{
+		{
+			name:     "greedy shifts the max step to the best Flow",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmGreedy, MaxStepPct: 10},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 50}, {ID: testFlowB, CurrentServePct: 50}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 10, AvgScore: 4},
+				{FlowID: testFlowB, TotFeedback: 10, AvgScore: 3},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 60, testFlowB: 40},
+		},
+		{
+			name:     "greedy completes once the best Flow serves all the traffic",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmGreedy, MaxStepPct: 10},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 95}, {ID: testFlowB, CurrentServePct: 5}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 10, AvgScore: 4},
+				{FlowID: testFlowB, TotFeedback: 10, AvgScore: 3},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 100, testFlowB: 0},
+			wantDone: true,
+		},
+		{
+			name:     "epsilon greedy moves toward the exploitation share",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmEpsilonGreedy, MaxStepPct: 10, Epsilon: mm_utils.Float64Ptr(0.1)},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 50}, {ID: testFlowB, CurrentServePct: 50}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 10, AvgScore: 4},
+				{FlowID: testFlowB, TotFeedback: 10, AvgScore: 3},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 60, testFlowB: 40},
+		},
+		{
+			name:     "epsilon greedy completes once the exploitation share is reached",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmEpsilonGreedy, MaxStepPct: 10, Epsilon: mm_utils.Float64Ptr(0.1)},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 90}, {ID: testFlowB, CurrentServePct: 10}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 10, AvgScore: 4},
+				{FlowID: testFlowB, TotFeedback: 10, AvgScore: 3},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 100, testFlowB: 0},
+			wantDone: true,
+		},
+		{
+			name:     "ucb1 explores Flows without feedback first",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmUCB1, MaxStepPct: 10},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 50}, {ID: testFlowB, CurrentServePct: 50}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 10, AvgScore: 5},
+				{FlowID: testFlowB, TotFeedback: 0, AvgScore: 0},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 40, testFlowB: 60},
+		},
+		{
+			name:     "ucb1 completes when the best lower bound is above the other upper bounds",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmUCB1, MaxStepPct: 10},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 50}, {ID: testFlowB, CurrentServePct: 50}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 100, AvgScore: 5},
+				{FlowID: testFlowB, TotFeedback: 100, AvgScore: 1},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 100, testFlowB: 0},
+			wantDone: true,
+		},
+		{
+			name:     "thompson sampling moves toward the confidently best Flow",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmThompsonSampling, MaxStepPct: 10, MinBestProbability: mm_utils.Float64Ptr(0.95)},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 50}, {ID: testFlowB, CurrentServePct: 50}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 1000, AvgScore: 4.5},
+				{FlowID: testFlowB, TotFeedback: 1000, AvgScore: 2},
+			},
+			summaries: []FeedbackSummary{
+				{FlowID: testFlowA, TotFeedback: 1000, AvgScore: 4.5, VarScore: 0.5},
+				{FlowID: testFlowB, TotFeedback: 1000, AvgScore: 2, VarScore: 0.5},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 60, testFlowB: 40},
+		},
+		{
+			name:     "thompson sampling completes once the best Flow serves all the traffic",
+			adaptive: mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmThompsonSampling, MaxStepPct: 10, MinBestProbability: mm_utils.Float64Ptr(0.95)},
+			flows:    []Flow{{ID: testFlowA, CurrentServePct: 95}, {ID: testFlowB, CurrentServePct: 5}},
+			statistics: []FlowStatistics{
+				{FlowID: testFlowA, TotFeedback: 1000, AvgScore: 4.5},
+				{FlowID: testFlowB, TotFeedback: 1000, AvgScore: 2},
+			},
+			summaries: []FeedbackSummary{
+				{FlowID: testFlowA, TotFeedback: 1000, AvgScore: 4.5, VarScore: 0.5},
+				{FlowID: testFlowB, TotFeedback: 1000, AvgScore: 2, VarScore: 0.5},
+			},
+			wantPcts: map[uuid.UUID]float64{testFlowA: 100, testFlowB: 0},
+			wantDone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			random := rand.New(rand.NewPCG(1, 2))
+			allocator := newAdaptiveAllocator(tt.adaptive, tt.summaries, random)
+			done := allocator.allocate(tt.flows, indexStatistics(tt.statistics))
+			if done != tt.wantDone {
+				t.Fatalf("unexpected completion: got %v, want %v", done, tt.wantDone)
+			}
+			got := servePcts(tt.flows)
+			for flowID, wantPct := range tt.wantPcts {
+				if got[flowID] != wantPct {
+					t.Fatalf("unexpected serve PCTs: got %v, want %v", got, tt.wantPcts)
+				}
+			}
+		})
+	}
+}
+
+func TestThompsonSamplingAllocatorIsReproducible(t *testing.T) {
+	adaptive := mm_pubsub.RsAdaptivePhase{Algorithm: mm_pubsub.RsAdaptiveAlgorithmThompsonSampling, MaxStepPct: 50}
+	statistics := []FlowStatistics{
+		{FlowID: testFlowA, TotFeedback: 20, AvgScore: 3.6},
+		{FlowID: testFlowB, TotFeedback: 20, AvgScore: 3.4},
+	}
+	summaries := []FeedbackSummary{
+		{FlowID: testFlowA, TotFeedback: 20, AvgScore: 3.6, VarScore: 1.5},
+		{FlowID: testFlowB, TotFeedback: 20, AvgScore: 3.4, VarScore: 1.5},
+	}
+	allocate := func() map[uuid.UUID]float64 {
+		flows := []Flow{{ID: testFlowA, CurrentServePct: 50}, {ID: testFlowB, CurrentServePct: 50}}
+		allocator := newAdaptiveAllocator(adaptive, summaries, rand.New(rand.NewPCG(1, 2)))
+		if allocator.allocate(flows, indexStatistics(statistics)) {
+			t.Fatalf("unexpected completion with close scores")
+		}
+		return servePcts(flows)
+	}
+	first, second := allocate(), allocate()
+	if first[testFlowA] != second[testFlowA] || first[testFlowB] != second[testFlowB] {
+		t.Fatalf("allocations differ with the same seed: %v and %v", first, second)
+	}
+	// Close scores split the traffic based on the probability of being the best Flow
+	if first[testFlowA] <= 50 || first[testFlowA] >= 90 || math.Abs(first[testFlowA]+first[testFlowB]-100) > 1e-9 {
+		t.Fatalf("unexpected split between close Flows: %v", first)
+	}
+}
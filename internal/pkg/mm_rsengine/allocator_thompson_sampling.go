@@ -0,0 +1,102 @@
+package mm_rsengine
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+)
+
+/*
+thompsonSamplingAllocator moves traffic, at most MaxStepPct per interval, toward a split where each Flow serves a
+share of traffic equal to its posterior probability of being the best one. Once a Flow is the best with at least
+the configured probability, the target becomes 100% of traffic for that Flow.
+*/
+type thompsonSamplingAllocator struct {
+	maxStepPct         float64
+	minBestProbability *float64
+	indexedSummaries   map[string]FeedbackSummary
+	random             *rand.Rand
+}
+
+func newThompsonSamplingAllocator(adaptive mm_pubsub.RsAdaptivePhase, summaries []FeedbackSummary, random *rand.Rand) thompsonSamplingAllocator {
+	return thompsonSamplingAllocator{
+		maxStepPct:         adaptive.MaxStepPct,
+		minBestProbability: adaptive.MinBestProbability,
+		indexedSummaries:   indexFeedbackSummaries(summaries),
+		random:             random,
+	}
+}
+
+func (a thompsonSamplingAllocator) allocate(flows []Flow, indexedStatistics map[string]FlowStatistics) bool {
+	probabilities := calculateBestProbabilities(flows, indexedStatistics, a.indexedSummaries, a.random)
+	bestFlowIndex := 0
+	for i := range probabilities {
+		if probabilities[i] > probabilities[bestFlowIndex] {
+			bestFlowIndex = i
+		}
+	}
+	targetPcts := make([]float64, len(flows))
+	if a.minBestProbability != nil && probabilities[bestFlowIndex] >= *a.minBestProbability {
+		// If the best Flow is confidently the best one, it goes toward all the traffic
+		targetPcts[bestFlowIndex] = 100
+	} else {
+		// Otherwise the traffic goes toward a split based on probabilities
+		for i := range probabilities {
+			targetPcts[i] = probabilities[i] * 100
+		}
+	}
+	setServePcts(flows, moveTowardTargetPcts(flows, targetPcts, a.maxStepPct), bestFlowIndex)
+	return flows[bestFlowIndex].CurrentServePct >= 100
+}
+
+/*
+Estimate for each Flow the posterior probability of being the best one.
+The average score of each Flow is modeled with a Normal distribution, based on the number of feedback, the
+average score and the variance of the scores. A prior centered in the middle of the score range, with the
+variance of uniform scores, counts as one feedback: Flows with few or scattered feedback have a wider
+distribution and keep being explored. When the summary of a Flow is not available, the variance is
+estimated as the highest one possible for its average score.
+*/
+func calculateBestProbabilities(flows []Flow, indexedStatistics map[string]FlowStatistics, indexedSummaries map[string]FeedbackSummary, random *rand.Rand) []float64 {
+	priorMean := (MinFeedbackScore + MaxFeedbackScore) / 2
+	priorVariance := math.Pow(MaxFeedbackScore-MinFeedbackScore, 2) / 12
+	means := make([]float64, len(flows))
+	stdDevs := make([]float64, len(flows))
+	for i := range flows {
+		count, avgScore, varScore := 0.0, 0.0, 0.0
+		if summary, ok := indexedSummaries[flows[i].ID.String()]; ok {
+			count, avgScore, varScore = float64(summary.TotFeedback), summary.AvgScore, summary.VarScore
+		} else if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
+			count, avgScore = float64(stat.TotFeedback), stat.AvgScore
+			varScore = math.Max((avgScore-MinFeedbackScore)*(MaxFeedbackScore-avgScore), 0)
+		}
+		means[i] = (priorMean + count*avgScore) / (count + 1)
+		stdDevs[i] = math.Sqrt((priorVariance + count*varScore) / (count + 1) / (count + 1))
+	}
+	wins := make([]int, len(flows))
+	for range thompsonSamplingDraws {
+		bestIndex := 0
+		bestSample := math.Inf(-1)
+		for i := range flows {
+			if sample := means[i] + random.NormFloat64()*stdDevs[i]; sample > bestSample {
+				bestSample = sample
+				bestIndex = i
+			}
+		}
+		wins[bestIndex]++
+	}
+	probabilities := make([]float64, len(flows))
+	for i := range wins {
+		probabilities[i] = float64(wins[i]) / float64(thompsonSamplingDraws)
+	}
+	return probabilities
+}
+
+/*
+BestProbabilities estimates for each Flow the posterior probability of being the best one, based on its
+feedback summary, as the Thompson Sampling allocator does.
+*/
+func BestProbabilities(flows []Flow, summaries []FeedbackSummary, random *rand.Rand) []float64 {
+	return calculateBestProbabilities(flows, map[string]FlowStatistics{}, indexFeedbackSummaries(summaries), random)
+}
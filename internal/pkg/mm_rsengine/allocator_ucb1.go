@@ -0,0 +1,81 @@
+package mm_rsengine
+
+import (
+	"math"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+)
+
+/*
+ucb1Allocator shifts at most MaxStepPct of traffic toward the Flow with the highest upper confidence bound
+(UCB1), so Flows with few feedback keep being explored. Once the lower bound of the Flow with the best
+average score is above the upper bounds of all the other Flows, it gets 100% of traffic.
+*/
+type ucb1Allocator struct {
+	maxStepPct        float64
+	explorationFactor float64
+}
+
+func newUCB1Allocator(adaptive mm_pubsub.RsAdaptivePhase) ucb1Allocator {
+	allocator := ucb1Allocator{
+		maxStepPct:        adaptive.MaxStepPct,
+		explorationFactor: math.Sqrt2,
+	}
+	if adaptive.ExplorationFactor != nil {
+		allocator.explorationFactor = *adaptive.ExplorationFactor
+	}
+	return allocator
+}
+
+func (a ucb1Allocator) allocate(flows []Flow, indexedStatistics map[string]FlowStatistics) bool {
+	// Total number of feedback across all active Flows
+	var totalFeedback int64 = 0
+	for i := range flows {
+		if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
+			totalFeedback += stat.TotFeedback
+		}
+	}
+	// Calculate bounds per Flow. Flows without feedback are explored first
+	means := make([]float64, len(flows))
+	upperBounds := make([]float64, len(flows))
+	lowerBounds := make([]float64, len(flows))
+	for i := range flows {
+		stat, ok := indexedStatistics[flows[i].ID.String()]
+		if !ok || stat.TotFeedback == 0 {
+			means[i] = math.Inf(-1)
+			upperBounds[i] = math.Inf(1)
+			lowerBounds[i] = math.Inf(-1)
+			continue
+		}
+		means[i] = normalizeScore(stat.AvgScore)
+		bonus := a.explorationFactor * math.Sqrt(math.Log(float64(totalFeedback))/float64(stat.TotFeedback))
+		upperBounds[i] = means[i] + bonus
+		lowerBounds[i] = means[i] - bonus
+	}
+	bestMeanIndex := 0
+	bestUpperBoundIndex := 0
+	for i := range flows {
+		if means[i] > means[bestMeanIndex] {
+			bestMeanIndex = i
+		}
+		if upperBounds[i] > upperBounds[bestUpperBoundIndex] {
+			bestUpperBoundIndex = i
+		}
+	}
+	// If the best Flow is better than all the others with confidence, it takes all the traffic
+	isBestConfident := !math.IsInf(lowerBounds[bestMeanIndex], -1)
+	for i := range flows {
+		if i != bestMeanIndex && upperBounds[i] > lowerBounds[bestMeanIndex] {
+			isBestConfident = false
+		}
+	}
+	if isBestConfident {
+		assignAllTraffic(flows, bestMeanIndex)
+		return true
+	}
+	// Otherwise move traffic toward the Flow with the highest upper bound
+	targetPcts := make([]float64, len(flows))
+	targetPcts[bestUpperBoundIndex] = 100
+	setServePcts(flows, moveTowardTargetPcts(flows, targetPcts, a.maxStepPct), bestUpperBoundIndex)
+	return flows[bestUpperBoundIndex].CurrentServePct >= 100
+}
@@ -0,0 +1,221 @@
+package mm_rsengine
+
+import (
+	"container/heap"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/google/uuid"
+)
+
+/*
+BacktestSession is a recorded session (first request of a correlation) and the Flow that served it.
+*/
+type BacktestSession struct {
+	CorrelationID uuid.UUID
+	FlowID        uuid.UUID
+	StartedAt     time.Time
+}
+
+/*
+BacktestFeedback is a recorded feedback on a session.
+*/
+type BacktestFeedback struct {
+	CorrelationID uuid.UUID
+	FlowID        uuid.UUID
+	Score         float64
+	CreatedAt     time.Time
+}
+
+type BacktestResult struct {
+	SimulationResult
+	StartedAt         time.Time                `json:"startedAt"`
+	TotSessions       int64                    `json:"totSessions"`
+	TotFeedback       int64                    `json:"totFeedback"`
+	ConvergenceMinute *int64                   `json:"convergenceMinute"`
+	WinnerFlowID      *uuid.UUID               `json:"winnerFlowId"`
+	Flows             []BacktestFlowAllocation `json:"flows"`
+}
+
+type BacktestFlowAllocation struct {
+	FlowID              uuid.UUID `json:"flowId"`
+	RecordedSessions    int64     `json:"recordedSessions"`
+	ProjectedSessions   int64     `json:"projectedSessions"`
+	ProjectedFeedback   int64     `json:"projectedFeedback"`
+	ProjectedAvgScore   float64   `json:"projectedAvgScore"`
+	ProjectedTrafficPct float64   `json:"projectedTrafficPct"`
+	FinalServePct       float64   `json:"finalServePct"`
+}
+
+/*
+Feedback of a Flow as recorded, with its delay from the start of the session
+*/
+type recordedFeedback struct {
+	score float64
+	delay time.Duration
+}
+
+/*
+Feedback waiting to be delivered to the engine
+*/
+type pendingFeedback struct {
+	flowID uuid.UUID
+	score  float64
+	dueAt  time.Time
+}
+
+type pendingFeedbackQueue []pendingFeedback
+
+func (q pendingFeedbackQueue) Len() int           { return len(q) }
+func (q pendingFeedbackQueue) Less(i, j int) bool { return q[i].dueAt.Before(q[j].dueAt) }
+func (q pendingFeedbackQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pendingFeedbackQueue) Push(x any)        { *q = append(*q, x.(pendingFeedback)) }
+func (q *pendingFeedbackQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+/*
+Backtest replays recorded sessions through the Rollout Strategy engine, starting from the WARMUP phase at the
+time of the first session. Each session is routed to a Flow based on the serve PCTs of the candidate
+configuration, also after the end of the Rollout Strategy. When the session goes to the Flow that served it,
+its recorded feedback is replayed as is; otherwise a feedback is resampled from the ones recorded for the
+selected Flow, keeping the feedback rate and the delay observed on that Flow. Sessions must be sorted by
+start time.
+*/
+func Backtest(config mm_pubsub.RSConfiguration, flows []Flow, sessions []BacktestSession, feedback []BacktestFeedback, seed uint64) BacktestResult {
+	random := rand.New(rand.NewPCG(seed, seed))
+	result := BacktestResult{}
+	if len(sessions) == 0 {
+		result.SimulationResult = newRolloutRun(config, flows, time.Time{}, random).finish(time.Time{})
+		return result
+	}
+	start := sessions[0].StartedAt.Truncate(time.Minute)
+	result.StartedAt = start
+	// Index recorded sessions and feedback
+	sessionStartedAt := map[uuid.UUID]time.Time{}
+	recordedSessions := map[uuid.UUID]int64{}
+	for _, session := range sessions {
+		sessionStartedAt[session.CorrelationID] = session.StartedAt
+		recordedSessions[session.FlowID]++
+	}
+	feedbackByCorrelation := map[uuid.UUID][]BacktestFeedback{}
+	feedbackPools := map[uuid.UUID][]recordedFeedback{}
+	sessionsWithFeedback := map[uuid.UUID]map[uuid.UUID]bool{}
+	for _, f := range feedback {
+		startedAt, ok := sessionStartedAt[f.CorrelationID]
+		if !ok {
+			continue
+		}
+		feedbackByCorrelation[f.CorrelationID] = append(feedbackByCorrelation[f.CorrelationID], f)
+		feedbackPools[f.FlowID] = append(feedbackPools[f.FlowID], recordedFeedback{score: f.Score, delay: max(f.CreatedAt.Sub(startedAt), 0)})
+		if _, ok := sessionsWithFeedback[f.FlowID]; !ok {
+			sessionsWithFeedback[f.FlowID] = map[uuid.UUID]bool{}
+		}
+		sessionsWithFeedback[f.FlowID][f.CorrelationID] = true
+	}
+	// Replay sessions minute by minute
+	run := newRolloutRun(config, flows, start, random)
+	queue := &pendingFeedbackQueue{}
+	projectedSessions := map[uuid.UUID]int64{}
+	now := start
+	sessionIndex := 0
+	running := true
+	endedAt := start
+	for sessionIndex < len(sessions) || queue.Len() > 0 {
+		now = now.Add(time.Minute)
+		for ; sessionIndex < len(sessions) && sessions[sessionIndex].StartedAt.Before(now); sessionIndex++ {
+			session := sessions[sessionIndex]
+			flowID, ok := pickFlow(run.flows, random)
+			if !ok {
+				continue
+			}
+			run.addSessionRequest(flowID)
+			projectedSessions[flowID]++
+			result.TotSessions++
+			// Same Flow as recorded, replay its feedback
+			if flowID == session.FlowID {
+				for _, f := range feedbackByCorrelation[session.CorrelationID] {
+					heap.Push(queue, pendingFeedback{flowID: flowID, score: f.Score, dueAt: f.CreatedAt})
+				}
+				continue
+			}
+			// Different Flow, resample a feedback based on what has been recorded for it
+			pool := feedbackPools[flowID]
+			if len(pool) == 0 || recordedSessions[flowID] == 0 {
+				continue
+			}
+			feedbackRate := float64(len(sessionsWithFeedback[flowID])) / float64(recordedSessions[flowID])
+			if random.Float64() < feedbackRate {
+				f := pool[random.IntN(len(pool))]
+				heap.Push(queue, pendingFeedback{flowID: flowID, score: f.score, dueAt: session.StartedAt.Add(f.delay)})
+			}
+		}
+		for queue.Len() > 0 && (*queue)[0].dueAt.Before(now) {
+			f := heap.Pop(queue).(pendingFeedback)
+			run.addFeedback(f.flowID, f.score, f.dueAt)
+			result.TotFeedback++
+		}
+		// Once the Rollout Strategy is over, the remaining sessions are routed with the final serve PCTs
+		if running {
+			running = run.step(now)
+			endedAt = now
+		}
+	}
+	result.SimulationResult = run.finish(endedAt)
+	// Convergence and winner
+	for _, transition := range result.Transitions {
+		if transition.ToState == mm_pubsub.RolloutStateCompleted {
+			minute := transition.Minute
+			result.ConvergenceMinute = &minute
+		}
+	}
+	if result.FinalState == mm_pubsub.RolloutStateCompleted {
+		for i := range run.flows {
+			if result.WinnerFlowID == nil || run.flows[i].CurrentServePct > run.servePct(*result.WinnerFlowID) {
+				result.WinnerFlowID = &run.flows[i].ID
+			}
+		}
+	}
+	// Projected allocation per Flow
+	statistics := run.flowStatistics()
+	result.Flows = make([]BacktestFlowAllocation, len(statistics))
+	for i, stat := range statistics {
+		result.Flows[i] = BacktestFlowAllocation{
+			FlowID:            stat.FlowID,
+			RecordedSessions:  recordedSessions[stat.FlowID],
+			ProjectedSessions: projectedSessions[stat.FlowID],
+			ProjectedFeedback: stat.TotFeedback,
+			ProjectedAvgScore: stat.AvgScore,
+			FinalServePct:     run.servePct(stat.FlowID),
+		}
+		if result.TotSessions > 0 {
+			result.Flows[i].ProjectedTrafficPct = float64(projectedSessions[stat.FlowID]) / float64(result.TotSessions) * 100
+		}
+	}
+	return result
+}
+
+/*
+Pick a Flow randomly, weighted by its serve PCT
+*/
+func pickFlow(flows []Flow, random *rand.Rand) (uuid.UUID, bool) {
+	var totalPct float64 = 0
+	for _, flow := range flows {
+		totalPct += flow.CurrentServePct
+	}
+	if totalPct <= 0 {
+		return uuid.UUID{}, false
+	}
+	target := random.Float64() * totalPct
+	for _, flow := range flows {
+		if target < flow.CurrentServePct {
+			return flow.ID, true
+		}
+		target -= flow.CurrentServePct
+	}
+	return flows[len(flows)-1].ID, true
+}
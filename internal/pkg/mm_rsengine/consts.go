@@ -0,0 +1,11 @@
+package mm_rsengine
+
+const (
+	MinFeedbackScore float64 = 1.0
+	MaxFeedbackScore float64 = 5.0
+)
+
+/*
+Number of draws from the posterior distributions used to estimate the probability of each Flow being the best one
+*/
+const thompsonSamplingDraws = 10000
@@ -0,0 +1,281 @@
+/*
+Package mm_rsengine implements the Rollout Strategy engine logic, independent from the storage and the clock,
+so it can be used both by the live engine and by simulations. Each function updates the serve PCT of the given
+active Flows and returns the new Rollout State, together with a flag that reports if the Rollout Strategy has
+been evaluated (and so an update has to be notified) or if it has been skipped.
+*/
+package mm_rsengine
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+)
+
+/*
+PhaseStartedAt returns when the current phase of the Rollout Strategy started, which is its last update,
+unless the phase has been resumed after a pause: in that case the start is shifted forward by the paused
+duration, so the time spent in PAUSED does not count toward Warmup and Adaptive intervals.
+*/
+func PhaseStartedAt(config mm_pubsub.RSConfiguration, updatedAt time.Time) time.Time {
+	if config.StateConfigurations.PhaseStartedAt != nil {
+		return *config.StateConfigurations.PhaseStartedAt
+	}
+	return updatedAt
+}
+
+/*
+ApplyWarmupOnTraffic moves the Flows toward their Warmup goals based on the total number of session requests
+received by the Use Case. Once all goals are achieved, the Rollout Strategy moves to ADAPTIVE.
+Note: inactive Flows are included in statistics as well because they can be disabled in the middle,
+but the total requests remains.
+*/
+func ApplyWarmupOnTraffic(state mm_pubsub.RolloutState, config mm_pubsub.RSConfiguration, flows []Flow, statistics []FlowStatistics) (mm_pubsub.RolloutState, bool) {
+	// If the RS is not in the WARMUP status or the Warmup configuration is not base on Traffic rules, skip it
+	if state != mm_pubsub.RolloutStateWarmup || config.Warmup == nil || config.Warmup.IntervalSessReqs == nil {
+		return state, false
+	}
+	// Total Count of Session Requests across all existing Flows
+	var totalCountSessionReqs int64 = 0
+	for _, stat := range statistics {
+		totalCountSessionReqs += stat.TotSessionRequests
+	}
+	// If all flows have achieved their goal, we can move to the next state
+	if applyWarmupGoals(*config.Warmup, flows, totalCountSessionReqs, *config.Warmup.IntervalSessReqs) {
+		return mm_pubsub.RolloutStateAdaptive, true
+	}
+	return state, true
+}
+
+/*
+ApplyWarmupOnTime moves the Flows toward their Warmup goals based on the time elapsed since the start of
+the WARMUP phase. Once all goals are achieved, the Rollout Strategy moves to ADAPTIVE.
+*/
+func ApplyWarmupOnTime(state mm_pubsub.RolloutState, config mm_pubsub.RSConfiguration, flows []Flow, phaseStartedAt time.Time, now time.Time) (mm_pubsub.RolloutState, bool) {
+	// If the RS is not in the WARMUP status or the Warmup configuration is not base on Time rules, skip it
+	if state != mm_pubsub.RolloutStateWarmup || config.Warmup == nil || config.Warmup.IntervalMins == nil {
+		return state, false
+	}
+	// If there are no active Flows, mark the RS as completed
+	if len(flows) == 0 {
+		return mm_pubsub.RolloutStateCompleted, true
+	}
+	// From now, check how many minutes are missing to reach the target (start of WARMUP + Interval Mins)
+	missingMinutes := int64(math.Round(phaseStartedAt.Add(time.Duration(*config.Warmup.IntervalMins * int64(time.Minute))).Sub(now).Minutes()))
+	// Specific case, should never happen, force to 0, so move automatically to ADAPTIVE
+	if missingMinutes < 0 {
+		missingMinutes = 0
+	}
+	// If all flows have achieved their goal, we can move to the next state
+	elapsedMinutes := max(*config.Warmup.IntervalMins-missingMinutes, 0)
+	if applyWarmupGoals(*config.Warmup, flows, elapsedMinutes, *config.Warmup.IntervalMins) {
+		return mm_pubsub.RolloutStateAdaptive, true
+	}
+	return state, true
+}
+
+/*
+Update the PCT of each Flow toward its Warmup goal, following the Warmup curve. Flows without an explicit goal split evenly the
+remaining traffic. It returns true if all Flows have achieved their goal.
+*/
+func applyWarmupGoals(warmup mm_pubsub.RsWarmupPhase, flows []Flow, currentSteps int64, targetSteps int64) bool {
+	// Representation of Warmup goal (FlowID --> Pct Goal)
+	indexedGoals := map[string]float64{}
+	for _, goal := range warmup.Goals {
+		indexedGoals[goal.FlowID.String()] = goal.FinalServePct
+	}
+	// Indicates if all Flows have achieved their goal
+	var flowGoalAchieved bool = true
+	var totalPctReservedForGoals float64 = 0
+	// List of Flows without an explicit Warmup goal
+	activeFlowsWithoutGoalIndexes := []int{}
+	for i := range flows {
+		// Per each flow update PCT and check if there is an explicit Warmup goal achieved
+		pctGoal, ok := indexedGoals[flows[i].ID.String()]
+		if !ok {
+			// No explicit Warmup goal, add to the list
+			activeFlowsWithoutGoalIndexes = append(activeFlowsWithoutGoalIndexes, i)
+			continue
+		}
+		flows[i].CurrentServePct = calculateNewServePct(warmup, flows[i].CurrentServePct, pctGoal, currentSteps, targetSteps)
+		totalPctReservedForGoals += pctGoal
+		// Check if the goal has been achieved
+		if flows[i].CurrentServePct != pctGoal {
+			flowGoalAchieved = false
+		}
+	}
+	// Now that we updated all Flows with an explicit Warmup goal, proceed with others
+	remainingPctPerFlow := (100.0 - totalPctReservedForGoals) / float64(len(activeFlowsWithoutGoalIndexes))
+	for _, i := range activeFlowsWithoutGoalIndexes {
+		flows[i].CurrentServePct = calculateNewServePct(warmup, flows[i].CurrentServePct, remainingPctPerFlow, currentSteps, targetSteps)
+		if flows[i].CurrentServePct != remainingPctPerFlow {
+			flowGoalAchieved = false
+		}
+	}
+	return flowGoalAchieved
+}
+
+/*
+ApplyEscape checks the Escape rules against the Flow statistics. If a Flow matches its rule (based on min
+number of feedback and score, or on its guardrail conditions), the Rollout Strategy moves to ESCAPED and the
+rollback PCTs are applied. It also returns the rule that fired, with the matching guardrails.
+When a score aggregation is configured, the AVG_SCORE guardrail uses the aggregated summaries, while the
+min number of feedback and the other guardrails always use the statistics since the start of the rollout.
+*/
+func ApplyEscape(state mm_pubsub.RolloutState, config mm_pubsub.RSConfiguration, flows []Flow, statistics []FlowStatistics, summaries []FeedbackSummary, windows []FeedbackWindow) (mm_pubsub.RolloutState, *mm_pubsub.RsEscapeTrigger, bool) {
+	// If the RS is not in the WARMUP or ADAPTIVE status or the Escape configuration is not defined, skip it
+	if (state != mm_pubsub.RolloutStateWarmup && state != mm_pubsub.RolloutStateAdaptive) || config.Escape == nil {
+		return state, nil, false
+	}
+	// Representation of Escape rules (FlowID --> Escape Rule)
+	indexedRules := map[string]mm_pubsub.RsEscapeRule{}
+	for _, rule := range config.Escape.Rules {
+		indexedRules[rule.FlowID.String()] = rule
+	}
+	indexedStatistics := indexStatistics(statistics)
+	var indexedScores map[string]FeedbackSummary
+	if config.ScoreAggregation != nil {
+		indexedScores = indexFeedbackSummaries(summaries)
+	}
+	for i := range flows {
+		// Per each flow check if there is an explicit Rule for Escape and a Flow Statistics
+		rule, ok := indexedRules[flows[i].ID.String()]
+		if !ok {
+			continue
+		}
+		if _, ok := indexedStatistics[flows[i].ID.String()]; !ok {
+			continue
+		}
+		// Check if the Escape rule matches
+		if matched, guardrails := evaluateEscapeCondition(rule, escapeRuleCondition(rule), indexedStatistics, indexedScores, windows); matched {
+			applyRollback(rule, flows)
+			return mm_pubsub.RolloutStateEscaped, &mm_pubsub.RsEscapeTrigger{FlowID: rule.FlowID, Guardrails: guardrails}, true
+		}
+	}
+	return state, nil, true
+}
+
+/*
+ApplyForcedEscape applies the rollback PCTs of the first Flow with an Escape rule, when the Rollout
+Strategy is forced to escape.
+*/
+func ApplyForcedEscape(config mm_pubsub.RSConfiguration, flows []Flow) bool {
+	// If the Escape configuration is not defined, skip it
+	if config.Escape == nil {
+		return false
+	}
+	// Representation of Escape rules (FlowID --> Escape Rule)
+	indexedRules := map[string]mm_pubsub.RsEscapeRule{}
+	for _, rule := range config.Escape.Rules {
+		indexedRules[rule.FlowID.String()] = rule
+	}
+	for i := range flows {
+		// Per each flow check if there is an explicit Rule for Escape
+		if rule, ok := indexedRules[flows[i].ID.String()]; ok {
+			applyRollback(rule, flows)
+			break
+		}
+	}
+	return true
+}
+
+/*
+Adapt all Flows to the Rollback PCTs of the rule. Flows without a Rollback PCT are set to 0
+*/
+func applyRollback(rule mm_pubsub.RsEscapeRule, flows []Flow) {
+	// Representation of Rollback rules (FlowID --> Rollback)
+	indexedRollback := map[string]mm_pubsub.RsEscapeRollback{}
+	for _, rb := range rule.Rollback {
+		indexedRollback[rb.FlowID.String()] = rb
+	}
+	for i := range flows {
+		if rb, ok := indexedRollback[flows[i].ID.String()]; ok {
+			flows[i].CurrentServePct = rb.FinalServePct
+		} else {
+			flows[i].CurrentServePct = 0
+		}
+	}
+}
+
+/*
+ApplyForcedCompleted puts the forced Flow to 100% and all others to 0%, when the Rollout Strategy
+is forced to complete.
+*/
+func ApplyForcedCompleted(config mm_pubsub.RSConfiguration, flows []Flow) bool {
+	// If the completed Flow is not defined, skip it
+	if config.StateConfigurations.CompletedFlowID == nil {
+		return false
+	}
+	forcedFlowID := *config.StateConfigurations.CompletedFlowID
+	for i := range flows {
+		if flows[i].ID == forcedFlowID {
+			flows[i].CurrentServePct = 100
+		} else {
+			flows[i].CurrentServePct = 0
+		}
+	}
+	return true
+}
+
+/*
+IsAdaptiveIntervalElapsed returns true when the minutes elapsed since the start of the ADAPTIVE phase
+are a multiple of the configured interval, so the Adaptive phase has to be evaluated.
+*/
+func IsAdaptiveIntervalElapsed(config mm_pubsub.RSConfiguration, phaseStartedAt time.Time, now time.Time) bool {
+	elaspedMinutes := int64(math.Round(now.Sub(phaseStartedAt).Minutes()))
+	return elaspedMinutes%config.Adaptive.IntervalMins == 0
+}
+
+/*
+ApplyAdaptive shifts traffic between Flows using the configured algorithm, once all Flows have the minimum
+number of feedback. When one Flow serves 100% of traffic the Rollout Strategy moves to COMPLETED, unless a
+minimum confidence is required and the feedback summaries do not prove the winner is significantly better.
+When a score aggregation is configured, the Flows are ranked on the aggregated summaries, while the min number
+of feedback is always checked on the statistics since the start of the rollout.
+The random source is used by the algorithms sampling from the score distributions, so runs can be reproduced.
+*/
+func ApplyAdaptive(state mm_pubsub.RolloutState, config mm_pubsub.RSConfiguration, flows []Flow, statistics []FlowStatistics, summaries []FeedbackSummary, random *rand.Rand) (mm_pubsub.RolloutState, bool) {
+	if state != mm_pubsub.RolloutStateAdaptive {
+		return state, false
+	}
+	// If there are no active Flows, mark the RS as completed
+	if len(flows) == 0 {
+		return mm_pubsub.RolloutStateCompleted, true
+	}
+	// Check for each flow if it has at least the number of needed feeedback to start the adaptive phase
+	indexedStatistics := indexStatistics(statistics)
+	for i := range flows {
+		if stat, ok := indexedStatistics[flows[i].ID.String()]; ok {
+			if stat.TotFeedback < config.Adaptive.MinFeedback {
+				return state, false
+			}
+		}
+	}
+	// Keep the current serve PCTs, to restore them in case the completion is not allowed yet
+	previousServePcts := map[string]float64{}
+	for i := range flows {
+		previousServePcts[flows[i].ID.String()] = flows[i].CurrentServePct
+	}
+	// Shift traffic between Flows based on the configured algorithm
+	if config.ScoreAggregation != nil {
+		indexedStatistics = indexStatistics(withAggregatedScores(statistics, summaries))
+	}
+	allocator := newAdaptiveAllocator(config.Adaptive, summaries, random)
+	flowReachedMaxPct := allocator.allocate(flows, indexedStatistics)
+	// If required, block the completion until the winner is significantly better than other Flows
+	if flowReachedMaxPct && config.Adaptive.MinConfidence != nil {
+		if calculateWinnerConfidence(flows, summaries) < *config.Adaptive.MinConfidence {
+			for i := range flows {
+				flows[i].CurrentServePct = previousServePcts[flows[i].ID.String()]
+			}
+			flowReachedMaxPct = false
+		}
+	}
+	// If the Adaptive phase achieved its goal, move to COMPLETED
+	if flowReachedMaxPct {
+		return mm_pubsub.RolloutStateCompleted, true
+	}
+	return state, true
+}
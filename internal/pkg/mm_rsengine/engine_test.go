@@ -0,0 +1,83 @@
+package mm_rsengine
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+)
+
+func TestApplyAdaptiveMinConfidence(t *testing.T) {
+	statistics := []FlowStatistics{
+		{FlowID: testFlowA, TotFeedback: 1000, AvgScore: 4.5},
+		{FlowID: testFlowB, TotFeedback: 1000, AvgScore: 2},
+	}
+	tests := []struct {
+		name          string
+		minConfidence *float64
+		summaries     []FeedbackSummary
+		wantState     mm_pubsub.RolloutState
+		wantPctA      float64
+		wantPctB      float64
+	}{
+		{
+			name:      "completes without a min confidence",
+			wantState: mm_pubsub.RolloutStateCompleted,
+			wantPctA:  100,
+			wantPctB:  0,
+		},
+		{
+			name:          "completes when the winner is significantly better",
+			minConfidence: mm_utils.Float64Ptr(0.95),
+			summaries: []FeedbackSummary{
+				{FlowID: testFlowA, TotFeedback: 1000, AvgScore: 4.5, VarScore: 1},
+				{FlowID: testFlowB, TotFeedback: 1000, AvgScore: 2, VarScore: 1},
+			},
+			wantState: mm_pubsub.RolloutStateCompleted,
+			wantPctA:  100,
+			wantPctB:  0,
+		},
+		{
+			name:          "keeps the previous allocation when the winner is not significant",
+			minConfidence: mm_utils.Float64Ptr(0.95),
+			summaries: []FeedbackSummary{
+				{FlowID: testFlowA, TotFeedback: 1, AvgScore: 4.5},
+				{FlowID: testFlowB, TotFeedback: 1, AvgScore: 2},
+			},
+			wantState: mm_pubsub.RolloutStateAdaptive,
+			wantPctA:  95,
+			wantPctB:  5,
+		},
+		{
+			name:          "keeps the previous allocation without feedback summaries",
+			minConfidence: mm_utils.Float64Ptr(0.95),
+			wantState:     mm_pubsub.RolloutStateAdaptive,
+			wantPctA:      95,
+			wantPctB:      5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := mm_pubsub.RSConfiguration{
+				Adaptive: mm_pubsub.RsAdaptivePhase{
+					Algorithm:     mm_pubsub.RsAdaptiveAlgorithmGreedy,
+					MaxStepPct:    10,
+					MinConfidence: tt.minConfidence,
+				},
+			}
+			flows := []Flow{{ID: testFlowA, CurrentServePct: 95}, {ID: testFlowB, CurrentServePct: 5}}
+			state, evaluated := ApplyAdaptive(mm_pubsub.RolloutStateAdaptive, config, flows, statistics, tt.summaries, rand.New(rand.NewPCG(1, 2)))
+			if !evaluated {
+				t.Fatalf("adaptive phase not evaluated")
+			}
+			if state != tt.wantState {
+				t.Fatalf("unexpected state: got %v, want %v", state, tt.wantState)
+			}
+			pcts := servePcts(flows)
+			if pcts[testFlowA] != tt.wantPctA || pcts[testFlowB] != tt.wantPctB {
+				t.Fatalf("unexpected serve PCTs: got %v, want %v and %v", pcts, tt.wantPctA, tt.wantPctB)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package mm_rsengine
+
+import (
+	"github.com/google/uuid"
+)
+
+/*
+Flow is an active Flow of the Use Case with the percentage of traffic it is serving.
+*/
+type Flow struct {
+	ID              uuid.UUID
+	CurrentServePct float64
+}
+
+/*
+FlowStatistics are the aggregated statistics of a Flow used by the engine.
+*/
+type FlowStatistics struct {
+	FlowID             uuid.UUID
+	TotSessionRequests int64
+	TotFeedback        int64
+	AvgScore           float64
+	TotOneStarFeedback int64
+	TotErrors          int64
+	TotAbandons        int64
+}
+
+/*
+FeedbackWindow summarizes the feedback received by a Flow in the last minutes, to detect sudden score drops.
+*/
+type FeedbackWindow struct {
+	FlowID      uuid.UUID
+	WindowMins  int64
+	TotFeedback int64
+	AvgScore    float64
+}
+
+/*
+FeedbackSummary summarizes the feedback scores of a Flow, to compare Flows with statistical tests.
+*/
+type FeedbackSummary struct {
+	FlowID      uuid.UUID
+	TotFeedback int64
+	AvgScore    float64
+	VarScore    float64
+}
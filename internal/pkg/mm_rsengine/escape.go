@@ -0,0 +1,179 @@
+package mm_rsengine
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+)
+
+/*
+EscapeWindowsMins returns the distinct time windows used by the SCORE_DROP guardrails of the Escape rules,
+so the feedback of the Flows can be summarized on each of them before running the Escape.
+*/
+func EscapeWindowsMins(config mm_pubsub.RSConfiguration) []int64 {
+	windowsMins := []int64{}
+	if config.Escape == nil {
+		return windowsMins
+	}
+	seen := map[int64]bool{}
+	var collect func(condition mm_pubsub.RsEscapeCondition)
+	collect = func(condition mm_pubsub.RsEscapeCondition) {
+		if condition.Type == mm_pubsub.RsEscapeConditionScoreDrop && condition.WindowMins != nil && !seen[*condition.WindowMins] {
+			seen[*condition.WindowMins] = true
+			windowsMins = append(windowsMins, *condition.WindowMins)
+		}
+		for _, nested := range condition.Conditions {
+			collect(nested)
+		}
+	}
+	for _, rule := range config.Escape.Rules {
+		if rule.Condition != nil {
+			collect(*rule.Condition)
+		}
+	}
+	return windowsMins
+}
+
+/*
+Return the condition of the Escape rule. Rules without conditions match on the average score only,
+as they have always done.
+*/
+func escapeRuleCondition(rule mm_pubsub.RsEscapeRule) mm_pubsub.RsEscapeCondition {
+	if rule.Condition != nil {
+		return *rule.Condition
+	}
+	lowerScore := rule.LowerScore
+	return mm_pubsub.RsEscapeCondition{
+		Type:      mm_pubsub.RsEscapeConditionAvgScore,
+		Threshold: &lowerScore,
+	}
+}
+
+/*
+Evaluate the condition on the Flow of the rule. It returns true if the condition matches, together with the
+guardrails that made it match.
+*/
+func evaluateEscapeCondition(rule mm_pubsub.RsEscapeRule, condition mm_pubsub.RsEscapeCondition, indexedStatistics map[string]FlowStatistics, indexedScores map[string]FeedbackSummary, windows []FeedbackWindow) (bool, []mm_pubsub.RsEscapeTriggerGuardrail) {
+	switch condition.Type {
+	case mm_pubsub.RsEscapeConditionAnd:
+		guardrails := []mm_pubsub.RsEscapeTriggerGuardrail{}
+		for _, nested := range condition.Conditions {
+			matched, nestedGuardrails := evaluateEscapeCondition(rule, nested, indexedStatistics, indexedScores, windows)
+			if !matched {
+				return false, nil
+			}
+			guardrails = append(guardrails, nestedGuardrails...)
+		}
+		return len(condition.Conditions) > 0, guardrails
+	case mm_pubsub.RsEscapeConditionOr:
+		for _, nested := range condition.Conditions {
+			if matched, nestedGuardrails := evaluateEscapeCondition(rule, nested, indexedStatistics, indexedScores, windows); matched {
+				return true, nestedGuardrails
+			}
+		}
+		return false, nil
+	default:
+		value, matched := evaluateEscapeGuardrail(rule, condition, indexedStatistics, indexedScores, windows)
+		if !matched {
+			return false, nil
+		}
+		return true, []mm_pubsub.RsEscapeTriggerGuardrail{{
+			Type:           condition.Type,
+			Threshold:      *condition.Threshold,
+			Value:          mm_utils.RoundTo2Decimals(value),
+			BaselineFlowID: condition.BaselineFlowID,
+			WindowMins:     condition.WindowMins,
+		}}
+	}
+}
+
+/*
+Evaluate a single guardrail on the Flow of the rule. It returns the observed value and true if the guardrail
+matches. Guardrails on feedback need at least the min number of feedback of the rule, while guardrails on
+client outcomes need at least the min number of session requests. The aggregated scores, if any, are used
+only by the AVG_SCORE guardrail: the other ones compare counts and averages of the same statistics.
+*/
+func evaluateEscapeGuardrail(rule mm_pubsub.RsEscapeRule, condition mm_pubsub.RsEscapeCondition, indexedStatistics map[string]FlowStatistics, indexedScores map[string]FeedbackSummary, windows []FeedbackWindow) (float64, bool) {
+	if condition.Threshold == nil {
+		return 0, false
+	}
+	threshold := *condition.Threshold
+	stat := indexedStatistics[rule.FlowID.String()]
+	switch condition.Type {
+	case mm_pubsub.RsEscapeConditionAvgScore:
+		// Average score lower or equal than the threshold, on the aggregated feedback if configured
+		if stat.TotFeedback < rule.MinFeedback {
+			return 0, false
+		}
+		if indexedScores != nil {
+			score, ok := indexedScores[rule.FlowID.String()]
+			if !ok || score.TotFeedback == 0 {
+				return 0, false
+			}
+			avgScore := mm_utils.RoundTo2Decimals(score.AvgScore)
+			return avgScore, avgScore <= threshold
+		}
+		return stat.AvgScore, stat.AvgScore <= threshold
+	case mm_pubsub.RsEscapeConditionBaselineDegradation:
+		// Average score lower than the one of the baseline Flow by at least the threshold (in PCT)
+		if condition.BaselineFlowID == nil || stat.TotFeedback < rule.MinFeedback {
+			return 0, false
+		}
+		baseline, ok := indexedStatistics[condition.BaselineFlowID.String()]
+		if !ok || baseline.TotFeedback < rule.MinFeedback || baseline.AvgScore <= 0 {
+			return 0, false
+		}
+		degradationPct := (baseline.AvgScore - stat.AvgScore) / baseline.AvgScore * 100
+		return degradationPct, degradationPct >= threshold
+	case mm_pubsub.RsEscapeConditionOneStarShare:
+		// Share (in PCT) of 1-star feedback greater or equal than the threshold
+		if stat.TotFeedback == 0 || stat.TotFeedback < rule.MinFeedback {
+			return 0, false
+		}
+		sharePct := float64(stat.TotOneStarFeedback) / float64(stat.TotFeedback) * 100
+		return sharePct, sharePct >= threshold
+	case mm_pubsub.RsEscapeConditionScoreDrop:
+		// Average score in the window lower than the one before the window by at least the threshold
+		if condition.WindowMins == nil {
+			return 0, false
+		}
+		window, ok := findFeedbackWindow(windows, rule.FlowID, *condition.WindowMins)
+		if !ok || window.TotFeedback == 0 || window.TotFeedback < rule.MinFeedback || stat.TotFeedback <= window.TotFeedback {
+			return 0, false
+		}
+		beforeAvgScore := (stat.AvgScore*float64(stat.TotFeedback) - window.AvgScore*float64(window.TotFeedback)) / float64(stat.TotFeedback-window.TotFeedback)
+		drop := beforeAvgScore - window.AvgScore
+		return drop, drop >= threshold
+	case mm_pubsub.RsEscapeConditionErrorRate:
+		// Share (in PCT) of sessions reported in error greater or equal than the threshold
+		if stat.TotSessionRequests == 0 || stat.TotSessionRequests < rule.MinSessionRequests {
+			return 0, false
+		}
+		ratePct := float64(stat.TotErrors) / float64(stat.TotSessionRequests) * 100
+		return ratePct, ratePct >= threshold
+	case mm_pubsub.RsEscapeConditionAbandonRate:
+		// Share (in PCT) of sessions reported as abandoned greater or equal than the threshold
+		if stat.TotSessionRequests == 0 || stat.TotSessionRequests < rule.MinSessionRequests {
+			return 0, false
+		}
+		ratePct := float64(stat.TotAbandons) / float64(stat.TotSessionRequests) * 100
+		return ratePct, ratePct >= threshold
+	}
+	return 0, false
+}
+
+func findFeedbackWindow(windows []FeedbackWindow, flowID uuid.UUID, windowMins int64) (FeedbackWindow, bool) {
+	for _, window := range windows {
+		if window.FlowID == flowID && window.WindowMins == windowMins {
+			return window, true
+		}
+	}
+	return FeedbackWindow{}, false
+}
+
+/*
+IsOneStarScore returns true if the feedback score rounds to 1 star.
+*/
+func IsOneStarScore(score float64) bool {
+	return score < MinFeedbackScore+0.5
+}
@@ -0,0 +1,133 @@
+package mm_rsengine
+
+import (
+	"testing"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+)
+
+func TestApplyEscapeConditions(t *testing.T) {
+	avgScore := mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionAvgScore, Threshold: mm_utils.Float64Ptr(2)}
+	errorRate := mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionErrorRate, Threshold: mm_utils.Float64Ptr(10)}
+	oneStarShare := mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionOneStarShare, Threshold: mm_utils.Float64Ptr(50)}
+	lowScore := FlowStatistics{FlowID: testFlowA, TotFeedback: 10, AvgScore: 1.5, TotOneStarFeedback: 2, TotSessionRequests: 100, TotErrors: 20}
+	fewErrors := FlowStatistics{FlowID: testFlowA, TotFeedback: 10, AvgScore: 1.5, TotOneStarFeedback: 2, TotSessionRequests: 100, TotErrors: 5}
+	tests := []struct {
+		name           string
+		condition      *mm_pubsub.RsEscapeCondition
+		minFeedback    int64
+		statistics     FlowStatistics
+		aggregation    *mm_pubsub.RsScoreAggregation
+		summaries      []FeedbackSummary
+		wantEscaped    bool
+		wantGuardrails []mm_pubsub.RsEscapeConditionType
+	}{
+		{
+			name:           "rule without condition matches on the lower score",
+			statistics:     lowScore,
+			wantEscaped:    true,
+			wantGuardrails: []mm_pubsub.RsEscapeConditionType{mm_pubsub.RsEscapeConditionAvgScore},
+		},
+		{
+			name:        "rule without condition needs the min number of feedback",
+			minFeedback: 20,
+			statistics:  lowScore,
+		},
+		{
+			name:           "AND matches when all the conditions match",
+			condition:      &mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionAnd, Conditions: []mm_pubsub.RsEscapeCondition{avgScore, errorRate}},
+			statistics:     lowScore,
+			wantEscaped:    true,
+			wantGuardrails: []mm_pubsub.RsEscapeConditionType{mm_pubsub.RsEscapeConditionAvgScore, mm_pubsub.RsEscapeConditionErrorRate},
+		},
+		{
+			name:       "AND does not match when a condition does not match",
+			condition:  &mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionAnd, Conditions: []mm_pubsub.RsEscapeCondition{avgScore, errorRate}},
+			statistics: fewErrors,
+		},
+		{
+			name:       "AND without conditions does not match",
+			condition:  &mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionAnd},
+			statistics: lowScore,
+		},
+		{
+			name:           "OR matches on the first condition that matches",
+			condition:      &mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionOr, Conditions: []mm_pubsub.RsEscapeCondition{errorRate, avgScore}},
+			statistics:     fewErrors,
+			wantEscaped:    true,
+			wantGuardrails: []mm_pubsub.RsEscapeConditionType{mm_pubsub.RsEscapeConditionAvgScore},
+		},
+		{
+			name:       "OR does not match when no condition matches",
+			condition:  &mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionOr, Conditions: []mm_pubsub.RsEscapeCondition{errorRate, oneStarShare}},
+			statistics: fewErrors,
+		},
+		{
+			name: "nested conditions",
+			condition: &mm_pubsub.RsEscapeCondition{Type: mm_pubsub.RsEscapeConditionOr, Conditions: []mm_pubsub.RsEscapeCondition{
+				oneStarShare,
+				{Type: mm_pubsub.RsEscapeConditionAnd, Conditions: []mm_pubsub.RsEscapeCondition{avgScore, errorRate}},
+			}},
+			statistics:     lowScore,
+			wantEscaped:    true,
+			wantGuardrails: []mm_pubsub.RsEscapeConditionType{mm_pubsub.RsEscapeConditionAvgScore, mm_pubsub.RsEscapeConditionErrorRate},
+		},
+		{
+			name:        "average score uses the aggregated scores if configured",
+			condition:   &avgScore,
+			statistics:  lowScore,
+			aggregation: &mm_pubsub.RsScoreAggregation{Mode: mm_pubsub.RsScoreAggregationWindow, WindowHours: mm_utils.Int64Ptr(24)},
+			summaries:   []FeedbackSummary{{FlowID: testFlowA, TotFeedback: 5, AvgScore: 4}},
+		},
+		{
+			name:        "average score without aggregated feedback does not match",
+			condition:   &avgScore,
+			statistics:  lowScore,
+			aggregation: &mm_pubsub.RsScoreAggregation{Mode: mm_pubsub.RsScoreAggregationWindow, WindowHours: mm_utils.Int64Ptr(24)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := mm_pubsub.RSConfiguration{
+				Escape: &mm_pubsub.RsEscapePhase{Rules: []mm_pubsub.RsEscapeRule{{
+					FlowID:      testFlowA,
+					MinFeedback: tt.minFeedback,
+					LowerScore:  2,
+					Condition:   tt.condition,
+					Rollback:    []mm_pubsub.RsEscapeRollback{{FlowID: testFlowB, FinalServePct: 100}},
+				}}},
+				ScoreAggregation: tt.aggregation,
+			}
+			flows := []Flow{{ID: testFlowA, CurrentServePct: 50}, {ID: testFlowB, CurrentServePct: 50}}
+			statistics := []FlowStatistics{tt.statistics, {FlowID: testFlowB, TotFeedback: 10, AvgScore: 4}}
+			state, trigger, evaluated := ApplyEscape(mm_pubsub.RolloutStateAdaptive, config, flows, statistics, tt.summaries, nil)
+			if !evaluated {
+				t.Fatalf("escape not evaluated")
+			}
+			if !tt.wantEscaped {
+				if state != mm_pubsub.RolloutStateAdaptive || trigger != nil {
+					t.Fatalf("unexpected escape: state %v, trigger %+v", state, trigger)
+				}
+				if flows[0].CurrentServePct != 50 || flows[1].CurrentServePct != 50 {
+					t.Fatalf("unexpected rollback: %+v", flows)
+				}
+				return
+			}
+			if state != mm_pubsub.RolloutStateEscaped || trigger == nil {
+				t.Fatalf("expected escape: state %v, trigger %+v", state, trigger)
+			}
+			if flows[0].CurrentServePct != 0 || flows[1].CurrentServePct != 100 {
+				t.Fatalf("rollback not applied: %+v", flows)
+			}
+			if len(trigger.Guardrails) != len(tt.wantGuardrails) {
+				t.Fatalf("unexpected guardrails: got %+v, want %v", trigger.Guardrails, tt.wantGuardrails)
+			}
+			for i, guardrail := range trigger.Guardrails {
+				if guardrail.Type != tt.wantGuardrails[i] {
+					t.Fatalf("unexpected guardrails: got %+v, want %v", trigger.Guardrails, tt.wantGuardrails)
+				}
+			}
+		})
+	}
+}
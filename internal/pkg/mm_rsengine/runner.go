@@ -0,0 +1,297 @@
+package mm_rsengine
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+)
+
+/*
+Statistics collected for a Flow during a run in virtual time
+*/
+type runFlowStatistics struct {
+	sessionRequests int64
+	feedback        int64
+	oneStarFeedback int64
+	sumScore        float64
+	sumSquaredScore float64
+	// Feedback per minute, to summarize the feedback received in a time window
+	feedbackBuckets []runFeedbackBucket
+}
+
+type runFeedbackBucket struct {
+	minute          int64
+	feedback        int64
+	sumScore        float64
+	sumSquaredScore float64
+}
+
+/*
+rolloutRun drives the engine in virtual time, from the WARMUP phase, the same way the live engine does:
+it reacts to new session requests and feedback, and it is ticked every minute. It keeps track of the
+serve PCTs every time they change and of the state transitions. The random source is shared with the
+caller, so the whole run is reproducible from its seed.
+*/
+type rolloutRun struct {
+	config         mm_pubsub.RSConfiguration
+	state          mm_pubsub.RolloutState
+	start          time.Time
+	phaseStartedAt time.Time
+	flows          []Flow
+	random         *rand.Rand
+	statistics     map[uuid.UUID]*runFlowStatistics
+	hasNewRequests bool
+	hasNewFeedback bool
+	result         SimulationResult
+}
+
+func newRolloutRun(config mm_pubsub.RSConfiguration, flows []Flow, start time.Time, random *rand.Rand) *rolloutRun {
+	r := &rolloutRun{
+		config:         config,
+		state:          mm_pubsub.RolloutStateWarmup,
+		start:          start,
+		phaseStartedAt: start,
+		flows:          flows,
+		random:         random,
+		statistics:     map[uuid.UUID]*runFlowStatistics{},
+	}
+	for _, flow := range flows {
+		r.statistics[flow.ID] = &runFlowStatistics{}
+	}
+	r.result = SimulationResult{
+		Transitions: []SimulationTransition{{Minute: 0, FromState: mm_pubsub.RolloutStateInit, ToState: r.state}},
+		Series:      []SimulationPoint{r.newPoint(0)},
+	}
+	return r
+}
+
+/*
+Return the current serve PCT of the Flow
+*/
+func (r *rolloutRun) servePct(flowID uuid.UUID) float64 {
+	for _, flow := range r.flows {
+		if flow.ID == flowID {
+			return flow.CurrentServePct
+		}
+	}
+	return 0
+}
+
+func (r *rolloutRun) addSessionRequest(flowID uuid.UUID) {
+	if stat, ok := r.statistics[flowID]; ok {
+		stat.sessionRequests++
+		r.hasNewRequests = true
+	}
+}
+
+func (r *rolloutRun) addFeedback(flowID uuid.UUID, score float64, createdAt time.Time) {
+	if stat, ok := r.statistics[flowID]; ok {
+		stat.feedback++
+		stat.sumScore += score
+		stat.sumSquaredScore += score * score
+		if IsOneStarScore(score) {
+			stat.oneStarFeedback++
+		}
+		minute := r.minute(createdAt)
+		if last := len(stat.feedbackBuckets) - 1; last >= 0 && stat.feedbackBuckets[last].minute == minute {
+			stat.feedbackBuckets[last].feedback++
+			stat.feedbackBuckets[last].sumScore += score
+			stat.feedbackBuckets[last].sumSquaredScore += score * score
+		} else {
+			stat.feedbackBuckets = append(stat.feedbackBuckets, runFeedbackBucket{minute: minute, feedback: 1, sumScore: score, sumSquaredScore: score * score})
+		}
+		r.hasNewFeedback = true
+	}
+}
+
+/*
+Run the engine on the traffic and feedback collected since the previous step, then tick it.
+It returns false once the Rollout Strategy leaves the WARMUP and ADAPTIVE phases.
+*/
+func (r *rolloutRun) step(now time.Time) bool {
+	minute := r.minute(now)
+	previousState := r.state
+	previousPoint := r.newPoint(minute)
+	// React to traffic and feedback as the live engine does on statistics updates
+	if r.hasNewRequests {
+		r.state, _ = ApplyWarmupOnTraffic(r.state, r.config, r.flows, r.flowStatistics())
+	}
+	var escapeTrigger *mm_pubsub.RsEscapeTrigger
+	if r.hasNewFeedback {
+		r.state, escapeTrigger, _ = ApplyEscape(r.state, r.config, r.flows, r.flowStatistics(), r.engineFeedbackSummaries(now), r.feedbackWindows(minute))
+	}
+	r.hasNewRequests = false
+	r.hasNewFeedback = false
+	// Tick the engine, if the state did not change in this step
+	if r.state == previousState {
+		switch r.state {
+		case mm_pubsub.RolloutStateWarmup:
+			r.state, _ = ApplyWarmupOnTime(r.state, r.config, r.flows, r.phaseStartedAt, now)
+		case mm_pubsub.RolloutStateAdaptive:
+			if IsAdaptiveIntervalElapsed(r.config, r.phaseStartedAt, now) && IsWithinActiveWindows(r.config.Adaptive, now) {
+				r.state, _ = ApplyAdaptive(r.state, r.config, r.flows, r.flowStatistics(), r.engineFeedbackSummaries(now), r.random)
+			}
+		}
+	}
+	// Track changes
+	if r.state != previousState {
+		r.phaseStartedAt = now
+		r.result.Transitions = append(r.result.Transitions, SimulationTransition{Minute: minute, FromState: previousState, ToState: r.state, EscapeTrigger: escapeTrigger})
+	}
+	if point := r.newPoint(minute); !isSameSimulationPoint(previousPoint, point) {
+		r.result.Series = append(r.result.Series, point)
+	}
+	return r.state == mm_pubsub.RolloutStateWarmup || r.state == mm_pubsub.RolloutStateAdaptive
+}
+
+func (r *rolloutRun) finish(now time.Time) SimulationResult {
+	r.result.FinalState = r.state
+	r.result.EndMinute = r.minute(now)
+	return r.result
+}
+
+func (r *rolloutRun) minute(now time.Time) int64 {
+	return int64(math.Round(now.Sub(r.start).Minutes()))
+}
+
+func (r *rolloutRun) newPoint(minute int64) SimulationPoint {
+	point := SimulationPoint{
+		Minute:       minute,
+		RolloutState: r.state,
+		Flows:        make([]SimulationFlowPct, len(r.flows)),
+	}
+	for i := range r.flows {
+		point.Flows[i] = SimulationFlowPct{
+			FlowID:          r.flows[i].ID,
+			CurrentServePct: r.flows[i].CurrentServePct,
+		}
+	}
+	return point
+}
+
+func (r *rolloutRun) flowStatistics() []FlowStatistics {
+	result := make([]FlowStatistics, len(r.flows))
+	for i := range r.flows {
+		stat := r.statistics[r.flows[i].ID]
+		result[i] = FlowStatistics{
+			FlowID:             r.flows[i].ID,
+			TotSessionRequests: stat.sessionRequests,
+			TotFeedback:        stat.feedback,
+			AvgScore:           stat.avgScore(),
+			TotOneStarFeedback: stat.oneStarFeedback,
+		}
+	}
+	return result
+}
+
+func (r *rolloutRun) feedbackSummaries() []FeedbackSummary {
+	result := make([]FeedbackSummary, len(r.flows))
+	for i := range r.flows {
+		stat := r.statistics[r.flows[i].ID]
+		result[i] = FeedbackSummary{
+			FlowID:      r.flows[i].ID,
+			TotFeedback: stat.feedback,
+			AvgScore:    stat.sumScore / math.Max(float64(stat.feedback), 1),
+			VarScore:    stat.varScore(),
+		}
+	}
+	return result
+}
+
+/*
+Feedback summaries used by the engine: the feedback scores are aggregated as configured in the Rollout Strategy
+*/
+func (r *rolloutRun) engineFeedbackSummaries(now time.Time) []FeedbackSummary {
+	if r.config.ScoreAggregation == nil {
+		return r.feedbackSummaries()
+	}
+	return AggregateScores(*r.config.ScoreAggregation, r.scoreBuckets(), now)
+}
+
+/*
+Convert the feedback received per minute in score buckets
+*/
+func (r *rolloutRun) scoreBuckets() []ScoreBucket {
+	result := []ScoreBucket{}
+	for i := range r.flows {
+		for _, bucket := range r.statistics[r.flows[i].ID].feedbackBuckets {
+			result = append(result, ScoreBucket{
+				FlowID:          r.flows[i].ID,
+				StartedAt:       r.start.Add(time.Duration(bucket.minute) * time.Minute),
+				TotFeedback:     bucket.feedback,
+				SumScore:        bucket.sumScore,
+				SumSquaredScore: bucket.sumSquaredScore,
+			})
+		}
+	}
+	return result
+}
+
+/*
+Summarize the feedback received by each Flow in the time windows used by the Escape rules, up to the given minute
+*/
+func (r *rolloutRun) feedbackWindows(minute int64) []FeedbackWindow {
+	result := []FeedbackWindow{}
+	for _, windowMins := range EscapeWindowsMins(r.config) {
+		for i := range r.flows {
+			stat := r.statistics[r.flows[i].ID]
+			window := FeedbackWindow{FlowID: r.flows[i].ID, WindowMins: windowMins}
+			var sumScore float64 = 0
+			for j := len(stat.feedbackBuckets) - 1; j >= 0 && stat.feedbackBuckets[j].minute > minute-windowMins; j-- {
+				window.TotFeedback += stat.feedbackBuckets[j].feedback
+				sumScore += stat.feedbackBuckets[j].sumScore
+			}
+			if window.TotFeedback > 0 {
+				window.AvgScore = sumScore / float64(window.TotFeedback)
+			}
+			result = append(result, window)
+		}
+	}
+	return result
+}
+
+/*
+Check if two points have the same state and serve PCTs. Flows can be reordered by the engine,
+so they are compared by ID.
+*/
+func isSameSimulationPoint(a SimulationPoint, b SimulationPoint) bool {
+	if a.RolloutState != b.RolloutState {
+		return false
+	}
+	indexedPcts := map[uuid.UUID]float64{}
+	for _, flow := range a.Flows {
+		indexedPcts[flow.FlowID] = flow.CurrentServePct
+	}
+	for _, flow := range b.Flows {
+		if pct, ok := indexedPcts[flow.FlowID]; !ok || pct != flow.CurrentServePct {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Average score rounded to 2 decimals, as stored in Flow statistics
+*/
+func (s runFlowStatistics) avgScore() float64 {
+	if s.feedback == 0 {
+		return 0
+	}
+	return mm_utils.RoundTo2Decimals(s.sumScore / float64(s.feedback))
+}
+
+/*
+Sample variance of the scores
+*/
+func (s runFlowStatistics) varScore() float64 {
+	if s.feedback < 2 {
+		return 0
+	}
+	n := float64(s.feedback)
+	mean := s.sumScore / n
+	return math.Max((s.sumSquaredScore-n*mean*mean)/(n-1), 0)
+}
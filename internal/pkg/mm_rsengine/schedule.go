@@ -0,0 +1,77 @@
+package mm_rsengine
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the timezone database, so Active Windows work also where it is not installed
+	_ "time/tzdata"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+)
+
+/*
+ApplyScheduledStart starts the Rollout Strategy once its scheduled start time is reached. As for a manual
+start, it moves to WARMUP, or directly to ADAPTIVE if the Warmup configuration is not defined.
+*/
+func ApplyScheduledStart(state mm_pubsub.RolloutState, config mm_pubsub.RSConfiguration, now time.Time) (mm_pubsub.RolloutState, bool) {
+	// If the RS is not in the INIT status or the start is not scheduled yet, skip it
+	if state != mm_pubsub.RolloutStateInit || config.ScheduledStartAt == nil || now.Before(*config.ScheduledStartAt) {
+		return state, false
+	}
+	if config.Warmup == nil {
+		return mm_pubsub.RolloutStateAdaptive, true
+	}
+	return mm_pubsub.RolloutStateWarmup, true
+}
+
+/*
+IsWithinActiveWindows returns true if the Adaptive phase can be evaluated at the given time, based on the
+configured Active Windows. Without Active Windows, the Adaptive phase can be always evaluated.
+*/
+func IsWithinActiveWindows(adaptive mm_pubsub.RsAdaptivePhase, now time.Time) bool {
+	if adaptive.ActiveWindows == nil {
+		return true
+	}
+	location, err := time.LoadLocation(adaptive.ActiveWindows.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	localNow := now.In(location)
+	weekday := mm_pubsub.RsWeekday(strings.ToUpper(localNow.Weekday().String()))
+	minuteOfDay := localNow.Hour()*60 + localNow.Minute()
+	for _, window := range adaptive.ActiveWindows.Windows {
+		from, okFrom := parseMinuteOfDay(window.From)
+		to, okTo := parseMinuteOfDay(window.To)
+		if !okFrom || !okTo {
+			continue
+		}
+		for _, day := range window.Days {
+			// The start of the window is included, the end is excluded
+			if day == weekday && minuteOfDay >= from && minuteOfDay < to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+Convert a time of the day in the "HH:MM" format in the number of minutes since midnight ("24:00" is the end of the day)
+*/
+func parseMinuteOfDay(value string) (int, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return 0, false
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 24 {
+		return 0, false
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 || (hours == 24 && minutes != 0) {
+		return 0, false
+	}
+	return hours*60 + minutes, true
+}
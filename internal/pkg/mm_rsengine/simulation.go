@@ -0,0 +1,108 @@
+package mm_rsengine
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/google/uuid"
+)
+
+/*
+SimulationFlow is an active Flow in the simulation, with its initial serve PCT and the normal
+distribution of the feedback scores it receives.
+*/
+type SimulationFlow struct {
+	ID              uuid.UUID
+	InitialServePct float64
+	AvgScore        float64
+	StdDevScore     float64
+}
+
+/*
+SimulationParameters describes the synthetic traffic of the simulation.
+*/
+type SimulationParameters struct {
+	DurationMins          int64
+	SessionRequestsPerMin float64
+	FeedbackRate          float64
+	Seed                  uint64
+}
+
+type SimulationResult struct {
+	FinalState  mm_pubsub.RolloutState `json:"finalState"`
+	EndMinute   int64                  `json:"endMinute"`
+	Transitions []SimulationTransition `json:"transitions"`
+	Series      []SimulationPoint      `json:"series"`
+}
+
+type SimulationTransition struct {
+	Minute        int64                      `json:"minute"`
+	FromState     mm_pubsub.RolloutState     `json:"fromState"`
+	ToState       mm_pubsub.RolloutState     `json:"toState"`
+	EscapeTrigger *mm_pubsub.RsEscapeTrigger `json:"escapeTrigger"`
+}
+
+type SimulationPoint struct {
+	Minute       int64                  `json:"minute"`
+	RolloutState mm_pubsub.RolloutState `json:"rolloutState"`
+	Flows        []SimulationFlowPct    `json:"flows"`
+}
+
+type SimulationFlowPct struct {
+	FlowID          uuid.UUID `json:"flowId"`
+	CurrentServePct float64   `json:"currentServePct"`
+}
+
+/*
+Simulate runs the Rollout Strategy engine in virtual time, starting from the WARMUP phase, with synthetic
+traffic and feedback. Each minute, session requests are split between Flows based on their serve PCT and
+a share of them receives a feedback drawn from the score distribution of the Flow. The simulation stops at
+the end of the duration or once the Rollout Strategy leaves the WARMUP and ADAPTIVE phases.
+It returns the serve PCTs every time they change, and the state transitions.
+*/
+func Simulate(config mm_pubsub.RSConfiguration, simulationFlows []SimulationFlow, parameters SimulationParameters) SimulationResult {
+	random := rand.New(rand.NewPCG(parameters.Seed, parameters.Seed))
+	start := time.Unix(0, 0).UTC()
+	flows := make([]Flow, len(simulationFlows))
+	for i := range simulationFlows {
+		flows[i] = Flow{
+			ID:              simulationFlows[i].ID,
+			CurrentServePct: simulationFlows[i].InitialServePct,
+		}
+	}
+	run := newRolloutRun(config, flows, start, random)
+	// Fractional requests and feedback carried over to the next minute
+	var requestsCarry float64 = 0
+	flowRequestsCarry := make([]float64, len(simulationFlows))
+	flowFeedbackCarry := make([]float64, len(simulationFlows))
+	now := start
+	for minute := int64(1); minute <= parameters.DurationMins; minute++ {
+		now = start.Add(time.Duration(minute) * time.Minute)
+		// Generate the traffic of this minute and split it between Flows
+		requestsCarry += parameters.SessionRequestsPerMin
+		requests := math.Floor(requestsCarry)
+		requestsCarry -= requests
+		for i, simulationFlow := range simulationFlows {
+			flowRequestsCarry[i] += requests * run.servePct(simulationFlow.ID) / 100
+			flowRequests := math.Floor(flowRequestsCarry[i])
+			flowRequestsCarry[i] -= flowRequests
+			for range int64(flowRequests) {
+				run.addSessionRequest(simulationFlow.ID)
+			}
+			// A share of the session requests receives a feedback
+			flowFeedbackCarry[i] += flowRequests * parameters.FeedbackRate
+			flowFeedback := math.Floor(flowFeedbackCarry[i])
+			flowFeedbackCarry[i] -= flowFeedback
+			for range int64(flowFeedback) {
+				score := random.NormFloat64()*simulationFlow.StdDevScore + simulationFlow.AvgScore
+				run.addFeedback(simulationFlow.ID, math.Min(math.Max(score, MinFeedbackScore), MaxFeedbackScore), now)
+			}
+		}
+		if !run.step(now) {
+			break
+		}
+	}
+	return run.finish(now)
+}
@@ -0,0 +1,71 @@
+package mm_rsengine
+
+import (
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_stats"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+)
+
+func calculateNewServePct(warmup mm_pubsub.RsWarmupPhase, currentPct float64, targetPct float64, currentSteps int64, targetSteps int64) float64 {
+	// If target PCT of the Flow has been reach, we are fine
+	if currentPct == targetPct {
+		return targetPct
+	}
+	// If the total number of session requests on the Use Case is greater or equal to the target,
+	// we can force the current PCT to the target PCT
+	if currentSteps >= targetSteps {
+		return targetPct
+	}
+	// Otherwise let's calculate the delta to add/sub from the current PCT of the Flow based on how
+	// the Warmup curve progresses from the previous step to the current one, over what is missing.
+	// Using the previous step because when we calculate the delta, the current steps has been already
+	// incremented and we are reacting from that, it is not in combination.
+	previousProgress := warmupProgress(warmup, currentSteps-1, targetSteps)
+	if previousProgress >= 1 {
+		return targetPct
+	}
+	progress := warmupProgress(warmup, currentSteps, targetSteps)
+	delta := (targetPct - currentPct) * (progress - previousProgress) / (1 - previousProgress)
+	return mm_utils.RoundTo2Decimals(currentPct + delta)
+}
+
+/*
+Calculate how confident we are that the Flow serving 100% of traffic has better feedback scores than all
+the other active Flows, based on a two-sample test on their feedback.
+*/
+func calculateWinnerConfidence(flows []Flow, summaries []FeedbackSummary) float64 {
+	indexedSamples := map[string]mm_stats.Sample{}
+	for _, summary := range summaries {
+		indexedSamples[summary.FlowID.String()] = mm_stats.Sample{
+			Count:    summary.TotFeedback,
+			Mean:     summary.AvgScore,
+			Variance: summary.VarScore,
+		}
+	}
+	var winner mm_stats.Sample
+	others := []mm_stats.Sample{}
+	for i := range flows {
+		if flows[i].CurrentServePct == 100 {
+			winner = indexedSamples[flows[i].ID.String()]
+		} else {
+			others = append(others, indexedSamples[flows[i].ID.String()])
+		}
+	}
+	return mm_stats.Confidence(winner, others)
+}
+
+func indexStatistics(statistics []FlowStatistics) map[string]FlowStatistics {
+	indexedStatistics := map[string]FlowStatistics{}
+	for _, stat := range statistics {
+		indexedStatistics[stat.FlowID.String()] = stat
+	}
+	return indexedStatistics
+}
+
+func indexFeedbackSummaries(summaries []FeedbackSummary) map[string]FeedbackSummary {
+	indexedSummaries := map[string]FeedbackSummary{}
+	for _, summary := range summaries {
+		indexedSummaries[summary.FlowID.String()] = summary
+	}
+	return indexedSummaries
+}
@@ -0,0 +1,67 @@
+package mm_rsengine
+
+import (
+	"math"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+)
+
+/*
+Calculate how far (from 0 to 1) the Flows should be toward their Warmup goals after the given number of
+steps (minutes or session requests) over the Warmup interval, based on the configured curve.
+*/
+func warmupProgress(warmup mm_pubsub.RsWarmupPhase, currentSteps int64, targetSteps int64) float64 {
+	if targetSteps <= 0 || currentSteps >= targetSteps {
+		return 1
+	}
+	if currentSteps <= 0 {
+		return 0
+	}
+	x := float64(currentSteps) / float64(targetSteps)
+	switch warmup.Curve {
+	case mm_pubsub.RsWarmupCurveExponential:
+		// Traffic doubles the given number of times over the interval, with a long tail at low PCTs
+		if warmup.Doublings == nil || *warmup.Doublings <= 0 {
+			return x
+		}
+		d := float64(*warmup.Doublings)
+		return (math.Pow(2, d*x) - 1) / (math.Pow(2, d) - 1)
+	case mm_pubsub.RsWarmupCurveStep:
+		// Traffic moves by fixed increments, the first one is applied as soon as the Warmup starts
+		if warmup.Steps == nil || *warmup.Steps <= 0 {
+			return x
+		}
+		steps := float64(*warmup.Steps)
+		return math.Ceil(x*steps) / steps
+	case mm_pubsub.RsWarmupCurveCustom:
+		return customWarmupProgress(warmup.Checkpoints, currentSteps, targetSteps)
+	default:
+		// LINEAR curve, and default for configurations without a curve
+		return x
+	}
+}
+
+/*
+Interpolate linearly between the checkpoints of a custom curve, which implicitly starts at 0% progress at
+the beginning of the Warmup and ends at 100% at the end of the interval. Checkpoints are sorted by offset.
+*/
+func customWarmupProgress(checkpoints []mm_pubsub.RsWarmupCheckpoint, currentSteps int64, targetSteps int64) float64 {
+	fromOffset, fromProgress := int64(0), 0.0
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Offset >= targetSteps {
+			break
+		}
+		if currentSteps <= checkpoint.Offset {
+			return interpolateWarmupProgress(fromOffset, fromProgress, checkpoint.Offset, checkpoint.ProgressPct/100, currentSteps)
+		}
+		fromOffset, fromProgress = checkpoint.Offset, checkpoint.ProgressPct/100
+	}
+	return interpolateWarmupProgress(fromOffset, fromProgress, targetSteps, 1, currentSteps)
+}
+
+func interpolateWarmupProgress(fromOffset int64, fromProgress float64, toOffset int64, toProgress float64, currentSteps int64) float64 {
+	if toOffset <= fromOffset {
+		return toProgress
+	}
+	return fromProgress + (toProgress-fromProgress)*float64(currentSteps-fromOffset)/float64(toOffset-fromOffset)
+}
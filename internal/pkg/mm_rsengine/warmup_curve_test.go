@@ -0,0 +1,45 @@
+package mm_rsengine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+)
+
+func TestWarmupProgress(t *testing.T) {
+	checkpoints := []mm_pubsub.RsWarmupCheckpoint{
+		{Offset: 2, ProgressPct: 10},
+		{Offset: 5, ProgressPct: 80},
+	}
+	tests := []struct {
+		name         string
+		warmup       mm_pubsub.RsWarmupPhase
+		currentSteps int64
+		targetSteps  int64
+		want         float64
+	}{
+		{"not started", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveLinear}, 0, 10, 0},
+		{"interval elapsed", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveLinear}, 12, 10, 1},
+		{"no interval", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveLinear}, 3, 0, 1},
+		{"linear", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveLinear}, 3, 10, 0.3},
+		{"default curve is linear", mm_pubsub.RsWarmupPhase{}, 3, 10, 0.3},
+		{"exponential", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveExponential, Doublings: mm_utils.Int64Ptr(2)}, 5, 10, 1.0 / 3},
+		{"exponential without doublings", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveExponential}, 5, 10, 0.5},
+		{"step rounds up to the next step", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveStep, Steps: mm_utils.Int64Ptr(4)}, 3, 10, 0.5},
+		{"step at the boundary", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveStep, Steps: mm_utils.Int64Ptr(4)}, 5, 10, 0.5},
+		{"custom before the first checkpoint", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveCustom, Checkpoints: checkpoints}, 1, 10, 0.05},
+		{"custom on a checkpoint", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveCustom, Checkpoints: checkpoints}, 5, 10, 0.8},
+		{"custom between checkpoints", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveCustom, Checkpoints: checkpoints}, 3, 10, 0.1 + 0.7/3},
+		{"custom after the last checkpoint", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveCustom, Checkpoints: checkpoints}, 7, 10, 0.88},
+		{"custom ignores checkpoints after the interval", mm_pubsub.RsWarmupPhase{Curve: mm_pubsub.RsWarmupCurveCustom, Checkpoints: checkpoints}, 3, 4, 0.1 + 0.9/2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := warmupProgress(tt.warmup, tt.currentSteps, tt.targetSteps); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("unexpected progress: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
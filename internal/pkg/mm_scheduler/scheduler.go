@@ -2,8 +2,12 @@ package mm_scheduler
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/ai-model-match/backend/internal/pkg/mm_tracing"
 	"github.com/go-co-op/gocron/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -59,7 +63,7 @@ func (s Scheduler) AddJob(job ScheduledJob) error {
 	instance := *s.scheduler
 	if j, err := instance.NewJob(
 		gocron.CronJob(job.Schedule, false),
-		gocron.NewTask(job.Handler, job.Parameters),
+		gocron.NewTask(traceJob(job.Handler), job.Parameters),
 	); err != nil {
 		zap.L().Error("Failed to schedule a new Job", zap.Error(err), zap.String("service", "scheduler"))
 		return err
@@ -69,6 +73,27 @@ func (s Scheduler) AddJob(job ScheduledJob) error {
 	return nil
 }
 
+/*
+Wrap the handler of a job to trace each execution in a dedicated span.
+Handlers with a different signature are returned as they are.
+*/
+func traceJob(handler any) any {
+	fn, ok := handler.(func(ScheduledJobParameter) error)
+	if !ok {
+		return handler
+	}
+	return func(p ScheduledJobParameter) error {
+		_, span := mm_tracing.Start(context.Background(), fmt.Sprintf("scheduler.job %s", p.Title), trace.SpanKindInternal,
+			attribute.String("job.title", p.Title),
+			attribute.Int64("job.id", p.JobID),
+		)
+		defer span.End()
+		err := fn(p)
+		mm_tracing.RecordError(span, err)
+		return err
+	}
+}
+
 /*
 Given a generic DB Connection, get a specific low-level connection to DB.
 It is used for low-level locks for scheduled activities
@@ -0,0 +1,488 @@
+package mm_statistics
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/ai-model-match/backend/internal/pkg/mm_utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+ErrRolloutStartNotFound is returned when the start of the current rollout is not recorded in the Rollout Strategy
+history (e.g. never started or started before the history was tracked) and no explicit start is given
+*/
+var ErrRolloutStartNotFound = errors.New("rollout-start-not-found")
+
+/*
+Consumer groups updating the statistics from the events, whose pending events are not recomputed
+*/
+const flowStatisticsConsumerGroup = "flow-statistics"
+const flowStepStatisticsConsumerGroup = "flow-step-statistics"
+
+/*
+FlowCounters are the counters of a Flow statistics that can be recomputed from the recorded requests,
+feedback and session outcomes
+*/
+type FlowCounters struct {
+	TotRequests        int64   `json:"totRequests"`
+	TotSessionRequests int64   `json:"totSessionRequests"`
+	TotFeedback        int64   `json:"totFeedback"`
+	AvgScore           float64 `json:"avgScore"`
+	TotOneStarFeedback int64   `json:"totOneStarFeedback"`
+	TotErrors          int64   `json:"totErrors"`
+	TotAbandons        int64   `json:"totAbandons"`
+}
+
+type FlowStatisticsDiff struct {
+	FlowID     uuid.UUID    `json:"flowId"`
+	Current    FlowCounters `json:"current"`
+	Recomputed FlowCounters `json:"recomputed"`
+	Changed    bool         `json:"changed"`
+}
+
+type FlowStepStatisticsDiff struct {
+	FlowStepID            uuid.UUID `json:"flowStepId"`
+	FlowID                uuid.UUID `json:"flowId"`
+	CurrentTotRequests    int64     `json:"currentTotRequests"`
+	RecomputedTotRequests int64     `json:"recomputedTotRequests"`
+	Changed               bool      `json:"changed"`
+}
+
+/*
+BucketCounters are the counters of an hourly bucket of a Flow
+*/
+type BucketCounters struct {
+	TotRequests        int64   `json:"totRequests"`
+	TotSessionRequests int64   `json:"totSessionRequests"`
+	TotFeedback        int64   `json:"totFeedback"`
+	SumScore           float64 `json:"sumScore"`
+	SumSquaredScore    float64 `json:"sumSquaredScore"`
+}
+
+/*
+Sums of scores are compared with a tolerance, as they are added in a different order
+*/
+func (c BucketCounters) equals(other BucketCounters) bool {
+	return c.TotRequests == other.TotRequests &&
+		c.TotSessionRequests == other.TotSessionRequests &&
+		c.TotFeedback == other.TotFeedback &&
+		math.Abs(c.SumScore-other.SumScore) < 1e-6 &&
+		math.Abs(c.SumSquaredScore-other.SumSquaredScore) < 1e-6
+}
+
+type FlowBucketDiff struct {
+	FlowID      uuid.UUID      `json:"flowId"`
+	BucketStart time.Time      `json:"bucketStart"`
+	Current     BucketCounters `json:"current"`
+	Recomputed  BucketCounters `json:"recomputed"`
+	Changed     bool           `json:"changed"`
+}
+
+/*
+RecomputeResult reports, for each Flow, Flow Step and hourly bucket of the Use Case, the stored statistics and the
+recomputed ones
+*/
+type RecomputeResult struct {
+	UseCaseID   uuid.UUID                `json:"useCaseId"`
+	Since       *time.Time               `json:"since"`
+	DryRun      bool                     `json:"dryRun"`
+	Flows       []FlowStatisticsDiff     `json:"flows"`
+	FlowSteps   []FlowStepStatisticsDiff `json:"flowSteps"`
+	FlowBuckets []FlowBucketDiff         `json:"flowBuckets"`
+}
+
+type flowCountersModel struct {
+	FlowID             uuid.UUID `gorm:"column:flow_id"`
+	TotRequests        int64     `gorm:"column:tot_req"`
+	TotSessionRequests int64     `gorm:"column:tot_sess_req"`
+	TotFeedback        int64     `gorm:"column:tot_feedback"`
+	AvgScore           float64   `gorm:"column:avg_score"`
+	TotOneStarFeedback int64     `gorm:"column:tot_one_star_feedback"`
+	TotErrors          int64     `gorm:"column:tot_errors"`
+	TotAbandons        int64     `gorm:"column:tot_abandons"`
+}
+
+type flowStatisticsModel struct {
+	ID                 uuid.UUID `gorm:"column:id"`
+	FlowID             uuid.UUID `gorm:"column:flow_id"`
+	TotRequests        int64     `gorm:"column:tot_req"`
+	TotSessionRequests int64     `gorm:"column:tot_sess_req"`
+	TotFeedback        int64     `gorm:"column:tot_feedback"`
+	AvgScore           float64   `gorm:"column:avg_score"`
+	TotOneStarFeedback int64     `gorm:"column:tot_one_star_feedback"`
+	TotErrors          int64     `gorm:"column:tot_errors"`
+	TotAbandons        int64     `gorm:"column:tot_abandons"`
+}
+
+func (m flowStatisticsModel) toCounters() FlowCounters {
+	return FlowCounters{
+		TotRequests:        m.TotRequests,
+		TotSessionRequests: m.TotSessionRequests,
+		TotFeedback:        m.TotFeedback,
+		AvgScore:           m.AvgScore,
+		TotOneStarFeedback: m.TotOneStarFeedback,
+		TotErrors:          m.TotErrors,
+		TotAbandons:        m.TotAbandons,
+	}
+}
+
+type flowStepStatisticsModel struct {
+	ID          uuid.UUID `gorm:"column:id"`
+	FlowStepID  uuid.UUID `gorm:"column:flow_step_id"`
+	FlowID      uuid.UUID `gorm:"column:flow_id"`
+	TotRequests int64     `gorm:"column:tot_req"`
+}
+
+type flowStepCountersModel struct {
+	FlowStepID  uuid.UUID `gorm:"column:flow_step_id"`
+	TotRequests int64     `gorm:"column:tot_req"`
+}
+
+type flowBucketModel struct {
+	ID                 uuid.UUID `gorm:"column:id"`
+	FlowID             uuid.UUID `gorm:"column:flow_id"`
+	UseCaseID          uuid.UUID `gorm:"column:use_case_id"`
+	BucketStart        time.Time `gorm:"column:bucket_start"`
+	TotRequests        int64     `gorm:"column:tot_req"`
+	TotSessionRequests int64     `gorm:"column:tot_sess_req"`
+	TotFeedback        int64     `gorm:"column:tot_feedback"`
+	SumScore           float64   `gorm:"column:sum_score"`
+	SumSquaredScore    float64   `gorm:"column:sum_squared_score"`
+}
+
+func (m flowBucketModel) toCounters() BucketCounters {
+	return BucketCounters{
+		TotRequests:        m.TotRequests,
+		TotSessionRequests: m.TotSessionRequests,
+		TotFeedback:        m.TotFeedback,
+		SumScore:           m.SumScore,
+		SumSquaredScore:    m.SumSquaredScore,
+	}
+}
+
+type flowBucketKey struct {
+	flowID      uuid.UUID
+	bucketStart time.Time
+}
+
+/*
+IDs of the recorded requests, feedback and session outcomes whose events are not processed yet by the consumers.
+They are left out of the recomputed statistics, as the consumers will add them once they process their events.
+*/
+type pendingRecords struct {
+	flowRequests     []uuid.UUID
+	flowStepRequests []uuid.UUID
+	feedback         []uuid.UUID
+}
+
+/*
+Exclude the pending records from the query on the recorded data
+*/
+func excludePending(query *gorm.DB, column string, ids []uuid.UUID) *gorm.DB {
+	if len(ids) == 0 {
+		return query
+	}
+	return query.Where(column+" NOT IN ?", ids)
+}
+
+/*
+Recompute rebuilds the Flow and Flow Step statistics and the hourly buckets of the Use Case from the recorded picker
+requests, feedback and session outcomes, since the start of the current rollout (when statistics are reset) or since
+the given time. Statistics are updated directly, without publishing events, so no other side effect is triggered.
+The stored statistics are locked while they are recomputed, and the records whose events are still pending are left
+to their consumers, so events consumed in the meantime are not counted twice (only events persisted on DB are
+tracked). In dry-run mode nothing is locked or stored.
+*/
+func Recompute(db *gorm.DB, useCaseID uuid.UUID, since *time.Time, dryRun bool) (RecomputeResult, error) {
+	result := RecomputeResult{
+		UseCaseID:   useCaseID,
+		Since:       since,
+		DryRun:      dryRun,
+		Flows:       []FlowStatisticsDiff{},
+		FlowSteps:   []FlowStepStatisticsDiff{},
+		FlowBuckets: []FlowBucketDiff{},
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Statistics are reset when a rollout moves from INIT to WARMUP
+		if result.Since == nil {
+			if err := tx.Table("mm_rollout_strategy_history").
+				Select("MAX(created_at)").
+				Where("use_case_id = ?", useCaseID).
+				Where("from_state = ?", mm_pubsub.RolloutStateInit).
+				Where("to_state = ?", mm_pubsub.RolloutStateWarmup).
+				Scan(&result.Since).Error; err != nil {
+				return err
+			}
+		}
+		// Without the start of the rollout, statistics would be recomputed from the first record ever
+		if result.Since == nil {
+			return ErrRolloutStartNotFound
+		}
+		// Lock the stored statistics, so they are not updated while they are recomputed
+		locking := func(query *gorm.DB, table string) *gorm.DB {
+			if dryRun {
+				return query
+			}
+			if table == "" {
+				return query.Clauses(clause.Locking{Strength: "UPDATE"})
+			}
+			return query.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: table}})
+		}
+		var flowStatistics []flowStatisticsModel
+		if err := locking(tx.Table("mm_flow_statistics").Where("use_case_id = ?", useCaseID), "").
+			Order("flow_id ASC").
+			Find(&flowStatistics).Error; err != nil {
+			return err
+		}
+		var flowStepStatistics []flowStepStatisticsModel
+		if err := locking(tx.Table("mm_flow_step_statistics AS fss"), "fss").
+			Select("fss.id, fss.flow_step_id, fss.flow_id, fss.tot_req").
+			Joins("JOIN mm_flow AS f ON f.id = fss.flow_id").
+			Where("f.use_case_id = ?", useCaseID).
+			Order("fss.flow_id ASC, fss.flow_step_id ASC").
+			Find(&flowStepStatistics).Error; err != nil {
+			return err
+		}
+		// Buckets are hourly, so the ones of the hour the rollout started in are rebuilt entirely
+		bucketsSince := result.Since.Truncate(time.Hour)
+		var flowBuckets []flowBucketModel
+		if err := tx.Table("mm_flow_statistics_bucket").
+			Where("use_case_id = ?", useCaseID).
+			Where("bucket_start >= ?", bucketsSince).
+			Order("flow_id ASC, bucket_start ASC").
+			Find(&flowBuckets).Error; err != nil {
+			return err
+		}
+		// Recompute the counters from the recorded data
+		pending, err := listPendingRecords(tx, useCaseID, bucketsSince)
+		if err != nil {
+			return err
+		}
+		flowCounters, err := recomputeFlowCounters(tx, useCaseID, *result.Since, pending)
+		if err != nil {
+			return err
+		}
+		flowStepCounters, err := recomputeFlowStepCounters(tx, useCaseID, *result.Since, pending)
+		if err != nil {
+			return err
+		}
+		bucketCounters, err := recomputeBucketCounters(tx, useCaseID, bucketsSince, pending)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, stat := range flowStatistics {
+			diff := FlowStatisticsDiff{
+				FlowID:     stat.FlowID,
+				Current:    stat.toCounters(),
+				Recomputed: flowCounters[stat.FlowID],
+			}
+			diff.Changed = diff.Current != diff.Recomputed
+			result.Flows = append(result.Flows, diff)
+			if !diff.Changed || dryRun {
+				continue
+			}
+			if err := tx.Table("mm_flow_statistics").Where("id = ?", stat.ID).UpdateColumns(map[string]any{
+				"tot_req":               diff.Recomputed.TotRequests,
+				"tot_sess_req":          diff.Recomputed.TotSessionRequests,
+				"tot_feedback":          diff.Recomputed.TotFeedback,
+				"avg_score":             diff.Recomputed.AvgScore,
+				"tot_one_star_feedback": diff.Recomputed.TotOneStarFeedback,
+				"tot_errors":            diff.Recomputed.TotErrors,
+				"tot_abandons":          diff.Recomputed.TotAbandons,
+				"updated_at":            now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		for _, stat := range flowStepStatistics {
+			diff := FlowStepStatisticsDiff{
+				FlowStepID:            stat.FlowStepID,
+				FlowID:                stat.FlowID,
+				CurrentTotRequests:    stat.TotRequests,
+				RecomputedTotRequests: flowStepCounters[stat.FlowStepID],
+			}
+			diff.Changed = diff.CurrentTotRequests != diff.RecomputedTotRequests
+			result.FlowSteps = append(result.FlowSteps, diff)
+			if !diff.Changed || dryRun {
+				continue
+			}
+			if err := tx.Table("mm_flow_step_statistics").Where("id = ?", stat.ID).UpdateColumns(map[string]any{
+				"tot_req":    diff.RecomputedTotRequests,
+				"updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		// Buckets are compared only for the Flows with statistics, the ones the consumers keep updated
+		indexedFlowStatistics := map[uuid.UUID]bool{}
+		for _, stat := range flowStatistics {
+			indexedFlowStatistics[stat.FlowID] = true
+		}
+		storedBuckets := map[flowBucketKey]bool{}
+		for _, bucket := range flowBuckets {
+			key := flowBucketKey{flowID: bucket.FlowID, bucketStart: bucket.BucketStart.UTC()}
+			storedBuckets[key] = true
+			result.FlowBuckets = append(result.FlowBuckets, newFlowBucketDiff(key, bucket.toCounters(), bucketCounters[key]))
+		}
+		for key, counters := range bucketCounters {
+			if !storedBuckets[key] && indexedFlowStatistics[key.flowID] {
+				result.FlowBuckets = append(result.FlowBuckets, newFlowBucketDiff(key, BucketCounters{}, counters))
+			}
+		}
+		for _, diff := range result.FlowBuckets {
+			if !diff.Changed || dryRun {
+				continue
+			}
+			if err := tx.Table("mm_flow_statistics_bucket").Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "flow_id"}, {Name: "bucket_start"}},
+				DoUpdates: clause.AssignmentColumns([]string{"tot_req", "tot_sess_req", "tot_feedback", "sum_score", "sum_squared_score"}),
+			}).Create(&flowBucketModel{
+				ID:                 uuid.New(),
+				FlowID:             diff.FlowID,
+				UseCaseID:          useCaseID,
+				BucketStart:        diff.BucketStart,
+				TotRequests:        diff.Recomputed.TotRequests,
+				TotSessionRequests: diff.Recomputed.TotSessionRequests,
+				TotFeedback:        diff.Recomputed.TotFeedback,
+				SumScore:           diff.Recomputed.SumScore,
+				SumSquaredScore:    diff.Recomputed.SumSquaredScore,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return RecomputeResult{}, err
+	}
+	return result, nil
+}
+
+func newFlowBucketDiff(key flowBucketKey, current BucketCounters, recomputed BucketCounters) FlowBucketDiff {
+	return FlowBucketDiff{
+		FlowID:      key.flowID,
+		BucketStart: key.bucketStart,
+		Current:     current,
+		Recomputed:  recomputed,
+		Changed:     !current.equals(recomputed),
+	}
+}
+
+func listPendingRecords(tx *gorm.DB, useCaseID uuid.UUID, since time.Time) (pendingRecords, error) {
+	var pending pendingRecords
+	var err error
+	if pending.flowRequests, err = mm_pubsub.PendingEventEntityIDs(tx, mm_pubsub.TopicPickerV1, flowStatisticsConsumerGroup, useCaseID, since); err != nil {
+		return pending, err
+	}
+	if pending.flowStepRequests, err = mm_pubsub.PendingEventEntityIDs(tx, mm_pubsub.TopicPickerV1, flowStepStatisticsConsumerGroup, useCaseID, since); err != nil {
+		return pending, err
+	}
+	// Feedback and session outcomes share the same topic
+	if pending.feedback, err = mm_pubsub.PendingEventEntityIDs(tx, mm_pubsub.TopicFeedbackV1, flowStatisticsConsumerGroup, useCaseID, since); err != nil {
+		return pending, err
+	}
+	return pending, nil
+}
+
+func recomputeFlowCounters(tx *gorm.DB, useCaseID uuid.UUID, since time.Time, pending pendingRecords) (map[uuid.UUID]FlowCounters, error) {
+	var requests []flowCountersModel
+	requestQuery := tx.Table("mm_picker_request").
+		Select("flow_id, COUNT(*) AS tot_req, COUNT(*) FILTER (WHERE is_first_correlation) AS tot_sess_req").
+		Where("use_case_id = ? AND created_at >= ?", useCaseID, since)
+	// 1-star feedback are counted as done by mm_rsengine.IsOneStarScore
+	var feedback []flowCountersModel
+	feedbackQuery := tx.Table("mm_feedback").
+		Select("flow_id, COUNT(*) AS tot_feedback, AVG(score) AS avg_score, COUNT(*) FILTER (WHERE score < ?) AS tot_one_star_feedback", mm_rsengine.MinFeedbackScore+0.5).
+		Where("use_case_id = ? AND created_at >= ?", useCaseID, since)
+	var outcomes []flowCountersModel
+	outcomeQuery := tx.Table("mm_session_outcome").
+		Select("flow_id, COUNT(*) FILTER (WHERE outcome = ?) AS tot_errors, COUNT(*) FILTER (WHERE outcome = ?) AS tot_abandons", mm_pubsub.SessionOutcomeError, mm_pubsub.SessionOutcomeAbandoned).
+		Where("use_case_id = ? AND created_at >= ?", useCaseID, since)
+	if err := excludePending(requestQuery, "id", pending.flowRequests).Group("flow_id").Scan(&requests).Error; err != nil {
+		return nil, err
+	}
+	if err := excludePending(feedbackQuery, "id", pending.feedback).Group("flow_id").Scan(&feedback).Error; err != nil {
+		return nil, err
+	}
+	if err := excludePending(outcomeQuery, "id", pending.feedback).Group("flow_id").Scan(&outcomes).Error; err != nil {
+		return nil, err
+	}
+	counters := map[uuid.UUID]FlowCounters{}
+	for _, m := range requests {
+		c := counters[m.FlowID]
+		c.TotRequests = m.TotRequests
+		c.TotSessionRequests = m.TotSessionRequests
+		counters[m.FlowID] = c
+	}
+	for _, m := range feedback {
+		c := counters[m.FlowID]
+		c.TotFeedback = m.TotFeedback
+		// Average scores are stored rounded to 2 decimals
+		c.AvgScore = mm_utils.RoundTo2Decimals(m.AvgScore)
+		c.TotOneStarFeedback = m.TotOneStarFeedback
+		counters[m.FlowID] = c
+	}
+	for _, m := range outcomes {
+		c := counters[m.FlowID]
+		c.TotErrors = m.TotErrors
+		c.TotAbandons = m.TotAbandons
+		counters[m.FlowID] = c
+	}
+	return counters, nil
+}
+
+func recomputeFlowStepCounters(tx *gorm.DB, useCaseID uuid.UUID, since time.Time, pending pendingRecords) (map[uuid.UUID]int64, error) {
+	var models []flowStepCountersModel
+	query := tx.Table("mm_picker_request").
+		Select("flow_step_id, COUNT(*) AS tot_req").
+		Where("use_case_id = ? AND created_at >= ?", useCaseID, since)
+	if err := excludePending(query, "id", pending.flowStepRequests).Group("flow_step_id").Scan(&models).Error; err != nil {
+		return nil, err
+	}
+	counters := map[uuid.UUID]int64{}
+	for _, m := range models {
+		counters[m.FlowStepID] = m.TotRequests
+	}
+	return counters, nil
+}
+
+func recomputeBucketCounters(tx *gorm.DB, useCaseID uuid.UUID, since time.Time, pending pendingRecords) (map[flowBucketKey]BucketCounters, error) {
+	var requests []flowBucketModel
+	requestQuery := tx.Table("mm_picker_request").
+		Select("flow_id, date_trunc('hour', created_at) AS bucket_start, COUNT(*) AS tot_req, COUNT(*) FILTER (WHERE is_first_correlation) AS tot_sess_req").
+		Where("use_case_id = ? AND created_at >= ?", useCaseID, since)
+	var feedback []flowBucketModel
+	feedbackQuery := tx.Table("mm_feedback").
+		Select("flow_id, date_trunc('hour', created_at) AS bucket_start, COUNT(*) AS tot_feedback, SUM(score) AS sum_score, SUM(score * score) AS sum_squared_score").
+		Where("use_case_id = ? AND created_at >= ?", useCaseID, since)
+	if err := excludePending(requestQuery, "id", pending.flowRequests).Group("flow_id, bucket_start").Scan(&requests).Error; err != nil {
+		return nil, err
+	}
+	if err := excludePending(feedbackQuery, "id", pending.feedback).Group("flow_id, bucket_start").Scan(&feedback).Error; err != nil {
+		return nil, err
+	}
+	counters := map[flowBucketKey]BucketCounters{}
+	for _, m := range requests {
+		key := flowBucketKey{flowID: m.FlowID, bucketStart: m.BucketStart.UTC()}
+		c := counters[key]
+		c.TotRequests = m.TotRequests
+		c.TotSessionRequests = m.TotSessionRequests
+		counters[key] = c
+	}
+	for _, m := range feedback {
+		key := flowBucketKey{flowID: m.FlowID, bucketStart: m.BucketStart.UTC()}
+		c := counters[key]
+		c.TotFeedback = m.TotFeedback
+		c.SumScore = m.SumScore
+		c.SumSquaredScore = m.SumSquaredScore
+		counters[key] = c
+	}
+	return counters, nil
+}
@@ -0,0 +1,132 @@
+package mm_statistics
+
+import (
+	"time"
+
+	"github.com/ai-model-match/backend/internal/pkg/mm_pubsub"
+	"github.com/ai-model-match/backend/internal/pkg/mm_rsengine"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type feedbackSummaryModel struct {
+	FlowID      uuid.UUID `gorm:"column:flow_id"`
+	TotFeedback int64     `gorm:"column:tot_feedback"`
+	AvgScore    float64   `gorm:"column:avg_score"`
+	VarScore    float64   `gorm:"column:var_score"`
+}
+
+type scoreBucketModel struct {
+	FlowID          uuid.UUID `gorm:"column:flow_id"`
+	BucketStart     time.Time `gorm:"column:bucket_start"`
+	TotFeedback     int64     `gorm:"column:tot_feedback"`
+	SumScore        float64   `gorm:"column:sum_score"`
+	SumSquaredScore float64   `gorm:"column:sum_squared_score"`
+}
+
+/*
+RolloutStartedAt returns when the current rollout of the Use Case started, that is its last transition from
+the INIT state, or nil if it never started.
+*/
+func RolloutStartedAt(tx *gorm.DB, useCaseID uuid.UUID) (*time.Time, error) {
+	var startedAt *time.Time
+	if err := tx.Table("mm_rollout_strategy_history").
+		Select("MAX(created_at)").
+		Where("use_case_id = ?", useCaseID).
+		Where("from_state = ?", mm_pubsub.RolloutStateInit).
+		Scan(&startedAt).Error; err != nil {
+		return nil, err
+	}
+	return startedAt, nil
+}
+
+/*
+FeedbackSummaries summarizes the feedback scores of each active Flow of the Use Case, as used by the engine to
+rank the Flows and to check the significance of the winner: only the feedback received since the start of the
+current rollout is considered, aggregated on a sliding window or with a decayed average if configured in the
+Rollout Strategy. Active Flows without feedback are returned with empty summaries, in order of creation.
+*/
+func FeedbackSummaries(tx *gorm.DB, useCaseID uuid.UUID, config mm_pubsub.RSConfiguration, now time.Time) ([]mm_rsengine.FeedbackSummary, error) {
+	var flowIDs []uuid.UUID
+	if err := tx.Table("mm_flow").
+		Where("use_case_id = ?", useCaseID).
+		Where("active IS TRUE").
+		Order("created_at ASC").
+		Pluck("id", &flowIDs).Error; err != nil {
+		return nil, err
+	}
+	startedAt, err := RolloutStartedAt(tx, useCaseID)
+	if err != nil {
+		return nil, err
+	}
+	var summaries []mm_rsengine.FeedbackSummary
+	if config.ScoreAggregation != nil {
+		summaries, err = aggregatedFeedbackSummaries(tx, flowIDs, *config.ScoreAggregation, startedAt, now)
+	} else {
+		summaries, err = rolloutFeedbackSummaries(tx, flowIDs, startedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	indexedSummaries := map[uuid.UUID]mm_rsengine.FeedbackSummary{}
+	for _, summary := range summaries {
+		indexedSummaries[summary.FlowID] = summary
+	}
+	result := make([]mm_rsengine.FeedbackSummary, len(flowIDs))
+	for i, flowID := range flowIDs {
+		result[i] = indexedSummaries[flowID]
+		result[i].FlowID = flowID
+	}
+	return result, nil
+}
+
+/*
+Summarize all the feedback received by the Flows since the start of the rollout.
+*/
+func rolloutFeedbackSummaries(tx *gorm.DB, flowIDs []uuid.UUID, startedAt *time.Time) ([]mm_rsengine.FeedbackSummary, error) {
+	var models []feedbackSummaryModel
+	query := tx.Table("mm_feedback").
+		Select("flow_id, COUNT(*) AS tot_feedback, AVG(score) AS avg_score, COALESCE(VAR_SAMP(score), 0) AS var_score").
+		Where("flow_id IN ?", flowIDs)
+	if startedAt != nil {
+		query = query.Where("created_at >= ?", *startedAt)
+	}
+	if err := query.Group("flow_id").Scan(&models).Error; err != nil {
+		return nil, err
+	}
+	summaries := make([]mm_rsengine.FeedbackSummary, len(models))
+	for i, model := range models {
+		summaries[i] = mm_rsengine.FeedbackSummary(model)
+	}
+	return summaries, nil
+}
+
+/*
+Aggregate the hourly buckets of the Flows as configured. Buckets are hourly, so the one of the rollout start
+can include some feedback received just before it.
+*/
+func aggregatedFeedbackSummaries(tx *gorm.DB, flowIDs []uuid.UUID, aggregation mm_pubsub.RsScoreAggregation, startedAt *time.Time, now time.Time) ([]mm_rsengine.FeedbackSummary, error) {
+	since := mm_rsengine.ScoreAggregationSince(aggregation, now)
+	if startedAt != nil && startedAt.Truncate(time.Hour).After(since) {
+		since = startedAt.Truncate(time.Hour)
+	}
+	var models []scoreBucketModel
+	if err := tx.Table("mm_flow_statistics_bucket").
+		Where("flow_id IN ?", flowIDs).
+		Where("bucket_start >= ?", since).
+		Where("tot_feedback > 0").
+		Scan(&models).Error; err != nil {
+		return nil, err
+	}
+	buckets := make([]mm_rsengine.ScoreBucket, len(models))
+	for i, model := range models {
+		buckets[i] = mm_rsengine.ScoreBucket{
+			FlowID:          model.FlowID,
+			StartedAt:       model.BucketStart,
+			TotFeedback:     model.TotFeedback,
+			SumScore:        model.SumScore,
+			SumSquaredScore: model.SumSquaredScore,
+		}
+	}
+	return mm_rsengine.AggregateScores(aggregation, buckets, now), nil
+}
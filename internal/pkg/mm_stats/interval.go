@@ -0,0 +1,39 @@
+package mm_stats
+
+import (
+	"math"
+)
+
+/*
+StudentTQuantile returns the value t such that StudentTCDF(t, df) = p, found by bisection.
+*/
+func StudentTQuantile(p float64, df float64) float64 {
+	const maxIterations = 200
+	const epsilon = 1e-10
+	low, high := -1e3, 1e3
+	for range maxIterations {
+		mid := (low + high) / 2
+		if StudentTCDF(mid, df) < p {
+			low = mid
+		} else {
+			high = mid
+		}
+		if high-low < epsilon {
+			break
+		}
+	}
+	return (low + high) / 2
+}
+
+/*
+MeanConfidenceInterval returns the two-sided confidence interval of the mean of the sample at the given level
+(e.g. 0.95), based on the Student's t-distribution. Samples with less than 2 observations have no interval.
+*/
+func MeanConfidenceInterval(sample Sample, level float64) (float64, float64, bool) {
+	if sample.Count < 2 {
+		return 0, 0, false
+	}
+	df := float64(sample.Count - 1)
+	margin := StudentTQuantile(1-(1-level)/2, df) * math.Sqrt(sample.Variance/float64(sample.Count))
+	return sample.Mean - margin, sample.Mean + margin, true
+}
@@ -0,0 +1,133 @@
+package mm_stats
+
+import (
+	"math"
+)
+
+/*
+Sample summarizes a set of observations (e.g. the feedback scores of a Flow).
+*/
+type Sample struct {
+	Count    int64
+	Mean     float64
+	Variance float64
+}
+
+/*
+WelchTTest runs a one-sided Welch's two-sample t-test and returns the p-value of the hypothesis
+that the mean of sample a is greater than the mean of sample b.
+Samples with less than 2 observations are not comparable, so the returned p-value is 1.
+*/
+func WelchTTest(a Sample, b Sample) float64 {
+	if a.Count < 2 || b.Count < 2 {
+		return 1
+	}
+	varA := a.Variance / float64(a.Count)
+	varB := b.Variance / float64(b.Count)
+	standardError := math.Sqrt(varA + varB)
+	// No variance at all: the result is certain
+	if standardError == 0 {
+		if a.Mean > b.Mean {
+			return 0
+		}
+		return 1
+	}
+	t := (a.Mean - b.Mean) / standardError
+	// Welch–Satterthwaite degrees of freedom
+	df := math.Pow(varA+varB, 2) / (math.Pow(varA, 2)/float64(a.Count-1) + math.Pow(varB, 2)/float64(b.Count-1))
+	return 1 - StudentTCDF(t, df)
+}
+
+/*
+Confidence returns how confident we are that the best sample is better than all the other ones,
+as 1 minus the highest p-value of the pairwise tests. Without other samples the confidence is 1.
+*/
+func Confidence(best Sample, others []Sample) float64 {
+	maxPValue := 0.0
+	for _, other := range others {
+		maxPValue = math.Max(maxPValue, WelchTTest(best, other))
+	}
+	return 1 - maxPValue
+}
+
+/*
+StudentTCDF returns the cumulative distribution function of the Student's t-distribution
+with df degrees of freedom.
+*/
+func StudentTCDF(t float64, df float64) float64 {
+	x := df / (df + t*t)
+	tail := 0.5 * regularizedIncompleteBeta(x, df/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+/*
+Regularized incomplete beta function I_x(a, b), evaluated with its continued fraction representation.
+*/
+func regularizedIncompleteBeta(x float64, a float64, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgammaAB, _ := math.Lgamma(a + b)
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly only for x < (a+1)/(a+b+2), otherwise use the symmetry
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+/*
+Evaluate the continued fraction of the incomplete beta function with the modified Lentz's method.
+*/
+func betaContinuedFraction(x float64, a float64, b float64) float64 {
+	const maxIterations = 300
+	const epsilon = 1e-14
+	const tiny = 1e-300
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	result := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		// Even step
+		numerator := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		result *= d * c
+		// Odd step
+		numerator = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		result *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package mm_template
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+/*
+VariableType is the type of the value expected for a variable. Values are always provided as strings,
+so the type defines how they must be parsed.
+*/
+type VariableType string
+
+const (
+	VariableTypeString  VariableType = "STRING"
+	VariableTypeNumber  VariableType = "NUMBER"
+	VariableTypeBoolean VariableType = "BOOLEAN"
+)
+
+var AvailableVariableType = []interface{}{
+	VariableTypeString,
+	VariableTypeNumber,
+	VariableTypeBoolean,
+}
+
+/*
+Variable declares a placeholder that can be used in the configurations, and the constraints on its value.
+*/
+type Variable struct {
+	Name      string       `json:"name"`
+	Type      VariableType `json:"type"`
+	Required  bool         `json:"required"`
+	Default   *string      `json:"default"`
+	MaxLength *int         `json:"maxLength"`
+	Enum      []string     `json:"enum"`
+}
+
+/*
+Check that the value respects the type, the max length and the allowed values of the variable.
+*/
+func (v Variable) ValidateValue(value string) error {
+	switch v.Type {
+	case VariableTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("must be a number")
+		}
+	case VariableTypeBoolean:
+		if value != "true" && value != "false" {
+			return errors.New("must be true or false")
+		}
+	}
+	if v.MaxLength != nil && utf8.RuneCountInString(value) > *v.MaxLength {
+		return fmt.Errorf("must be no more than %d characters", *v.MaxLength)
+	}
+	if len(v.Enum) > 0 && !slices.Contains(v.Enum, value) {
+		return errors.New("must be a valid value")
+	}
+	return nil
+}
+
+/*
+Decode the variables declared by a schema. An empty schema declares no variables.
+*/
+func ParseSchema(schema json.RawMessage) ([]Variable, error) {
+	variables := []Variable{}
+	if len(schema) == 0 {
+		return variables, nil
+	}
+	if err := json.Unmarshal(schema, &variables); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
+
+/*
+Return the placeholders not declared by the schema, keeping their order.
+*/
+func UndeclaredPlaceholders(schema []Variable, placeholders []string) []string {
+	undeclared := []string{}
+	for _, placeholder := range placeholders {
+		if !slices.ContainsFunc(schema, func(v Variable) bool { return v.Name == placeholder }) {
+			undeclared = append(undeclared, placeholder)
+		}
+	}
+	return undeclared
+}
+
+/*
+Validate the variables provided by a caller against the schema, and return them with the defaults
+of the variables not provided. Each violation is returned as "<name> <reason>", in order of declaration
+followed by the variables not declared by the schema.
+*/
+func ValidateVariables(schema []Variable, variables map[string]string) (map[string]string, []string) {
+	resolved := map[string]string{}
+	violations := []string{}
+	for _, variable := range schema {
+		value, ok := variables[variable.Name]
+		if !ok {
+			if variable.Required {
+				violations = append(violations, fmt.Sprintf("%s is required", variable.Name))
+			} else if variable.Default != nil {
+				resolved[variable.Name] = *variable.Default
+			}
+			continue
+		}
+		if err := variable.ValidateValue(value); err != nil {
+			violations = append(violations, fmt.Sprintf("%s %s", variable.Name, err.Error()))
+			continue
+		}
+		resolved[variable.Name] = value
+	}
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if !slices.ContainsFunc(schema, func(v Variable) bool { return v.Name == name }) {
+			violations = append(violations, fmt.Sprintf("%s is not declared", name))
+		}
+	}
+	return resolved, violations
+}
@@ -0,0 +1,142 @@
+package mm_template
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+)
+
+/*
+Placeholders are declared inside the string values of a configuration as <<name>>, or <<name|default>>
+to use a default value when the variable is not provided. A placeholder preceded by a backslash,
+e.g. \<<name>>, is escaped and rendered as it is, without the backslash.
+*/
+var placeholderRegex = regexp.MustCompile(`(\\)?<<([A-Za-z0-9_-]+)(?:\|([^<>]*))?>>`)
+
+/*
+NameRegex matches a valid placeholder name
+*/
+var NameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+/*
+Return the names of the placeholders declared in the configuration, without duplicates and
+in order of appearance (object keys are visited in alphabetical order).
+*/
+func Placeholders(configuration json.RawMessage) ([]string, error) {
+	value, err := decode(configuration)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	walk(value, func(s string) string {
+		for _, match := range placeholderRegex.FindAllStringSubmatch(s, -1) {
+			if match[1] == "" && !slices.Contains(names, match[2]) {
+				names = append(names, match[2])
+			}
+		}
+		return s
+	})
+	return names, nil
+}
+
+/*
+Check if the value contains at least a placeholder, not escaped.
+*/
+func HasPlaceholders(value string) bool {
+	for _, match := range placeholderRegex.FindAllStringSubmatch(value, -1) {
+		if match[1] == "" {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Render the placeholders of the configuration with the given variables, falling back to their default value.
+Placeholders without a variable and a default are left as they are, and returned as missing.
+The configuration is returned unchanged if there is nothing to replace, otherwise it is encoded again
+with its object keys in alphabetical order.
+*/
+func Render(configuration json.RawMessage, variables map[string]string) (json.RawMessage, []string, error) {
+	value, err := decode(configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	missing := []string{}
+	replaced := false
+	rendered := walk(value, func(s string) string {
+		return placeholderRegex.ReplaceAllStringFunc(s, func(placeholder string) string {
+			// Indexes of the escape, name and default groups
+			match := placeholderRegex.FindStringSubmatchIndex(placeholder)
+			if match[2] != -1 {
+				replaced = true
+				return placeholder[1:]
+			}
+			name := placeholder[match[4]:match[5]]
+			if variable, ok := variables[name]; ok {
+				replaced = true
+				return variable
+			}
+			if match[6] != -1 {
+				replaced = true
+				return placeholder[match[6]:match[7]]
+			}
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return placeholder
+		})
+	})
+	if !replaced {
+		return configuration, missing, nil
+	}
+	// Keep characters as <, > and & as they are in the rendered values
+	var output bytes.Buffer
+	encoder := json.NewEncoder(&output)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(rendered); err != nil {
+		return nil, nil, err
+	}
+	return bytes.TrimSuffix(output.Bytes(), []byte("\n")), missing, nil
+}
+
+/*
+Decode the configuration keeping numbers as they are, to not lose precision when encoded again.
+*/
+func decode(configuration json.RawMessage) (any, error) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(configuration))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+/*
+Visit all the string values of a decoded JSON, replacing them with the result of the given function.
+*/
+func walk(value any, fn func(s string) string) any {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case []any:
+		for i := range v {
+			v[i] = walk(v[i], fn)
+		}
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			v[key] = walk(v[key], fn)
+		}
+		return v
+	default:
+		return v
+	}
+}
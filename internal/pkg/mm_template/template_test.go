@@ -0,0 +1,163 @@
+package mm_template
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration string
+		variables     map[string]string
+		want          string
+		wantMissing   []string
+	}{
+		{
+			name:          "variables replace their placeholders",
+			configuration: `{"prompt": "Hello <<name>>, you are <<age>>", "max": 10}`,
+			variables:     map[string]string{"name": "Ada", "age": "36"},
+			want:          `{"max":10,"prompt":"Hello Ada, you are 36"}`,
+			wantMissing:   []string{},
+		},
+		{
+			name:          "defaults are used for variables not provided",
+			configuration: `{"prompt": "Hello <<name|guest>>"}`,
+			variables:     map[string]string{},
+			want:          `{"prompt":"Hello guest"}`,
+			wantMissing:   []string{},
+		},
+		{
+			name:          "escaped placeholders are rendered without the backslash",
+			configuration: `{"prompt": "Use \\<<name>> for <<name>>"}`,
+			variables:     map[string]string{"name": "Ada"},
+			want:          `{"prompt":"Use <<name>> for Ada"}`,
+			wantMissing:   []string{},
+		},
+		{
+			name:          "placeholders in nested values",
+			configuration: `{"messages": [{"content": "<<a>>"}, {"content": "<<b>>"}]}`,
+			variables:     map[string]string{"a": "x", "b": "y"},
+			want:          `{"messages":[{"content":"x"},{"content":"y"}]}`,
+			wantMissing:   []string{},
+		},
+		{
+			name:          "HTML characters are not escaped",
+			configuration: `{"prompt": "<<tag>> & more"}`,
+			variables:     map[string]string{"tag": "<b>"},
+			want:          `{"prompt":"<b> & more"}`,
+			wantMissing:   []string{},
+		},
+		{
+			name:          "missing variables are left as they are",
+			configuration: `{"prompt": "<<a>> <<b>>", "other": "<<a>>"}`,
+			variables:     map[string]string{"b": "y"},
+			want:          `{"other":"<<a>>","prompt":"<<a>> y"}`,
+			wantMissing:   []string{"a"},
+		},
+		{
+			name:          "configuration without placeholders is unchanged",
+			configuration: `{"z": 1.50, "a": "<b> & c"}`,
+			variables:     map[string]string{"name": "Ada"},
+			want:          `{"z": 1.50, "a": "<b> & c"}`,
+			wantMissing:   []string{},
+		},
+		{
+			name:          "configuration with missing variables only is unchanged",
+			configuration: `{"z": "<<a>>", "a": 1}`,
+			variables:     map[string]string{},
+			want:          `{"z": "<<a>>", "a": 1}`,
+			wantMissing:   []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, missing, err := Render(json.RawMessage(tt.configuration), tt.variables)
+			if err != nil {
+				t.Fatalf("failed to render the configuration: %v", err)
+			}
+			if string(rendered) != tt.want {
+				t.Fatalf("unexpected configuration: got %s, want %s", rendered, tt.want)
+			}
+			if !slices.Equal(missing, tt.wantMissing) {
+				t.Fatalf("unexpected missing variables: got %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestRenderInvalidConfiguration(t *testing.T) {
+	if _, _, err := Render(json.RawMessage(`{"prompt": `), map[string]string{}); err == nil {
+		t.Fatalf("expected an error for invalid JSON")
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	maxLength := 5
+	defaultLanguage := "en"
+	schema := []Variable{
+		{Name: "name", Type: VariableTypeString, Required: true, MaxLength: &maxLength},
+		{Name: "age", Type: VariableTypeNumber},
+		{Name: "premium", Type: VariableTypeBoolean},
+		{Name: "language", Type: VariableTypeString, Default: &defaultLanguage, Enum: []string{"en", "it"}},
+	}
+	tests := []struct {
+		name           string
+		variables      map[string]string
+		wantResolved   map[string]string
+		wantViolations []string
+	}{
+		{
+			name:           "valid variables",
+			variables:      map[string]string{"name": "Ada", "age": "36", "premium": "true", "language": "it"},
+			wantResolved:   map[string]string{"name": "Ada", "age": "36", "premium": "true", "language": "it"},
+			wantViolations: []string{},
+		},
+		{
+			name:           "defaults of the variables not provided",
+			variables:      map[string]string{"name": "Ada"},
+			wantResolved:   map[string]string{"name": "Ada", "language": "en"},
+			wantViolations: []string{},
+		},
+		{
+			name:           "required variable not provided",
+			variables:      map[string]string{},
+			wantResolved:   map[string]string{"language": "en"},
+			wantViolations: []string{"name is required"},
+		},
+		{
+			name:         "invalid values in order of declaration",
+			variables:    map[string]string{"name": "Augusta", "age": "old", "premium": "yes", "language": "fr"},
+			wantResolved: map[string]string{},
+			wantViolations: []string{
+				"name must be no more than 5 characters",
+				"age must be a number",
+				"premium must be true or false",
+				"language must be a valid value",
+			},
+		},
+		{
+			name:           "variables not declared",
+			variables:      map[string]string{"name": "Ada", "zip": "1", "city": "Rome"},
+			wantResolved:   map[string]string{"name": "Ada", "language": "en"},
+			wantViolations: []string{"city is not declared", "zip is not declared"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, violations := ValidateVariables(schema, tt.variables)
+			if !slices.Equal(violations, tt.wantViolations) {
+				t.Fatalf("unexpected violations: got %q, want %q", violations, tt.wantViolations)
+			}
+			if len(resolved) != len(tt.wantResolved) {
+				t.Fatalf("unexpected resolved variables: got %v, want %v", resolved, tt.wantResolved)
+			}
+			for name, value := range tt.wantResolved {
+				if resolved[name] != value {
+					t.Fatalf("unexpected resolved variables: got %v, want %v", resolved, tt.wantResolved)
+				}
+			}
+		})
+	}
+}
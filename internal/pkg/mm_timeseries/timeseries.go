@@ -0,0 +1,67 @@
+package mm_timeseries
+
+import (
+	"time"
+)
+
+/*
+Granularity of the points of a time series
+*/
+type Granularity string
+
+const (
+	Hour Granularity = "hour"
+	Day  Granularity = "day"
+)
+
+/*
+AvailableGranularity represents a list of available granularities. It is generally used
+inside input DTOs to validate the input parameters provided during an API call.
+*/
+var AvailableGranularity = []interface{}{Hour, Day}
+
+/*
+Max number of points returned in a time series, to keep queries and responses bounded
+(e.g. one month of hourly points or two years of daily points)
+*/
+const MaxPoints int64 = 744
+
+/*
+Duration of a point with the given granularity
+*/
+func (g Granularity) Duration() time.Duration {
+	if g == Day {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+/*
+Truncate returns the start of the point the given time belongs to. Days start at midnight UTC.
+*/
+func Truncate(t time.Time, granularity Granularity) time.Time {
+	return t.UTC().Truncate(granularity.Duration())
+}
+
+/*
+CountPoints returns the number of points of a time series between from (included) and to (excluded)
+*/
+func CountPoints(from time.Time, to time.Time, granularity Granularity) int64 {
+	if !to.After(from) {
+		return 0
+	}
+	first := Truncate(from, granularity)
+	return int64((to.Sub(first) + granularity.Duration() - 1) / granularity.Duration())
+}
+
+/*
+Points returns the start of every point of a time series between from (included) and to (excluded),
+so that empty points can be reported as well
+*/
+func Points(from time.Time, to time.Time, granularity Granularity) []time.Time {
+	points := []time.Time{}
+	for point := Truncate(from, granularity); point.Before(to); point = point.Add(granularity.Duration()) {
+		points = append(points, point)
+	}
+	return points
+}
@@ -0,0 +1,17 @@
+package mm_tracing
+
+import (
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
+	"gorm.io/gorm"
+)
+
+/*
+GormPlugin traces the queries executed by gorm as children of the span in their context.
+Query variables are not recorded, to not export the data stored in DB.
+*/
+func GormPlugin() gorm.Plugin {
+	return otelgorm.NewPlugin(
+		otelgorm.WithoutQueryVariables(),
+		otelgorm.WithoutMetrics(),
+	)
+}
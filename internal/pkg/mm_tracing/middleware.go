@@ -0,0 +1,14 @@
+package mm_tracing
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+/*
+TracingMiddleware starts a server span for each HTTP request, continuing the trace of the
+caller when the W3C traceparent header is provided. The span is stored in the context of the request.
+*/
+func TracingMiddleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName)
+}
@@ -0,0 +1,169 @@
+package mm_tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+/*
+Available exporters of the spans
+*/
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOtlp   = "otlp"
+)
+
+const (
+	scopeName         = "github.com/ai-model-match/backend"
+	traceParentHeader = "traceparent"
+)
+
+/*
+Config of the tracing, read from the env variables.
+*/
+type Config struct {
+	Exporter     string
+	OtlpEndpoint string
+	ServiceName  string
+	SampleRatio  float64
+}
+
+/*
+W3C Trace Context propagator, used to continue the traces of the callers and of the events.
+*/
+var propagator = propagation.TraceContext{}
+
+var tracerProvider *sdktrace.TracerProvider
+
+/*
+Init the OpenTelemetry tracer provider with the configured exporter. With the `none` exporter,
+tracing is disabled and the global no-op provider is kept.
+*/
+func Init(config Config) {
+	otel.SetTextMapPropagator(propagator)
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.Exporter {
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterOtlp:
+		exporter, err = otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(config.OtlpEndpoint))
+	case ExporterNone:
+		zap.L().Info("Tracing disabled", zap.String("service", "tracing"))
+		return
+	default:
+		err = fmt.Errorf("invalid tracing exporter %s", config.Exporter)
+	}
+	if err != nil {
+		zap.L().Error("Impossible to create the tracing exporter", zap.String("service", "tracing"), zap.Error(err))
+		panic(err)
+	}
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+		sdktrace.WithSampler(rootClientSpanSampler{
+			sampler: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio)),
+		}),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	zap.L().Info("Tracing enabled", zap.String("service", "tracing"), zap.String("exporter", config.Exporter))
+}
+
+/*
+Shutdown the tracing, exporting the spans not sent yet. Executes during the application shutdown
+*/
+func Shutdown() {
+	if tracerProvider == nil {
+		return
+	}
+	if err := tracerProvider.Shutdown(context.Background()); err != nil {
+		zap.L().Error("Impossible to shutdown the tracing", zap.String("service", "tracing"), zap.Error(err))
+	}
+}
+
+/*
+Start a new span, child of the span in the context if any, and return a context that contains it.
+*/
+func Start(ctx context.Context, name string, kind trace.SpanKind, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(scopeName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes...))
+}
+
+/*
+Mark the span as failed with the given error. A nil error does nothing.
+*/
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+/*
+Return a new background context with only the span of the given context. It is used to trace
+operations that must not be cancelled with the request, e.g. DB transactions.
+The Gin context is resolved to the context of its request.
+*/
+func DetachedContext(ctx context.Context) context.Context {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		if ginCtx.Request == nil {
+			return context.Background()
+		}
+		ctx = ginCtx.Request.Context()
+	}
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+/*
+Return a context with the remote span described by the W3C traceparent value, used as parent of new spans.
+An empty or invalid value returns the context as it is.
+*/
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return propagator.Extract(ctx, propagation.MapCarrier{traceParentHeader: traceParent})
+}
+
+/*
+Return the W3C traceparent value of the span in the context, to propagate the trace to other processes or events.
+It is empty when the context has no valid span.
+*/
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get(traceParentHeader)
+}
+
+/*
+rootClientSpanSampler drops the client spans without a parent, so that queries executed outside
+a traced operation (e.g. the polling of the scheduled jobs) don't create new traces.
+*/
+type rootClientSpanSampler struct {
+	sampler sdktrace.Sampler
+}
+
+func (s rootClientSpanSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if p.Kind == trace.SpanKindClient && !trace.SpanContextFromContext(p.ParentContext).IsValid() {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.Drop,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.sampler.ShouldSample(p)
+}
+
+func (s rootClientSpanSampler) Description() string {
+	return fmt.Sprintf("RootClientSpanSampler{%s}", s.sampler.Description())
+}
@@ -0,0 +1,3 @@
+ALTER TABLE "mm_event_claim" DROP CONSTRAINT IF EXISTS "fk_mm_event_claim_event";
+
+DROP TABLE IF EXISTS "mm_event_claim";
\ No newline at end of file
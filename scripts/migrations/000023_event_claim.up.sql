@@ -0,0 +1,13 @@
+CREATE TABLE "mm_event_claim" (
+    "event_id" VARCHAR(36) NOT NULL,
+    "consumer_group" VARCHAR(255) NOT NULL,
+    "claimed_by" VARCHAR(255) NOT NULL,
+    "claimed_at" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("event_id", "consumer_group")
+);
+
+ALTER TABLE "mm_event_claim"
+    ADD CONSTRAINT "fk_mm_event_claim_event"
+    FOREIGN KEY ("event_id") REFERENCES mm_event(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
\ No newline at end of file
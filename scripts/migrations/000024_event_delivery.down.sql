@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "idx_mm_event_dead_letter_created_at";
+DROP TABLE IF EXISTS "mm_event_dead_letter";
+
+DROP TABLE IF EXISTS "mm_event_checkpoint";
+
+DROP INDEX IF EXISTS "idx_mm_event_claim_consumer_group_status";
+ALTER TABLE "mm_event_claim" DROP COLUMN IF EXISTS "updated_at";
+ALTER TABLE "mm_event_claim" DROP COLUMN IF EXISTS "next_attempt_at";
+ALTER TABLE "mm_event_claim" DROP COLUMN IF EXISTS "last_error";
+ALTER TABLE "mm_event_claim" DROP COLUMN IF EXISTS "attempts";
+ALTER TABLE "mm_event_claim" DROP COLUMN IF EXISTS "status";
\ No newline at end of file
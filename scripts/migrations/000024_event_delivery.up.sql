@@ -0,0 +1,30 @@
+ALTER TABLE "mm_event_claim" ADD COLUMN "status" VARCHAR(32) NOT NULL DEFAULT 'DONE';
+ALTER TABLE "mm_event_claim" ADD COLUMN "attempts" INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE "mm_event_claim" ADD COLUMN "last_error" TEXT;
+ALTER TABLE "mm_event_claim" ADD COLUMN "next_attempt_at" TIMESTAMP;
+ALTER TABLE "mm_event_claim" ADD COLUMN "updated_at" TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Index for fast lookups of deliveries to retry
+CREATE INDEX idx_mm_event_claim_consumer_group_status ON "mm_event_claim" ("consumer_group", "status");
+
+CREATE TABLE "mm_event_checkpoint" (
+    "consumer_group" VARCHAR(255) NOT NULL,
+    "topic" VARCHAR(255) NOT NULL,
+    "checkpoint_date" TIMESTAMP NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("consumer_group", "topic")
+);
+
+CREATE TABLE "mm_event_dead_letter" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "event_id" VARCHAR(36) NOT NULL,
+    "consumer_group" VARCHAR(255) NOT NULL,
+    "topic" VARCHAR(255) NOT NULL,
+    "event_type" VARCHAR(255) NOT NULL,
+    "event_body" JSON NOT NULL,
+    "attempts" INTEGER NOT NULL,
+    "last_error" TEXT,
+    "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_mm_event_dead_letter_created_at ON "mm_event_dead_letter" ("created_at");
\ No newline at end of file
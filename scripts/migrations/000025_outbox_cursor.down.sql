@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "mm_outbox_cursor";
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE "mm_outbox_cursor" (
+    "sink" VARCHAR(255) NOT NULL,
+    "topic" VARCHAR(255) NOT NULL,
+    "last_event_id" VARCHAR(36),
+    "last_event_date" TIMESTAMP NOT NULL,
+    "attempts" INTEGER NOT NULL,
+    "last_error" TEXT,
+    "next_attempt_at" TIMESTAMP,
+    "updated_at" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("sink", "topic")
+);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "idx_mm_webhook_delivery_status_next_attempt_at";
+DROP INDEX IF EXISTS "idx_mm_webhook_delivery_webhook_id_event_id";
+ALTER TABLE "mm_webhook_delivery" DROP CONSTRAINT IF EXISTS "fk_mm_webhook_delivery_webhook";
+DROP TABLE IF EXISTS "mm_webhook_delivery";
+
+ALTER TABLE "mm_webhook" DROP CONSTRAINT IF EXISTS "fk_mm_webhook_use_case";
+DROP TABLE IF EXISTS "mm_webhook";
\ No newline at end of file
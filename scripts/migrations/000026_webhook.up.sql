@@ -0,0 +1,43 @@
+CREATE TABLE "mm_webhook" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "title" VARCHAR(255) NOT NULL,
+    "url" TEXT NOT NULL,
+    "secret" VARCHAR(255) NOT NULL,
+    "event_types" JSON NOT NULL,
+    "use_case_id" VARCHAR(36),
+    "rollout_states" JSON NOT NULL,
+    "active" BOOLEAN NOT NULL,
+    "created_at" TIMESTAMP NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL
+);
+
+ALTER TABLE "mm_webhook"
+    ADD CONSTRAINT "fk_mm_webhook_use_case"
+    FOREIGN KEY ("use_case_id") REFERENCES mm_use_case(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+CREATE TABLE "mm_webhook_delivery" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "webhook_id" VARCHAR(36) NOT NULL,
+    "event_id" VARCHAR(36) NOT NULL,
+    "event_type" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(32) NOT NULL,
+    "attempts" INTEGER NOT NULL,
+    "response_status" INTEGER,
+    "last_error" TEXT,
+    "next_attempt_at" TIMESTAMP,
+    "payload" JSON NOT NULL,
+    "created_at" TIMESTAMP NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL
+);
+
+ALTER TABLE "mm_webhook_delivery"
+    ADD CONSTRAINT "fk_mm_webhook_delivery_webhook"
+    FOREIGN KEY ("webhook_id") REFERENCES mm_webhook(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+-- Each event is delivered once per webhook
+CREATE UNIQUE INDEX idx_mm_webhook_delivery_webhook_id_event_id ON "mm_webhook_delivery" ("webhook_id", "event_id");
+CREATE INDEX idx_mm_webhook_delivery_status_next_attempt_at ON "mm_webhook_delivery" ("status", "next_attempt_at");
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "idx_mm_rollout_strategy_history_use_case_id_created_at";
+ALTER TABLE "mm_rollout_strategy_history" DROP CONSTRAINT IF EXISTS "fk_mm_rollout_strategy_history_use_case";
+ALTER TABLE "mm_rollout_strategy_history" DROP CONSTRAINT IF EXISTS "fk_mm_rollout_strategy_history_rollout_strategy";
+DROP TABLE IF EXISTS "mm_rollout_strategy_history";
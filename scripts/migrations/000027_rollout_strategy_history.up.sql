@@ -0,0 +1,25 @@
+CREATE TABLE "mm_rollout_strategy_history" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "rollout_strategy_id" VARCHAR(36) NOT NULL,
+    "use_case_id" VARCHAR(36) NOT NULL,
+    "source" VARCHAR(32) NOT NULL,
+    "from_state" mm_rollout_state NOT NULL,
+    "to_state" mm_rollout_state NOT NULL,
+    "flows" JSON NOT NULL,
+    "actor" VARCHAR(255),
+    "created_at" TIMESTAMP NOT NULL
+);
+
+ALTER TABLE "mm_rollout_strategy_history"
+    ADD CONSTRAINT "fk_mm_rollout_strategy_history_rollout_strategy"
+    FOREIGN KEY ("rollout_strategy_id") REFERENCES mm_rollout_strategy(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+ALTER TABLE "mm_rollout_strategy_history"
+    ADD CONSTRAINT "fk_mm_rollout_strategy_history_use_case"
+    FOREIGN KEY ("use_case_id") REFERENCES mm_use_case(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_mm_rollout_strategy_history_use_case_id_created_at ON "mm_rollout_strategy_history" ("use_case_id", "created_at");
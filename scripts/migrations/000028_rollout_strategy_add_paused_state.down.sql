@@ -0,0 +1,21 @@
+CREATE TYPE mm_rollout_state_old AS ENUM (
+    'INIT',
+    'WARMUP',
+    'ESCAPED',
+    'ADAPTIVE',
+    'COMPLETED',
+    'FORCED_STOP',
+    'FORCED_ESCAPED',
+    'FORCED_COMPLETED'
+);
+
+UPDATE "mm_rollout_strategy" SET rollout_state = 'FORCED_STOP' WHERE rollout_state = 'PAUSED';
+DELETE FROM "mm_rollout_strategy_history" WHERE from_state = 'PAUSED' OR to_state = 'PAUSED';
+
+ALTER TABLE "mm_rollout_strategy" ALTER COLUMN rollout_state TYPE "mm_rollout_state_old" USING rollout_state::text::"mm_rollout_state_old";
+ALTER TABLE "mm_rollout_strategy_history" ALTER COLUMN from_state TYPE "mm_rollout_state_old" USING from_state::text::"mm_rollout_state_old";
+ALTER TABLE "mm_rollout_strategy_history" ALTER COLUMN to_state TYPE "mm_rollout_state_old" USING to_state::text::"mm_rollout_state_old";
+
+DROP TYPE "mm_rollout_state";
+
+ALTER TYPE "mm_rollout_state_old" RENAME TO "mm_rollout_state";
@@ -0,0 +1,11 @@
+ALTER TABLE "mm_rollout_strategy_history" DROP COLUMN IF EXISTS "escape_trigger";
+
+ALTER TABLE "mm_flow_statistics"
+    DROP COLUMN IF EXISTS "tot_one_star_feedback",
+    DROP COLUMN IF EXISTS "tot_errors",
+    DROP COLUMN IF EXISTS "tot_abandons";
+
+DROP INDEX IF EXISTS "idx_mm_session_outcome_correlation_id";
+ALTER TABLE "mm_session_outcome" DROP CONSTRAINT IF EXISTS "fk_mm_session_outcome_use_case";
+ALTER TABLE "mm_session_outcome" DROP CONSTRAINT IF EXISTS "fk_mm_session_outcome_flow";
+DROP TABLE IF EXISTS "mm_session_outcome";
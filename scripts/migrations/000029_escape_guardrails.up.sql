@@ -0,0 +1,29 @@
+CREATE TABLE "mm_session_outcome" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "use_case_id" VARCHAR(36),
+    "flow_id" VARCHAR(36),
+    "correlation_id" VARCHAR(36),
+    "outcome" VARCHAR(32) NOT NULL,
+    "created_at" TIMESTAMP NOT NULL
+);
+
+ALTER TABLE "mm_session_outcome"
+    ADD CONSTRAINT "fk_mm_session_outcome_use_case"
+    FOREIGN KEY ("use_case_id") REFERENCES mm_use_case(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+ALTER TABLE "mm_session_outcome"
+    ADD CONSTRAINT "fk_mm_session_outcome_flow"
+    FOREIGN KEY ("flow_id") REFERENCES mm_flow(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_mm_session_outcome_correlation_id ON "mm_session_outcome" ("correlation_id");
+
+ALTER TABLE "mm_flow_statistics"
+    ADD COLUMN "tot_one_star_feedback" BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN "tot_errors" BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN "tot_abandons" BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE "mm_rollout_strategy_history" ADD COLUMN "escape_trigger" JSON;
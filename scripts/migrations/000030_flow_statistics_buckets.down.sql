@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "idx_mm_flow_statistics_bucket_use_case_id_bucket_start";
+DROP INDEX IF EXISTS "idx_mm_flow_statistics_bucket_flow_id_bucket_start";
+ALTER TABLE "mm_flow_statistics_bucket" DROP CONSTRAINT IF EXISTS "fk_mm_flow_statistics_bucket_use_case";
+ALTER TABLE "mm_flow_statistics_bucket" DROP CONSTRAINT IF EXISTS "fk_mm_flow_statistics_bucket_flow";
+DROP TABLE IF EXISTS "mm_flow_statistics_bucket";
@@ -0,0 +1,26 @@
+CREATE TABLE "mm_flow_statistics_bucket" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "flow_id" VARCHAR(36),
+    "use_case_id" VARCHAR(36),
+    "bucket_start" TIMESTAMP NOT NULL,
+    "tot_req" BIGINT NOT NULL DEFAULT 0,
+    "tot_sess_req" BIGINT NOT NULL DEFAULT 0,
+    "tot_feedback" BIGINT NOT NULL DEFAULT 0,
+    "sum_score" DOUBLE PRECISION NOT NULL DEFAULT 0,
+    "sum_squared_score" DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+ALTER TABLE "mm_flow_statistics_bucket"
+    ADD CONSTRAINT "fk_mm_flow_statistics_bucket_flow"
+    FOREIGN KEY ("flow_id") REFERENCES mm_flow(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+ALTER TABLE "mm_flow_statistics_bucket"
+    ADD CONSTRAINT "fk_mm_flow_statistics_bucket_use_case"
+    FOREIGN KEY ("use_case_id") REFERENCES mm_use_case(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX idx_mm_flow_statistics_bucket_flow_id_bucket_start ON "mm_flow_statistics_bucket" ("flow_id", "bucket_start");
+CREATE INDEX idx_mm_flow_statistics_bucket_use_case_id_bucket_start ON "mm_flow_statistics_bucket" ("use_case_id", "bucket_start");
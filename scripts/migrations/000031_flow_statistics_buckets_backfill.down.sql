@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_mm_picker_request_flow_step_id_created_at";
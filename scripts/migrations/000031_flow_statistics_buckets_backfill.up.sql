@@ -0,0 +1,31 @@
+-- Backfill the hourly buckets with the requests and feedback received before the buckets were tracked
+INSERT INTO "mm_flow_statistics_bucket" ("id", "flow_id", "use_case_id", "bucket_start", "tot_req", "tot_sess_req")
+SELECT
+    gen_random_uuid()::VARCHAR,
+    "flow_id",
+    "use_case_id",
+    DATE_TRUNC('hour', "created_at"),
+    COUNT(*),
+    COUNT(*) FILTER (WHERE "is_first_correlation")
+FROM "mm_picker_request"
+WHERE "created_at" < COALESCE((SELECT MIN("bucket_start") FROM "mm_flow_statistics_bucket"), 'infinity'::TIMESTAMP)
+GROUP BY "flow_id", "use_case_id", DATE_TRUNC('hour', "created_at");
+
+INSERT INTO "mm_flow_statistics_bucket" ("id", "flow_id", "use_case_id", "bucket_start", "tot_feedback", "sum_score", "sum_squared_score")
+SELECT
+    gen_random_uuid()::VARCHAR,
+    "flow_id",
+    "use_case_id",
+    DATE_TRUNC('hour', "created_at"),
+    COUNT(*),
+    SUM("score"),
+    SUM("score" * "score")
+FROM "mm_feedback"
+WHERE "created_at" < COALESCE((SELECT MIN("bucket_start") FROM "mm_flow_statistics_bucket" WHERE "tot_feedback" > 0), 'infinity'::TIMESTAMP)
+GROUP BY "flow_id", "use_case_id", DATE_TRUNC('hour', "created_at")
+ON CONFLICT ("flow_id", "bucket_start") DO UPDATE SET
+    "tot_feedback" = "mm_flow_statistics_bucket"."tot_feedback" + EXCLUDED."tot_feedback",
+    "sum_score" = "mm_flow_statistics_bucket"."sum_score" + EXCLUDED."sum_score",
+    "sum_squared_score" = "mm_flow_statistics_bucket"."sum_squared_score" + EXCLUDED."sum_squared_score";
+
+CREATE INDEX idx_mm_picker_request_flow_step_id_created_at ON "mm_picker_request" ("flow_step_id", "created_at");
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_mm_picker_request_use_case_id_created_at";
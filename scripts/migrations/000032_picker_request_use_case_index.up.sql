@@ -0,0 +1 @@
+CREATE INDEX idx_mm_picker_request_use_case_id_created_at ON "mm_picker_request" ("use_case_id", "created_at");
@@ -0,0 +1 @@
+ALTER TABLE "mm_use_case_step" DROP COLUMN IF EXISTS "variables";
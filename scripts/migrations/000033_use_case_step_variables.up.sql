@@ -0,0 +1 @@
+ALTER TABLE "mm_use_case_step" ADD COLUMN "variables" JSON NOT NULL DEFAULT '[]';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "idx_mm_picker_request_flow_step_id_version";
+ALTER TABLE "mm_picker_request" DROP COLUMN IF EXISTS "flow_step_version";
+
+ALTER TABLE "mm_flow_step" DROP COLUMN IF EXISTS "version";
+
+DROP INDEX IF EXISTS "idx_mm_flow_step_version_flow_step_id_version";
+ALTER TABLE "mm_flow_step_version" DROP CONSTRAINT IF EXISTS "fk_mm_flow_step_version_flow_step_id";
+DROP TABLE IF EXISTS "mm_flow_step_version";
@@ -0,0 +1,38 @@
+CREATE TABLE "mm_flow_step_version" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "flow_step_id" VARCHAR(36) NOT NULL,
+    "version" BIGINT NOT NULL,
+    "configuration" JSON NOT NULL,
+    "placeholders" JSON NOT NULL,
+    "author" VARCHAR(255) NOT NULL,
+    "note" TEXT NOT NULL DEFAULT '',
+    "restored_from" BIGINT,
+    "created_at" TIMESTAMP NOT NULL
+);
+
+ALTER TABLE "mm_flow_step_version"
+    ADD CONSTRAINT "fk_mm_flow_step_version_flow_step_id"
+    FOREIGN KEY ("flow_step_id") REFERENCES mm_flow_step(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX idx_mm_flow_step_version_flow_step_id_version ON "mm_flow_step_version" ("flow_step_id", "version");
+
+ALTER TABLE "mm_flow_step" ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+
+-- The current configuration of the existing Flow Steps becomes their first version
+INSERT INTO "mm_flow_step_version" ("id", "flow_step_id", "version", "configuration", "placeholders", "author", "note", "created_at")
+SELECT
+    gen_random_uuid()::VARCHAR,
+    fs.id,
+    1,
+    fs.configuration,
+    fs.placeholders,
+    'system',
+    'Initial version',
+    fs.updated_at
+FROM mm_flow_step fs;
+
+ALTER TABLE "mm_picker_request" ADD COLUMN "flow_step_version" BIGINT;
+
+CREATE INDEX idx_mm_picker_request_flow_step_id_version ON "mm_picker_request" ("flow_step_id", "flow_step_version");
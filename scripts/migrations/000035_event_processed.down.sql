@@ -0,0 +1,2 @@
+ALTER TABLE "mm_event_processed" DROP CONSTRAINT IF EXISTS "fk_mm_event_processed_event";
+DROP TABLE IF EXISTS "mm_event_processed";
@@ -0,0 +1,12 @@
+CREATE TABLE "mm_event_processed" (
+    "event_id" VARCHAR(36) NOT NULL,
+    "consumer_group" VARCHAR(255) NOT NULL,
+    "processed_at" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("event_id", "consumer_group")
+);
+
+ALTER TABLE "mm_event_processed"
+    ADD CONSTRAINT "fk_mm_event_processed_event"
+    FOREIGN KEY ("event_id") REFERENCES mm_event(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
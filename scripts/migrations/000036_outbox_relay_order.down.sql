@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS "idx_mm_outbox_dead_letter_created_at";
+DROP TABLE IF EXISTS "mm_outbox_dead_letter";
+
+ALTER TABLE "mm_outbox_cursor" ADD COLUMN "last_event_date" TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE "mm_outbox_cursor" SET "last_event_date" = e."event_date"
+FROM "mm_event" e
+WHERE e."id" = "mm_outbox_cursor"."last_event_id";
+ALTER TABLE "mm_outbox_cursor" ALTER COLUMN "last_event_date" DROP DEFAULT;
+ALTER TABLE "mm_outbox_cursor" DROP COLUMN IF EXISTS "last_seq";
+ALTER TABLE "mm_outbox_cursor" DROP COLUMN IF EXISTS "last_txid";
+
+DROP INDEX IF EXISTS "idx_mm_event_topic_txid_seq";
+ALTER TABLE "mm_event" DROP COLUMN IF EXISTS "seq";
+ALTER TABLE "mm_event" DROP COLUMN IF EXISTS "txid";
@@ -0,0 +1,40 @@
+-- Transaction that persisted the event: events are relayed only once all the older transactions are completed
+ALTER TABLE "mm_event" ADD COLUMN "txid" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "mm_event" ALTER COLUMN "txid" SET DEFAULT (pg_current_xact_id()::text::bigint);
+
+-- Order of insert of the events, backfilled in order of date for the existing ones
+ALTER TABLE "mm_event" ADD COLUMN "seq" BIGINT;
+CREATE SEQUENCE "mm_event_seq_seq" OWNED BY "mm_event"."seq";
+UPDATE "mm_event" SET "seq" = ordered."rn"
+FROM (SELECT "id", ROW_NUMBER() OVER (ORDER BY "event_date", "id") AS "rn" FROM "mm_event") ordered
+WHERE "mm_event"."id" = ordered."id";
+SELECT setval('mm_event_seq_seq', COALESCE((SELECT MAX("seq") FROM "mm_event"), 0) + 1, false);
+ALTER TABLE "mm_event" ALTER COLUMN "seq" SET DEFAULT nextval('mm_event_seq_seq');
+ALTER TABLE "mm_event" ALTER COLUMN "seq" SET NOT NULL;
+
+CREATE INDEX idx_mm_event_topic_txid_seq ON "mm_event" ("topic", "txid", "seq");
+
+-- Cursors move on the position of the last relayed event
+ALTER TABLE "mm_outbox_cursor" ADD COLUMN "last_txid" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "mm_outbox_cursor" ADD COLUMN "last_seq" BIGINT NOT NULL DEFAULT 0;
+UPDATE "mm_outbox_cursor" c SET "last_seq" = COALESCE((
+    SELECT MAX(e."seq") FROM "mm_event" e
+    WHERE e."topic" = c."topic"
+    AND (e."event_date" < c."last_event_date" OR (e."event_date" = c."last_event_date" AND e."id" <= c."last_event_id"))
+), 0)
+WHERE c."last_event_id" IS NOT NULL;
+ALTER TABLE "mm_outbox_cursor" DROP COLUMN "last_event_date";
+
+CREATE TABLE "mm_outbox_dead_letter" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "sink" VARCHAR(255) NOT NULL,
+    "event_id" VARCHAR(36) NOT NULL,
+    "topic" VARCHAR(255) NOT NULL,
+    "event_type" VARCHAR(255) NOT NULL,
+    "event_body" JSON NOT NULL,
+    "attempts" INTEGER NOT NULL,
+    "last_error" TEXT,
+    "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_mm_outbox_dead_letter_created_at ON "mm_outbox_dead_letter" ("created_at");